@@ -0,0 +1,138 @@
+package mockable
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AdaptiveIntervalConfig configures AdaptiveInterval. Zero fields are replaced with defaults.
+type AdaptiveIntervalConfig struct {
+	// Min is the shortest interval. Defaults to 100ms.
+	Min time.Duration
+	// Max is the longest interval. Defaults to 30s.
+	Max time.Duration
+	// Initial is the first interval. Defaults to Min.
+	Initial time.Duration
+	// Widen is the factor by which Failure widens the interval. Defaults to 2.
+	Widen float64
+	// Narrow is the factor by which Success narrows the interval. Defaults to 0.5.
+	Narrow float64
+	// Jitter randomizes each wait within [interval*(1-Jitter), interval*(1+Jitter)).
+	// It must be in [0,1]. Zero means no jitter.
+	Jitter float64
+}
+
+// AdaptiveInterval is a polling interval which widens on failures and narrows on successes,
+// applying backpressure to pollers of busy or failing dependencies.
+//
+// Jitter is drawn from the injected Rand and waits are measured by the injected Timer,
+// so with fakes the sequence of waits is fully reproducible.
+type AdaptiveInterval struct {
+	mu       sync.Mutex
+	timer    Timer
+	rand     Rand
+	config   AdaptiveIntervalConfig
+	interval time.Duration
+}
+
+// NewAdaptiveInterval returns an AdaptiveInterval configured by config.
+// timer is exclusively used by Wait.
+func NewAdaptiveInterval(timer Timer, rand Rand, config AdaptiveIntervalConfig) *AdaptiveInterval {
+	if config.Min <= 0 {
+		config.Min = 100 * time.Millisecond
+	}
+	if config.Max <= 0 {
+		config.Max = 30 * time.Second
+	}
+	if config.Max < config.Min {
+		config.Max = config.Min
+	}
+	if config.Initial <= 0 {
+		config.Initial = config.Min
+	}
+	if config.Widen <= 0 {
+		config.Widen = 2
+	}
+	if config.Narrow <= 0 {
+		config.Narrow = 0.5
+	}
+	if config.Jitter < 0 || config.Jitter > 1 {
+		panic("AdaptiveInterval: Jitter out of range [0,1]")
+	}
+	a := &AdaptiveInterval{
+		timer:  timer,
+		rand:   rand,
+		config: config,
+	}
+	a.set(config.Initial)
+	return a
+}
+
+// set sets the interval, clamped into [Min, Max]. a.mu must be held.
+func (a *AdaptiveInterval) set(d time.Duration) {
+	switch {
+	case d < a.config.Min:
+		d = a.config.Min
+	case d > a.config.Max:
+		d = a.config.Max
+	}
+	a.interval = d
+}
+
+// scale multiplies the interval by f, saturating at Max on overflow. a.mu must be held.
+func (a *AdaptiveInterval) scale(f float64) {
+	next := float64(a.interval) * f
+	if next > float64(a.config.Max) {
+		a.set(a.config.Max)
+		return
+	}
+	a.set(time.Duration(next))
+}
+
+// Interval returns the current interval without jitter.
+func (a *AdaptiveInterval) Interval() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.interval
+}
+
+// Success narrows the interval, e.g. when a poll found work to do.
+func (a *AdaptiveInterval) Success() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.scale(a.config.Narrow)
+}
+
+// Failure widens the interval, e.g. when a poll failed or found nothing.
+func (a *AdaptiveInterval) Failure() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.scale(a.config.Widen)
+}
+
+// Reset restores the interval to Initial.
+func (a *AdaptiveInterval) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.set(a.config.Initial)
+}
+
+// Next returns the current interval with jitter applied.
+func (a *AdaptiveInterval) Next() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d := a.interval
+	if a.config.Jitter > 0 {
+		factor := 1 + a.config.Jitter*(2*a.rand.Float64()-1)
+		d = time.Duration(float64(d) * factor)
+	}
+	return d
+}
+
+// Wait arms the timer with Next and waits for it to fire. It returns ctx.Err() if ctx is done first.
+//
+// Waits armed on ClockFake can be asserted by CloneResetArg.
+func (a *AdaptiveInterval) Wait(ctx context.Context) error {
+	return SleepContext(ctx, a.timer, a.Next())
+}
@@ -0,0 +1,64 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveInterval(t *testing.T) {
+	require := require.New(t)
+
+	a := mockable.NewAdaptiveInterval(
+		mockable.NewClockFake(time.Now()),
+		mockable.NewRandFake(),
+		mockable.AdaptiveIntervalConfig{Min: time.Second, Max: 10 * time.Second},
+	)
+	require.Equal(time.Second, a.Interval())
+
+	a.Success()
+	require.Equal(time.Second, a.Interval())
+
+	var widened []time.Duration
+	for i := 0; i < 5; i++ {
+		a.Failure()
+		widened = append(widened, a.Interval())
+	}
+	require.Equal(
+		[]time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second},
+		widened,
+	)
+
+	a.Success()
+	require.Equal(5*time.Second, a.Interval())
+	a.Reset()
+	require.Equal(time.Second, a.Interval())
+}
+
+func TestAdaptiveInterval_Wait(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+	a := mockable.NewAdaptiveInterval(
+		clock,
+		mockable.NewRandFake(0, 0.75, 0.5),
+		mockable.AdaptiveIntervalConfig{Min: time.Second, Initial: 4 * time.Second, Jitter: 0.5},
+	)
+
+	var waits []time.Duration
+	for i := 0; i < 3; i++ {
+		errCh := make(chan error, 1)
+		go func() { errCh <- a.Wait(context.Background()) }()
+		waits = append(waits, <-clock.ResetCh)
+		clock.Send()
+		require.NoError(<-errCh)
+	}
+	require.Equal([]time.Duration{2 * time.Second, 5 * time.Second, 4 * time.Second}, waits)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(a.Wait(ctx), context.Canceled)
+}
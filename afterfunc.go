@@ -0,0 +1,238 @@
+package mockable
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The AfterFunc is a mockable interface equivalent to the *time.Timer returned from time.AfterFunc,
+// which also reports the run state of its callback.
+//
+// Unlike the Timer, a New function for the AfterFunc arms it at creation, as time.AfterFunc does.
+type AfterFunc interface {
+	// Stop prevents the callback from being called. It returns true if it did, false if the callback
+	// has already been started or t has already been stopped.
+	Stop() bool
+	// Reset arms t again to call the callback after d. It returns true if t had been armed.
+	Reset(d time.Duration) bool
+	// Fired reports whether the callback has been started since t was armed last.
+	Fired() bool
+	// Stopped reports whether t was stopped before the callback is started since t was armed last.
+	Stopped() bool
+	// Running reports whether any callback is running.
+	Running() bool
+	// Wait blocks until the callback started by the last arming returns, and returns nil.
+	// It returns ErrStopped if t is stopped or reset before the callback is started,
+	// or ctx.Err() if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
+// afterFuncRun is the state of an arming of an AfterFunc.
+type afterFuncRun struct {
+	fired, stopped bool
+	// cancel is closed when the run is stopped, and done when the callback returns or the run is stopped.
+	cancel chan struct{}
+	done   chan struct{}
+}
+
+func newAfterFuncRun() *afterFuncRun {
+	return &afterFuncRun{cancel: make(chan struct{}), done: make(chan struct{})}
+}
+
+// stop marks r stopped unless it is fired. It returns true if it did.
+func (r *afterFuncRun) stop() bool {
+	if r.fired || r.stopped {
+		return false
+	}
+	r.stopped = true
+	close(r.cancel)
+	close(r.done)
+	return true
+}
+
+// afterFuncState implements state queries of AfterFunc.
+type afterFuncState struct {
+	mu      sync.Mutex
+	f       func()
+	run     *afterFuncRun
+	running int
+}
+
+// begin marks r fired unless it is fired or stopped. It returns true if it did. s.mu must be held.
+func (s *afterFuncState) begin(r *afterFuncRun) bool {
+	if r.fired || r.stopped {
+		return false
+	}
+	r.fired = true
+	s.running++
+	return true
+}
+
+// invoke calls f for r, which begin has marked fired.
+func (s *afterFuncState) invoke(r *afterFuncRun) {
+	defer func() {
+		s.mu.Lock()
+		s.running--
+		close(r.done)
+		s.mu.Unlock()
+	}()
+	s.f()
+}
+
+func (s *afterFuncState) Fired() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.run.fired
+}
+
+func (s *afterFuncState) Stopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.run.stopped
+}
+
+func (s *afterFuncState) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running > 0
+}
+
+func (s *afterFuncState) Wait(ctx context.Context) error {
+	s.mu.Lock()
+	r := s.run
+	s.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.done:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.stopped {
+		return ErrStopped
+	}
+	return nil
+}
+
+var _ AfterFunc = (*AfterFuncReal)(nil)
+
+// AfterFuncReal implements AfterFunc using time.AfterFunc.
+type AfterFuncReal struct {
+	afterFuncState
+	t *time.Timer
+}
+
+// NewAfterFuncReal calls f in its own goroutine after d, as time.AfterFunc does.
+func NewAfterFuncReal(d time.Duration, f func()) *AfterFuncReal {
+	a := &AfterFuncReal{afterFuncState: afterFuncState{f: f}}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.arm(d)
+	return a
+}
+
+// arm arms a with a new run. a.mu must be held.
+func (a *AfterFuncReal) arm(d time.Duration) {
+	r := newAfterFuncRun()
+	a.run = r
+	a.t = time.AfterFunc(d, func() {
+		a.mu.Lock()
+		ok := a.begin(r)
+		a.mu.Unlock()
+		if ok {
+			a.invoke(r)
+		}
+	})
+}
+
+func (a *AfterFuncReal) Stop() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.t.Stop()
+	return a.run.stop()
+}
+
+func (a *AfterFuncReal) Reset(d time.Duration) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.t.Stop()
+	active := a.run.stop()
+	a.arm(d)
+	return active
+}
+
+var _ AfterFunc = (*AfterFuncClock)(nil)
+
+// AfterFuncClock implements AfterFunc using the injected Clock,
+// so that the callback is triggered by ClockFake.Send or VirtualClock.Advance in tests.
+//
+// A goroutine waits for the Clock while AfterFuncClock is armed, and callbacks run in their own goroutines.
+type AfterFuncClock struct {
+	afterFuncState
+	clock   Clock
+	serving bool
+}
+
+// NewAfterFuncClock calls f in its own goroutine after d measured by clock.
+// clock is exclusively used by the AfterFuncClock.
+func NewAfterFuncClock(clock Clock, d time.Duration, f func()) *AfterFuncClock {
+	a := &AfterFuncClock{afterFuncState: afterFuncState{f: f}, clock: clock}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.arm(d)
+	return a
+}
+
+// arm arms a with a new run. a.mu must be held.
+func (a *AfterFuncClock) arm(d time.Duration) {
+	a.run = newAfterFuncRun()
+	a.clock.Reset(d)
+	if !a.serving {
+		a.serving = true
+		go a.serve()
+	}
+}
+
+// serve waits for the clock while a is armed.
+func (a *AfterFuncClock) serve() {
+	for {
+		a.mu.Lock()
+		r := a.run
+		if r.fired || r.stopped {
+			a.serving = false
+			a.mu.Unlock()
+			return
+		}
+		a.mu.Unlock()
+
+		select {
+		case <-r.cancel:
+			// Stopped or reset; see the new run.
+		case <-a.clock.C():
+			// The clock is stopped or reset along with runs, so the value belongs to the current run.
+			a.mu.Lock()
+			r = a.run
+			if a.begin(r) {
+				go a.invoke(r)
+			}
+			a.mu.Unlock()
+		}
+	}
+}
+
+func (a *AfterFuncClock) Stop() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clock.Stop()
+	return a.run.stop()
+}
+
+func (a *AfterFuncClock) Reset(d time.Duration) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clock.Stop()
+	active := a.run.stop()
+	a.arm(d)
+	return active
+}
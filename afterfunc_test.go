@@ -0,0 +1,90 @@
+package mockable_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAfterFuncReal(t *testing.T) {
+	require := require.New(t)
+
+	var called atomic.Int32
+	a := mockable.NewAfterFuncReal(time.Millisecond, func() { called.Add(1) })
+	require.NoError(a.Wait(context.Background()))
+	require.True(a.Fired())
+	require.False(a.Stopped())
+	require.False(a.Running())
+	require.Equal(int32(1), called.Load())
+	require.False(a.Stop())
+
+	require.False(a.Reset(time.Hour))
+	require.False(a.Fired())
+	require.True(a.Stop())
+	require.True(a.Stopped())
+	require.ErrorIs(a.Wait(context.Background()), mockable.ErrStopped)
+	require.Equal(int32(1), called.Load())
+}
+
+func TestAfterFuncClock(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	release := make(chan struct{})
+	var called atomic.Int32
+	a := mockable.NewAfterFuncClock(clock, time.Second, func() {
+		called.Add(1)
+		<-release
+	})
+	require.Equal(time.Second, <-clock.ResetCh)
+	require.False(a.Fired())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	require.ErrorIs(a.Wait(ctx), context.DeadlineExceeded)
+	cancel()
+
+	clock.Send()
+	require.Eventually(a.Running, time.Second, time.Millisecond)
+	require.True(a.Fired())
+	require.False(a.Stop())
+	close(release)
+	require.NoError(a.Wait(context.Background()))
+	require.False(a.Running())
+	require.Equal(int32(1), called.Load())
+
+	// Reset arms it again.
+	require.False(a.Reset(time.Minute))
+	require.Equal(time.Minute, <-clock.ResetCh)
+	require.False(a.Fired())
+	require.True(a.Reset(time.Minute))
+	<-clock.ResetCh
+	clock.Send()
+	require.NoError(a.Wait(context.Background()))
+	require.Equal(int32(2), called.Load())
+
+	// Stopped before the fire.
+	a.Reset(time.Minute)
+	<-clock.ResetCh
+	require.True(a.Stop())
+	require.True(a.Stopped())
+	require.ErrorIs(a.Wait(context.Background()), mockable.ErrStopped)
+	require.Equal(int32(2), called.Load())
+}
+
+func TestAfterFuncClock_virtual(t *testing.T) {
+	require := require.New(t)
+
+	v := mockable.NewVirtualClock(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	var called atomic.Int32
+	a := mockable.NewAfterFuncClock(v.NewTimer(), time.Second, func() { called.Add(1) })
+
+	v.Advance(time.Second)
+	require.NoError(a.Wait(context.Background()))
+	require.Equal(int32(1), called.Load())
+}
@@ -0,0 +1,134 @@
+package mockable
+
+import (
+	"sort"
+	"time"
+)
+
+// AlarmState is a state of Alarm.
+type AlarmState int
+
+const (
+	// AlarmPending is the state of an Alarm waiting for its time.
+	AlarmPending AlarmState = iota
+	// AlarmExecuted is the state of an Alarm whose callback has been called.
+	AlarmExecuted
+	// AlarmCancelled is the state of an Alarm cancelled before its time.
+	AlarmCancelled
+)
+
+func (s AlarmState) String() string {
+	switch s {
+	case AlarmPending:
+		return "pending"
+	case AlarmExecuted:
+		return "executed"
+	case AlarmCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// Alarm is a callback scheduled on a VirtualClock by ScheduleAt or ScheduleAfter.
+type Alarm struct {
+	v     *VirtualClock
+	id    uint64
+	at    time.Time
+	fn    func(now time.Time)
+	state AlarmState
+}
+
+// AlarmRecord is an entry of VirtualClock.AlarmHistory.
+type AlarmRecord struct {
+	ID uint64
+	// At is the time the alarm was scheduled at.
+	At    time.Time
+	State AlarmState
+	// Time is the virtual time when the alarm was executed or cancelled.
+	Time time.Time
+}
+
+// ScheduleAt schedules fn to be called when the virtual time reaches t, and returns its handle.
+//
+// Callbacks are called on the goroutine advancing the virtual time, without any lock held,
+// with the virtual time set to their scheduled times. They are called in order of their times
+// and then of scheduling, before timers sharing the same time fire, regardless of the chaos mode.
+// A callback may schedule further alarms, which are called within the same advance if they are due.
+// A callback must not advance v itself.
+//
+// If t is not after the current virtual time, fn is called on the next Advance or AdvanceTo.
+func (v *VirtualClock) ScheduleAt(t time.Time, fn func(now time.Time)) *Alarm {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lastActivity = time.Now()
+	v.lastID++
+	a := &Alarm{v: v, id: v.lastID, at: t, fn: fn}
+	i := sort.Search(len(v.alarms), func(i int) bool { return v.alarms[i].at.After(t) })
+	v.alarms = append(v.alarms, nil)
+	copy(v.alarms[i+1:], v.alarms[i:])
+	v.alarms[i] = a
+	if v.real {
+		v.realSchedule(a)
+	}
+	return a
+}
+
+// ScheduleAfter schedules fn to be called when d of virtual time has elapsed. See ScheduleAt.
+func (v *VirtualClock) ScheduleAfter(d time.Duration, fn func(now time.Time)) *Alarm {
+	return v.ScheduleAt(v.Now().Add(d), fn)
+}
+
+// AlarmHistory returns records of executed and cancelled alarms, in the order they were settled.
+func (v *VirtualClock) AlarmHistory() []AlarmRecord {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]AlarmRecord(nil), v.alarmHistory...)
+}
+
+// settleAlarm removes the alarm at index i and records it as state. v.mu must be held.
+func (v *VirtualClock) settleAlarm(i int, state AlarmState) *Alarm {
+	a := v.alarms[i]
+	v.alarms = append(v.alarms[:i], v.alarms[i+1:]...)
+	a.state = state
+	now := v.now
+	if v.real {
+		now = time.Now()
+	}
+	v.alarmHistory = append(v.alarmHistory, AlarmRecord{ID: a.id, At: a.at, State: state, Time: now})
+	return a
+}
+
+// ID returns the sequence number of a, shared with timers of the same VirtualClock.
+func (a *Alarm) ID() uint64 {
+	return a.id
+}
+
+// At returns the time a is scheduled at.
+func (a *Alarm) At() time.Time {
+	return a.at
+}
+
+// State returns the current state of a.
+func (a *Alarm) State() AlarmState {
+	a.v.mu.Lock()
+	defer a.v.mu.Unlock()
+	return a.state
+}
+
+// Cancel cancels a. It returns false if a has already been executed or cancelled.
+func (a *Alarm) Cancel() bool {
+	v := a.v
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if a.state != AlarmPending {
+		return false
+	}
+	v.lastActivity = time.Now()
+	for i, p := range v.alarms {
+		if p == a {
+			v.settleAlarm(i, AlarmCancelled)
+			break
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualClock_ScheduleAt(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+
+	type call struct {
+		name string
+		now  time.Time
+		at   time.Time
+	}
+	var calls []call
+	record := func(name string) func(now time.Time) {
+		return func(now time.Time) {
+			calls = append(calls, call{name, now, v.Now()})
+		}
+	}
+
+	timer := v.NewTimer()
+	timer.Reset(2 * time.Second)
+
+	b := v.ScheduleAt(start.Add(2*time.Second), record("b"))
+	v.ScheduleAt(start.Add(2*time.Second), record("c"))
+	a := v.ScheduleAfter(time.Second, func(now time.Time) {
+		record("a")(now)
+		// Scheduled from a callback, due within the same advance.
+		v.ScheduleAfter(500*time.Millisecond, record("a+"))
+	})
+	cancelled := v.ScheduleAfter(3*time.Second, record("x"))
+
+	require.Equal(5, v.Pending())
+	next, ok := v.Next()
+	require.True(ok)
+	require.Equal(start.Add(time.Second), next)
+
+	require.True(cancelled.Cancel())
+	require.False(cancelled.Cancel())
+	require.Equal(mockable.AlarmCancelled, cancelled.State())
+
+	require.Equal(5, v.Advance(time.Hour))
+	require.Equal(
+		[]call{
+			{"a", start.Add(time.Second), start.Add(time.Second)},
+			{"a+", start.Add(1500 * time.Millisecond), start.Add(1500 * time.Millisecond)},
+			{"b", start.Add(2 * time.Second), start.Add(2 * time.Second)},
+			{"c", start.Add(2 * time.Second), start.Add(2 * time.Second)},
+		},
+		calls,
+	)
+	require.Equal(start.Add(2*time.Second), <-timer.C())
+	require.Equal(mockable.AlarmExecuted, a.State())
+	require.False(b.Cancel())
+
+	history := v.AlarmHistory()
+	require.Len(history, 5)
+	require.Equal(
+		mockable.AlarmRecord{ID: cancelled.ID(), At: start.Add(3 * time.Second), State: mockable.AlarmCancelled, Time: start},
+		history[0],
+	)
+	require.Equal(
+		mockable.AlarmRecord{ID: a.ID(), At: start.Add(time.Second), State: mockable.AlarmExecuted, Time: start.Add(time.Second)},
+		history[1],
+	)
+	require.Equal(mockable.AlarmExecuted.String(), "executed")
+
+	// Alarms in the past are executed on the next advance.
+	var late time.Time
+	v.ScheduleAt(start, func(now time.Time) { late = v.Now() })
+	require.Equal(1, v.Advance(0))
+	require.Equal(start.Add(time.Hour), late)
+}
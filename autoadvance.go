@@ -0,0 +1,94 @@
+package mockable
+
+import "time"
+
+// QuiescenceDetector decides whether the code under test driven by a VirtualClock is idle,
+// i.e. every goroutine of interest is blocked waiting for timers, so the virtual time may advance.
+type QuiescenceDetector interface {
+	Quiescent(v *VirtualClock) bool
+}
+
+// QuiescenceFunc is an adapter to use an ordinary function as QuiescenceDetector.
+type QuiescenceFunc func(v *VirtualClock) bool
+
+// Quiescent implements QuiescenceDetector.
+func (f QuiescenceFunc) Quiescent(v *VirtualClock) bool {
+	return f(v)
+}
+
+// IdleFor returns a QuiescenceDetector considering the code idle
+// when nothing has created, armed or stopped a timer of the VirtualClock, and no timer has fired,
+// for d in the real time. It is a heuristic: code busy with something else for longer than d is misjudged.
+func IdleFor(d time.Duration) QuiescenceDetector {
+	return QuiescenceFunc(func(v *VirtualClock) bool {
+		return time.Since(v.LastActivity()) >= d
+	})
+}
+
+// ArmedAtLeast returns a QuiescenceDetector considering the code idle when at least n timers are armed.
+// This is exact for code where n goroutines each wait on their own timer.
+func ArmedAtLeast(n int) QuiescenceDetector {
+	return QuiescenceFunc(func(v *VirtualClock) bool {
+		return v.Pending() >= n
+	})
+}
+
+// AllOf returns a QuiescenceDetector considering the code idle when every detector does.
+func AllOf(detectors ...QuiescenceDetector) QuiescenceDetector {
+	return QuiescenceFunc(func(v *VirtualClock) bool {
+		for _, d := range detectors {
+			if !d.Quiescent(v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// autoAdvancePoll is the real-time interval at which the auto-advance goroutine polls the detector.
+const autoAdvancePoll = 50 * time.Microsecond
+
+// WithAutoAdvance enables the auto-advance mode.
+//
+// In the auto-advance mode, a goroutine owned by the VirtualClock advances the virtual time
+// to the next pending deadline whenever detector reports the code under test is idle,
+// so tests do not need to call Advance. Call Close to stop it.
+func WithAutoAdvance(detector QuiescenceDetector) VirtualClockOption {
+	return func(v *VirtualClock) {
+		v.autoAdvance = detector
+	}
+}
+
+// LastActivity returns the real time when a timer of v was last created, armed, stopped or fired.
+func (v *VirtualClock) LastActivity() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.lastActivity
+}
+
+func (v *VirtualClock) runAutoAdvance() {
+	defer close(v.autoDone)
+	ticker := time.NewTicker(autoAdvancePoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-v.closeCh:
+			return
+		case <-ticker.C:
+		}
+		if !v.autoAdvance.Quiescent(v) {
+			continue
+		}
+		v.autoAdvanceStep()
+	}
+}
+
+// Close stops the auto-advance goroutine, if any. Timers keep working with explicit Advance calls.
+func (v *VirtualClock) Close() {
+	v.closeOnce.Do(func() {
+		close(v.closeCh)
+		if v.autoDone != nil {
+			<-v.autoDone
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package mockable_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualClock_auto_advance(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start, mockable.WithAutoAdvance(mockable.IdleFor(time.Millisecond)))
+	defer v.Close()
+
+	var (
+		mu    sync.Mutex
+		woken = map[string][]time.Time{}
+		wg    sync.WaitGroup
+	)
+	worker := func(name string, d time.Duration, n int) {
+		defer wg.Done()
+		timer := v.NewTimer()
+		for i := 0; i < n; i++ {
+			if err := mockable.SleepContext(context.Background(), timer, d); err != nil {
+				panic(err)
+			}
+			mu.Lock()
+			woken[name] = append(woken[name], timer.Now())
+			mu.Unlock()
+		}
+	}
+	wg.Add(2)
+	go worker("minute", time.Minute, 3)
+	go worker("hour", time.Hour, 2)
+	wg.Wait()
+
+	require.Equal(
+		map[string][]time.Time{
+			"minute": {start.Add(time.Minute), start.Add(2 * time.Minute), start.Add(3 * time.Minute)},
+			"hour":   {start.Add(time.Hour), start.Add(2 * time.Hour)},
+		},
+		woken,
+	)
+	require.Equal(start.Add(2*time.Hour), v.Now())
+}
+
+func TestQuiescenceDetectors(t *testing.T) {
+	require := require.New(t)
+
+	v := mockable.NewVirtualClock(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	defer v.Close()
+
+	armed := mockable.ArmedAtLeast(2)
+	require.False(armed.Quiescent(v))
+	t1, t2 := v.NewTimer(), v.NewTimer()
+	t1.Reset(time.Second)
+	t2.Reset(time.Second)
+	require.True(armed.Quiescent(v))
+
+	idle := mockable.IdleFor(time.Hour)
+	require.False(idle.Quiescent(v))
+	require.False(mockable.AllOf(armed, idle).Quiescent(v))
+	require.True(mockable.AllOf(armed, mockable.IdleFor(0)).Quiescent(v))
+}
@@ -0,0 +1,112 @@
+package mockable
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBackoffExhausted is returned from Backoff.Sleep when MaxElapsed has passed.
+var ErrBackoffExhausted = errors.New("mockable: backoff max elapsed time exceeded")
+
+// BackoffConfig configures Backoff. Zero fields are replaced with defaults.
+type BackoffConfig struct {
+	// Initial is the first delay, capped by Max. Defaults to 100ms.
+	Initial time.Duration
+	// Max caps delays before jitter is applied. Defaults to 30s.
+	Max time.Duration
+	// Multiplier is the factor by which the delay grows every time. Defaults to 2.
+	Multiplier float64
+	// Jitter randomizes each delay within [delay*(1-Jitter), delay*(1+Jitter)).
+	// It must be in [0,1]. Zero means no jitter.
+	Jitter float64
+	// MaxElapsed, if positive, stops the backoff once MaxElapsed has passed
+	// since the Backoff was created or Reset.
+	MaxElapsed time.Duration
+}
+
+// Backoff generates exponentially growing delays with optional jitter.
+//
+// Elapsed time is measured by the injected Nower and jitter is drawn from the injected Rand,
+// so with fakes the sequence of delays is fully reproducible.
+type Backoff struct {
+	mu      sync.Mutex
+	nower   Nower
+	rand    Rand
+	config  BackoffConfig
+	current time.Duration
+	start   time.Time
+}
+
+// NewBackoff returns a Backoff configured by config.
+func NewBackoff(nower Nower, rand Rand, config BackoffConfig) *Backoff {
+	if config.Initial <= 0 {
+		config.Initial = 100 * time.Millisecond
+	}
+	if config.Max <= 0 {
+		config.Max = 30 * time.Second
+	}
+	if config.Initial > config.Max {
+		config.Initial = config.Max
+	}
+	if config.Multiplier <= 0 {
+		config.Multiplier = 2
+	}
+	if config.Jitter < 0 || config.Jitter > 1 {
+		panic("Backoff: Jitter out of range [0,1]")
+	}
+	return &Backoff{
+		nower:   nower,
+		rand:    rand,
+		config:  config,
+		current: config.Initial,
+		start:   nower.Now(),
+	}
+}
+
+// NextDelay returns the next delay.
+// ok is false if MaxElapsed has passed, in which case the caller should stop retrying.
+func (b *Backoff) NextDelay() (delay time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.config.MaxElapsed > 0 && b.nower.Now().Sub(b.start) >= b.config.MaxElapsed {
+		return 0, false
+	}
+
+	delay = b.current
+	if b.config.Jitter > 0 {
+		factor := 1 + b.config.Jitter*(2*b.rand.Float64()-1)
+		delay = time.Duration(float64(delay) * factor)
+	}
+
+	// Compared in float64, as the product may overflow time.Duration.
+	if next := float64(b.current) * b.config.Multiplier; next < float64(b.config.Max) {
+		b.current = time.Duration(next)
+	} else {
+		b.current = b.config.Max
+	}
+
+	return delay, true
+}
+
+// Reset restarts the sequence from Initial and MaxElapsed from now.
+func (b *Backoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current = b.config.Initial
+	b.start = b.nower.Now()
+}
+
+// Sleep arms timer with NextDelay and waits for it to fire.
+// It returns ErrBackoffExhausted if MaxElapsed has passed, or ctx.Err() if ctx is done first.
+//
+// Delays armed on ClockFake can be asserted by CloneResetArg.
+func (b *Backoff) Sleep(ctx context.Context, timer Timer) error {
+	d, ok := b.NextDelay()
+	if !ok {
+		return ErrBackoffExhausted
+	}
+	return SleepContext(ctx, timer, d)
+}
@@ -0,0 +1,122 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(now)
+
+	b := mockable.NewBackoff(nower, mockable.NewRandFake(), mockable.BackoffConfig{
+		Initial:    time.Second,
+		Max:        5 * time.Second,
+		Multiplier: 2,
+		MaxElapsed: time.Minute,
+	})
+
+	var delays []time.Duration
+	for i := 0; i < 5; i++ {
+		d, ok := b.NextDelay()
+		require.True(ok)
+		delays = append(delays, d)
+	}
+	require.Equal(
+		[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		delays,
+	)
+
+	nower.SetNow(now.Add(time.Minute))
+	_, ok := b.NextDelay()
+	require.False(ok)
+
+	b.Reset()
+	d, ok := b.NextDelay()
+	require.True(ok)
+	require.Equal(time.Second, d)
+}
+
+func TestBackoff_config(t *testing.T) {
+	require := require.New(t)
+
+	delays := func(config mockable.BackoffConfig, n int) []time.Duration {
+		b := mockable.NewBackoff(&mockable.NowerFake{}, mockable.NewRandFake(), config)
+		var delays []time.Duration
+		for i := 0; i < n; i++ {
+			d, ok := b.NextDelay()
+			require.True(ok)
+			delays = append(delays, d)
+		}
+		return delays
+	}
+
+	// Initial is capped by Max.
+	require.Equal(
+		[]time.Duration{5 * time.Second, 5 * time.Second},
+		delays(mockable.BackoffConfig{Initial: time.Minute, Max: 5 * time.Second}, 2),
+	)
+	// A multiplier below 1 shrinks delays rather than jumping to Max.
+	require.Equal(
+		[]time.Duration{8 * time.Second, 4 * time.Second, 2 * time.Second},
+		delays(mockable.BackoffConfig{Initial: 8 * time.Second, Max: time.Minute, Multiplier: 0.5}, 3),
+	)
+	// A product overflowing time.Duration is capped by Max.
+	require.Equal(
+		[]time.Duration{time.Hour, 1<<63 - 1},
+		delays(mockable.BackoffConfig{Initial: time.Hour, Max: 1<<63 - 1, Multiplier: 1e10}, 2),
+	)
+}
+
+func TestBackoff_jitter(t *testing.T) {
+	require := require.New(t)
+
+	b := mockable.NewBackoff(mockable.NowerReal{}, mockable.NewRandFake(0, 0.5, 0.75), mockable.BackoffConfig{
+		Initial: time.Second,
+		Jitter:  0.5,
+	})
+
+	var delays []time.Duration
+	for i := 0; i < 3; i++ {
+		d, _ := b.NextDelay()
+		delays = append(delays, d)
+	}
+	require.Equal([]time.Duration{500 * time.Millisecond, 2 * time.Second, 5 * time.Second}, delays)
+}
+
+func TestBackoff_sleep(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	b := mockable.NewBackoff(clock, mockable.NewRandFake(0.25, 0.75), mockable.BackoffConfig{
+		Initial:    time.Second,
+		Jitter:     0.2,
+		MaxElapsed: 10 * time.Second,
+	})
+
+	errCh := make(chan error)
+	for i := 0; i < 3; i++ {
+		go func() { errCh <- b.Sleep(context.Background(), clock) }()
+		<-clock.ResetCh
+		clock.Send()
+		require.NoError(<-errCh)
+	}
+	// 1s*0.9, 2s*1.1, 4s*0.9; 6.7s has elapsed on the clock.
+	require.Equal(
+		[]time.Duration{900 * time.Millisecond, 2200 * time.Millisecond, 3600 * time.Millisecond},
+		derefDurations(clock.CloneResetArg()),
+	)
+
+	go func() { errCh <- b.Sleep(context.Background(), clock) }()
+	<-clock.ResetCh
+	clock.Send()
+	require.NoError(<-errCh)
+	require.ErrorIs(b.Sleep(context.Background(), clock), mockable.ErrBackoffExhausted)
+}
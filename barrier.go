@@ -0,0 +1,84 @@
+package mockable
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// AddBarrier registers a barrier at t.
+//
+// When the virtual time reaches a barrier, by Advance, AdvanceTo or the auto-advance mode,
+// v fires timers whose deadlines are not after t, then pauses at t:
+// the virtual time does not move until Continue is called.
+// This lets tests interleave assertions at precise instants of long simulated runs.
+// Barriers earlier than the current virtual time are ignored.
+func (v *VirtualClock) AddBarrier(t time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if t.Before(v.now) {
+		return
+	}
+	i := sort.Search(len(v.barriers), func(i int) bool { return !v.barriers[i].Before(t) })
+	if i < len(v.barriers) && v.barriers[i].Equal(t) {
+		return
+	}
+	v.barriers = append(v.barriers, time.Time{})
+	copy(v.barriers[i+1:], v.barriers[i:])
+	v.barriers[i] = t
+}
+
+// nextBarrier returns the earliest barrier not after t. v.mu must be held.
+func (v *VirtualClock) nextBarrier(t time.Time) (barrier time.Time, ok bool) {
+	for len(v.barriers) > 0 && v.barriers[0].Before(v.now) {
+		v.barriers = v.barriers[1:]
+	}
+	if len(v.barriers) == 0 || v.barriers[0].After(t) {
+		return time.Time{}, false
+	}
+	return v.barriers[0], true
+}
+
+// pause pauses v at the barrier. v.mu must be held.
+func (v *VirtualClock) pause(barrier time.Time) {
+	v.barriers = v.barriers[1:]
+	v.pauseAt, v.lastBarrier = &barrier, barrier
+	close(v.paused)
+}
+
+// Paused returns the barrier v is paused at. ok is false if v is not paused.
+func (v *VirtualClock) Paused() (at time.Time, ok bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.pauseAt == nil {
+		return time.Time{}, false
+	}
+	return *v.pauseAt, true
+}
+
+// WaitBarrier blocks until v pauses at a barrier, and returns the barrier.
+// It returns immediately if v is already paused, and ctx.Err() if ctx is done before that.
+func (v *VirtualClock) WaitBarrier(ctx context.Context) (time.Time, error) {
+	v.mu.Lock()
+	paused := v.paused
+	v.mu.Unlock()
+	select {
+	case <-paused:
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		return v.lastBarrier, nil
+	case <-ctx.Done():
+		return time.Time{}, ctx.Err()
+	}
+}
+
+// Continue resumes v paused at a barrier. It is no-op if v is not paused.
+func (v *VirtualClock) Continue() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.pauseAt == nil {
+		return
+	}
+	v.pauseAt = nil
+	v.paused = make(chan struct{})
+}
@@ -0,0 +1,90 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualClock_barrier(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+
+	t1, t2 := v.NewTimer(), v.NewTimer()
+	t1.Reset(time.Second)
+	t2.Reset(3 * time.Second)
+
+	v.AddBarrier(start.Add(time.Second))
+	v.AddBarrier(start.Add(2 * time.Second))
+	v.AddBarrier(start.Add(-time.Second))
+
+	require.Equal(1, v.AdvanceTo(start.Add(time.Hour)))
+	require.Equal(start.Add(time.Second), v.Now())
+	<-t1.C()
+	at, ok := v.Paused()
+	require.True(ok)
+	require.Equal(start.Add(time.Second), at)
+
+	// Paused until Continue.
+	require.Equal(0, v.Advance(time.Hour))
+	require.Equal(start.Add(time.Second), v.Now())
+
+	v.Continue()
+	require.Equal(0, v.AdvanceTo(start.Add(time.Hour)))
+	require.Equal(start.Add(2*time.Second), v.Now())
+
+	v.Continue()
+	_, ok = v.Paused()
+	require.False(ok)
+	require.Equal(1, v.AdvanceTo(start.Add(time.Hour)))
+	require.Equal(start.Add(time.Hour), v.Now())
+	<-t2.C()
+}
+
+func TestVirtualClock_barrier_auto_advance(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start, mockable.WithAutoAdvance(mockable.ArmedAtLeast(1)))
+	defer v.Close()
+
+	v.AddBarrier(start.Add(90 * time.Second))
+	v.AddBarrier(start.Add(5 * time.Minute))
+
+	ticks := make(chan time.Time)
+	go func() {
+		timer := v.NewTimer()
+		for {
+			timer.Reset(time.Minute)
+			ticks <- <-timer.C()
+		}
+	}()
+
+	ctx := context.Background()
+	require.Equal(start.Add(time.Minute), <-ticks)
+
+	at, err := v.WaitBarrier(ctx)
+	require.NoError(err)
+	require.Equal(start.Add(90*time.Second), at)
+	require.Equal(at, v.Now())
+
+	v.Continue()
+	require.Equal(start.Add(2*time.Minute), <-ticks)
+	for i := 0; i < 2; i++ {
+		<-ticks
+	}
+	at, err = v.WaitBarrier(ctx)
+	require.NoError(err)
+	require.Equal(start.Add(5*time.Minute), at)
+
+	timeout, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	v.Continue()
+	_, err = v.WaitBarrier(timeout)
+	require.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,146 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// BatcherConfig configures Batcher. Zero fields are replaced with defaults.
+type BatcherConfig struct {
+	// MaxSize is the number of items which flushes a batch immediately. Defaults to 100.
+	MaxSize int
+	// Window is how long a batch collects items after its first item, before it is flushed. Defaults to 1s.
+	Window time.Duration
+}
+
+// Batcher collects items into batches, flushing each when it reaches MaxSize
+// or when Window has elapsed since its first item, whichever comes first.
+//
+// Batches are flushed one at a time, in order, on a goroutine owned by the Batcher.
+// The window is measured by the injected Clock, so with ClockFake each Send flushes the current batch.
+type Batcher[T any] struct {
+	mu       sync.Mutex
+	clock    Clock
+	config   BatcherConfig
+	flush    func(batch []T)
+	items    []T
+	deadline time.Time
+	pending  [][]T
+	closed   bool
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+// NewBatcher returns a Batcher calling flush with each batch.
+// clock is exclusively used by the Batcher.
+func NewBatcher[T any](clock Clock, config BatcherConfig, flush func(batch []T)) *Batcher[T] {
+	if config.MaxSize <= 0 {
+		config.MaxSize = 100
+	}
+	if config.Window <= 0 {
+		config.Window = time.Second
+	}
+	b := &Batcher[T]{
+		clock:  clock,
+		config: config,
+		flush:  flush,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+	for {
+		select {
+		case <-b.wake:
+		case <-b.clock.C():
+			b.mu.Lock()
+			if len(b.items) > 0 {
+				if rest := b.deadline.Sub(b.clock.Now()); rest > 0 {
+					// A stale fire for a batch already flushed by size.
+					b.clock.Reset(rest)
+				} else {
+					b.cutLocked()
+				}
+			}
+			b.mu.Unlock()
+		}
+
+		for {
+			b.mu.Lock()
+			if len(b.pending) == 0 {
+				closed := b.closed
+				b.mu.Unlock()
+				if closed {
+					return
+				}
+				break
+			}
+			batch := b.pending[0]
+			b.pending[0] = nil
+			b.pending = b.pending[1:]
+			b.mu.Unlock()
+			b.flush(batch)
+		}
+	}
+}
+
+// cutLocked moves the current batch to the pending batches. b.mu must be held.
+func (b *Batcher[T]) cutLocked() {
+	if len(b.items) == 0 {
+		return
+	}
+	b.pending = append(b.pending, b.items)
+	b.items = nil
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Add adds item to the current batch. It returns ErrClosed after Close.
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	b.items = append(b.items, item)
+	if len(b.items) == 1 {
+		b.deadline = b.clock.Now().Add(b.config.Window)
+		b.clock.Reset(b.config.Window)
+	}
+	if len(b.items) >= b.config.MaxSize {
+		b.clock.Stop()
+		b.cutLocked()
+	}
+	return nil
+}
+
+// Len returns the number of items in the current batch.
+func (b *Batcher[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items)
+}
+
+// Close flushes the current batch regardless of its window, and waits for every batch to be flushed.
+// After Close, Add returns ErrClosed.
+func (b *Batcher[T]) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		b.clock.Stop()
+		b.cutLocked()
+		select {
+		case b.wake <- struct{}{}:
+		default:
+		}
+	}
+	b.mu.Unlock()
+	<-b.done
+}
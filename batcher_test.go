@@ -0,0 +1,62 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcher(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+	flushed := make(chan []int, 10)
+	b := mockable.NewBatcher(clock, mockable.BatcherConfig{MaxSize: 3, Window: time.Second}, func(batch []int) {
+		flushed <- batch
+	})
+
+	// Flushed by the window.
+	require.NoError(b.Add(1))
+	require.Equal(time.Second, <-clock.ResetCh)
+	require.NoError(b.Add(2))
+	require.Equal(2, b.Len())
+	clock.Send()
+	require.Equal([]int{1, 2}, <-flushed)
+	require.Equal(0, b.Len())
+
+	// Flushed by the size, without waiting for the window.
+	for i := 3; i <= 7; i++ {
+		require.NoError(b.Add(i))
+	}
+	require.Equal([]int{3, 4, 5}, <-flushed)
+	require.Equal(2, b.Len())
+
+	// Close drains the current batch.
+	b.Close()
+	require.Equal([]int{6, 7}, <-flushed)
+	require.ErrorIs(b.Add(8), mockable.ErrClosed)
+	b.Close()
+	require.Empty(flushed)
+}
+
+func TestBatcher_window_starts_at_first_item(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	flushed := make(chan time.Time, 10)
+	b := mockable.NewBatcher(v.NewTimer(), mockable.BatcherConfig{Window: time.Minute}, func(batch []string) {
+		flushed <- v.Now()
+	})
+	defer b.Close()
+
+	v.Advance(time.Hour)
+	require.NoError(b.Add("a"))
+	v.Advance(30 * time.Second)
+	require.NoError(b.Add("b"))
+	require.Empty(flushed)
+	v.Advance(30 * time.Second)
+	require.Equal(start.Add(time.Hour+time.Minute), <-flushed)
+}
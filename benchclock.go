@@ -0,0 +1,57 @@
+package mockable
+
+import (
+	"testing"
+	"time"
+)
+
+// Performance budgets of Clock and Nower implementations, including decorators, in this package.
+// They are generous bounds for a typical amd64 machine, not promises for any hardware:
+// benchmarks by BenchmarkClock and BenchmarkNower report the ratio of the measured time to them
+// as the "budget" metric, so regressions show up as ratios growing over 1.
+const (
+	// NowBudget is the budget of a single Now call.
+	NowBudget = 250 * time.Nanosecond
+	// ResetStopBudget is the budget of a Reset followed by a Stop.
+	ResetStopBudget = 2 * time.Microsecond
+)
+
+// BenchmarkNower benchmarks Now of n.
+func BenchmarkNower(b *testing.B, n Nower) {
+	b.Helper()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = n.Now()
+	}
+	reportBudget(b, NowBudget)
+}
+
+// BenchmarkClock benchmarks c in sub-benchmarks:
+// "Now" measures Now, and "ResetStop" measures Reset with a long duration followed by Stop.
+// Use it to compare implementations on your hardware, e.g.
+//
+//	func BenchmarkMyClock(b *testing.B) { mockable.BenchmarkClock(b, NewMyClock()) }
+func BenchmarkClock(b *testing.B, c Clock) {
+	b.Helper()
+	b.Run("Now", func(b *testing.B) {
+		BenchmarkNower(b, c)
+	})
+	b.Run("ResetStop", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			c.Reset(time.Hour)
+			c.Stop()
+		}
+		reportBudget(b, ResetStopBudget)
+	})
+}
+
+func reportBudget(b *testing.B, budget time.Duration) {
+	if b.N == 0 {
+		return
+	}
+	perOp := b.Elapsed() / time.Duration(b.N)
+	b.ReportMetric(float64(perOp)/float64(budget), "budget")
+}
@@ -0,0 +1,40 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+)
+
+func BenchmarkClock(b *testing.B) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	b.Run("ClockReal", func(b *testing.B) {
+		mockable.BenchmarkClock(b, mockable.NewClockReal())
+	})
+	b.Run("ClockRealCoarse", func(b *testing.B) {
+		c := mockable.NewClockReal()
+		c.Source = mockable.TimeSourceCoarse
+		mockable.BenchmarkClock(b, c)
+	})
+	b.Run("ClockFake", func(b *testing.B) {
+		mockable.BenchmarkClock(b, mockable.NewClockFake(start))
+	})
+	b.Run("VirtualTimer", func(b *testing.B) {
+		mockable.BenchmarkClock(b, mockable.NewVirtualClock(start).NewTimer())
+	})
+}
+
+func BenchmarkNower(b *testing.B) {
+	b.Run("NowerReal", func(b *testing.B) {
+		mockable.BenchmarkNower(b, mockable.NowerReal{})
+	})
+	b.Run("NowerCached", func(b *testing.B) {
+		n := mockable.NewNowerCached(mockable.NowerReal{}, mockable.NewTickerReal(), time.Millisecond)
+		defer n.Stop()
+		mockable.BenchmarkNower(b, n)
+	})
+	b.Run("ComposeNower", func(b *testing.B) {
+		mockable.BenchmarkNower(b, mockable.ComposeNower(mockable.NowerReal{}, mockable.OffsetNower(time.Hour)))
+	})
+}
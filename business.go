@@ -0,0 +1,218 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// BusinessCalendarConfig configures BusinessCalendar. Zero fields are replaced with defaults.
+type BusinessCalendarConfig struct {
+	// Location is the location days and business hours are evaluated in. Defaults to time.Local.
+	Location *time.Location
+	// Workdays are days of week which are business days unless they are holidays.
+	// Defaults to Monday through Friday.
+	Workdays []time.Weekday
+	// Holidays are dates which are not business days. Only their dates in Location are used.
+	Holidays []time.Time
+	// Open and Close are the business hours of a business day, as wall clock offsets from midnight.
+	// If Close is not after Open, they default to 9h and 17h.
+	Open, Close time.Duration
+}
+
+// TimeWindow is a recurring window of time, such as a maintenance window.
+type TimeWindow struct {
+	// Start activates at the start of each window, e.g. a CronSchedule.
+	Start Schedule
+	// Duration is the length of each window.
+	Duration time.Duration
+}
+
+// BusinessCalendar answers business-time questions, such as whether it is within business hours now,
+// when the next business day starts or when an SLA measured in business hours expires.
+//
+// "Now" always comes from the injected Nower, so logic depending on the calendar is tested by SetNow.
+// Searches give up after 5 years, for calendars with no business day.
+type BusinessCalendar struct {
+	mu       sync.Mutex
+	nower    Nower
+	loc      *time.Location
+	workdays [7]bool
+	holidays map[businessDate]bool
+	open     time.Duration
+	close    time.Duration
+}
+
+type businessDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dateOf(t time.Time) businessDate {
+	y, m, d := t.Date()
+	return businessDate{y, m, d}
+}
+
+const businessSearchDays = 5 * 366
+
+// NewBusinessCalendar returns a BusinessCalendar configured by config.
+func NewBusinessCalendar(nower Nower, config BusinessCalendarConfig) *BusinessCalendar {
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	if config.Workdays == nil {
+		config.Workdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	if config.Close <= config.Open {
+		config.Open, config.Close = 9*time.Hour, 17*time.Hour
+	}
+	c := &BusinessCalendar{
+		nower:    nower,
+		loc:      config.Location,
+		holidays: make(map[businessDate]bool),
+		open:     config.Open,
+		close:    config.Close,
+	}
+	for _, d := range config.Workdays {
+		c.workdays[d] = true
+	}
+	for _, h := range config.Holidays {
+		c.holidays[dateOf(h.In(c.loc))] = true
+	}
+	return c
+}
+
+// AddHoliday makes the date of day in the calendar's location a holiday.
+func (c *BusinessCalendar) AddHoliday(day time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.holidays[dateOf(day.In(c.loc))] = true
+}
+
+// RemoveHoliday removes the holiday on the date of day.
+func (c *BusinessCalendar) RemoveHoliday(day time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.holidays, dateOf(day.In(c.loc)))
+}
+
+// Now returns the current time in the calendar's location.
+func (c *BusinessCalendar) Now() time.Time {
+	return c.nower.Now().In(c.loc)
+}
+
+// IsBusinessDay reports whether the date of t in the calendar's location is a business day.
+func (c *BusinessCalendar) IsBusinessDay(t time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isBusinessDay(t.In(c.loc))
+}
+
+// isBusinessDay is IsBusinessDay without locking. c.mu must be held.
+func (c *BusinessCalendar) isBusinessDay(t time.Time) bool {
+	return c.workdays[t.Weekday()] && !c.holidays[dateOf(t)]
+}
+
+// at returns the wall clock offset off from midnight of the date of t.
+func (c *BusinessCalendar) at(t time.Time, off time.Duration) time.Time {
+	y, m, d := t.Date()
+	// time.Date normalizes nanoseconds into the wall clock, which keeps offsets right across DST transitions.
+	return time.Date(y, m, d, 0, 0, 0, int(off), c.loc)
+}
+
+// IsOpen reports whether it is within business hours of a business day now.
+func (c *BusinessCalendar) IsOpen() bool {
+	now := c.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isOpen(now)
+}
+
+// isOpen reports whether t is within business hours. c.mu must be held.
+func (c *BusinessCalendar) isOpen(t time.Time) bool {
+	return c.isBusinessDay(t) && !t.Before(c.at(t, c.open)) && t.Before(c.at(t, c.close))
+}
+
+// NextBusinessDay returns the opening time of the first business day after today.
+// It returns the zero time if none is found.
+func (c *BusinessCalendar) NextBusinessDay() time.Time {
+	now := c.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nextOpen(c.at(now, 24*time.Hour))
+}
+
+// nextOpen returns the first opening time not before the date of t. c.mu must be held.
+func (c *BusinessCalendar) nextOpen(t time.Time) time.Time {
+	for i := 0; i < businessSearchDays; i++ {
+		day := c.at(t, time.Duration(i)*24*time.Hour)
+		if c.isBusinessDay(day) {
+			return c.at(day, c.open)
+		}
+	}
+	return time.Time{}
+}
+
+// BusinessDeadline returns the time when d of business hours have elapsed from now,
+// e.g. the expiry of an SLA measured in business hours.
+// It returns the zero time if business hours run out.
+func (c *BusinessCalendar) BusinessDeadline(d time.Duration) time.Time {
+	t := c.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		if !c.isOpen(t) {
+			if c.isBusinessDay(t) && t.Before(c.at(t, c.open)) {
+				t = c.at(t, c.open)
+			} else {
+				t = c.nextOpen(c.at(t, 24*time.Hour))
+				if t.IsZero() {
+					return t
+				}
+			}
+		}
+		closing := c.at(t, c.close)
+		left := closing.Sub(t)
+		if d <= left {
+			return t.Add(d)
+		}
+		d -= left
+		t = closing
+	}
+}
+
+// IsWithinWindow reports whether it is within a window of w now.
+func (c *BusinessCalendar) IsWithinWindow(w TimeWindow) bool {
+	_, _, ok := c.CurrentWindow(w)
+	return ok
+}
+
+// CurrentWindow returns the window of w containing now, in the calendar's location.
+// If windows overlap, i.e. Duration is longer than the interval of Start, the latest started one is returned.
+// ok is false if it is not within a window now.
+func (c *BusinessCalendar) CurrentWindow(w TimeWindow) (start, end time.Time, ok bool) {
+	now := c.Now()
+	// The latest start in (now-Duration, now] is the start of the current window, if any.
+	start = w.Start.Next(now.Add(-w.Duration))
+	if start.IsZero() || start.After(now) {
+		return time.Time{}, time.Time{}, false
+	}
+	for {
+		next := w.Start.Next(start)
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		start = next
+	}
+	return start, start.Add(w.Duration), true
+}
+
+// NextWindow returns the first window of w starting after now.
+// start is the zero time if w never starts again.
+func (c *BusinessCalendar) NextWindow(w TimeWindow) (start, end time.Time) {
+	start = w.Start.Next(c.Now())
+	if start.IsZero() {
+		return start, start
+	}
+	return start, start.Add(w.Duration)
+}
@@ -0,0 +1,99 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessCalendar(t *testing.T) {
+	require := require.New(t)
+
+	loc := time.FixedZone("Test", 9*60*60)
+	// Friday.
+	clock := mockable.NewClockFake(time.Date(2023, 4, 28, 10, 0, 0, 0, loc))
+	cal := mockable.NewBusinessCalendar(clock, mockable.BusinessCalendarConfig{
+		Location: loc,
+		// Monday.
+		Holidays: []time.Time{time.Date(2023, 5, 1, 0, 0, 0, 0, loc)},
+	})
+
+	require.True(cal.IsOpen())
+	require.True(cal.IsBusinessDay(clock.Now()))
+	require.False(cal.IsBusinessDay(time.Date(2023, 4, 29, 12, 0, 0, 0, loc)))
+	require.False(cal.IsBusinessDay(time.Date(2023, 5, 1, 12, 0, 0, 0, loc)))
+	require.Equal(time.Date(2023, 5, 2, 9, 0, 0, 0, loc), cal.NextBusinessDay())
+
+	cal.RemoveHoliday(time.Date(2023, 5, 1, 0, 0, 0, 0, loc))
+	require.Equal(time.Date(2023, 5, 1, 9, 0, 0, 0, loc), cal.NextBusinessDay())
+	cal.AddHoliday(time.Date(2023, 5, 1, 0, 0, 0, 0, loc))
+
+	// 7h left on Friday, then 1h on Tuesday.
+	require.Equal(time.Date(2023, 5, 2, 10, 0, 0, 0, loc), cal.BusinessDeadline(8*time.Hour))
+	require.Equal(time.Date(2023, 4, 28, 12, 0, 0, 0, loc), cal.BusinessDeadline(2*time.Hour))
+
+	clock.SetNow(time.Date(2023, 4, 28, 18, 0, 0, 0, loc))
+	require.False(cal.IsOpen())
+	require.Equal(time.Date(2023, 5, 2, 9, 30, 0, 0, loc), cal.BusinessDeadline(30*time.Minute))
+
+	clock.SetNow(time.Date(2023, 4, 28, 7, 0, 0, 0, loc))
+	require.False(cal.IsOpen())
+	require.Equal(time.Date(2023, 4, 28, 9, 30, 0, 0, loc), cal.BusinessDeadline(30*time.Minute))
+}
+
+func TestBusinessCalendar_window(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Date(2023, 4, 30, 1, 30, 0, 0, time.UTC))
+	cal := mockable.NewBusinessCalendar(clock, mockable.BusinessCalendarConfig{Location: time.UTC})
+	// Sundays 01:00 to 03:00.
+	w := mockable.TimeWindow{Start: mockable.MustParseCron("0 1 * * 0"), Duration: 2 * time.Hour}
+
+	require.True(cal.IsWithinWindow(w))
+	start, end, ok := cal.CurrentWindow(w)
+	require.True(ok)
+	require.Equal(time.Date(2023, 4, 30, 1, 0, 0, 0, time.UTC), start)
+	require.Equal(time.Date(2023, 4, 30, 3, 0, 0, 0, time.UTC), end)
+
+	clock.SetNow(time.Date(2023, 4, 30, 3, 0, 0, 0, time.UTC))
+	require.False(cal.IsWithinWindow(w))
+	start, end = cal.NextWindow(w)
+	require.Equal(time.Date(2023, 5, 7, 1, 0, 0, 0, time.UTC), start)
+	require.Equal(time.Date(2023, 5, 7, 3, 0, 0, 0, time.UTC), end)
+
+	clock.SetNow(time.Date(2023, 4, 30, 1, 0, 0, 0, time.UTC))
+	require.True(cal.IsWithinWindow(w))
+}
+
+func TestBusinessCalendar_window_overlapping(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Date(2023, 5, 1, 14, 30, 0, 0, time.UTC))
+	cal := mockable.NewBusinessCalendar(clock, mockable.BusinessCalendarConfig{Location: time.UTC})
+	// A 3 hour window starting every hour.
+	w := mockable.TimeWindow{Start: mockable.MustParseCron("0 * * * *"), Duration: 3 * time.Hour}
+
+	start, end, ok := cal.CurrentWindow(w)
+	require.True(ok)
+	require.Equal(time.Date(2023, 5, 1, 14, 0, 0, 0, time.UTC), start)
+	require.Equal(time.Date(2023, 5, 1, 17, 0, 0, 0, time.UTC), end)
+
+	clock.SetNow(time.Date(2023, 5, 1, 15, 0, 0, 0, time.UTC))
+	start, _, ok = cal.CurrentWindow(w)
+	require.True(ok)
+	require.Equal(time.Date(2023, 5, 1, 15, 0, 0, 0, time.UTC), start)
+}
+
+func TestBusinessCalendar_noBusinessDay(t *testing.T) {
+	require := require.New(t)
+
+	cal := mockable.NewBusinessCalendar(
+		mockable.NewClockFake(time.Date(2023, 4, 28, 10, 0, 0, 0, time.UTC)),
+		mockable.BusinessCalendarConfig{Location: time.UTC, Workdays: []time.Weekday{}},
+	)
+	require.False(cal.IsOpen())
+	require.True(cal.NextBusinessDay().IsZero())
+	require.True(cal.BusinessDeadline(time.Hour).IsZero())
+}
@@ -0,0 +1,275 @@
+package mockable
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned from CircuitBreaker when it rejects a call.
+var ErrCircuitOpen = errors.New("mockable: circuit breaker is open")
+
+// CircuitState is a state of CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every call through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every call until the cooldown elapses.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe calls through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig configures CircuitBreaker. Zero fields are replaced with defaults.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures which opens the circuit. Defaults to 5.
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before becoming half-open. Defaults to 30s.
+	Cooldown time.Duration
+	// HalfOpenMaxCalls is the number of probe calls let through concurrently while half-open. Defaults to 1.
+	HalfOpenMaxCalls int
+	// SuccessThreshold is the number of successful probes which closes the circuit. Defaults to 1.
+	SuccessThreshold int
+}
+
+// CircuitBreakerOption configures CircuitBreaker.
+type CircuitBreakerOption func(cb *CircuitBreaker)
+
+// WithCircuitBreakerTimer makes CircuitBreaker become half-open as soon as the cooldown elapses,
+// which is measured by timer, rather than lazily on the next call.
+// timer is exclusively used by the CircuitBreaker until Stop is called.
+func WithCircuitBreakerTimer(timer Timer) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.timer = timer
+	}
+}
+
+// WithCircuitStateChange sets a callback called on every state transition.
+// It is called without any lock held, in the goroutine causing the transition.
+func WithCircuitStateChange(fn func(from, to CircuitState)) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.onChange = fn
+	}
+}
+
+// CircuitBreaker stops calling a failing dependency for a while.
+//
+// The circuit opens after consecutive failures, rejects calls with ErrCircuitOpen during the cooldown,
+// then becomes half-open to let probe calls through. Successful probes close the circuit
+// and a failed probe opens it again.
+// The cooldown is measured by the injected Nower, and optionally by a Timer
+// (see WithCircuitBreakerTimer), so transitions are tested by moving the fake time.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	nower    Nower
+	config   CircuitBreakerConfig
+	onChange func(from, to CircuitState)
+
+	state      CircuitState
+	generation uint64
+	failures   int
+	successes  int
+	probes     int
+	openedAt   time.Time
+	pending    [][2]CircuitState
+
+	timer   Timer
+	stopped bool
+	stopCh  chan struct{}
+	done    chan struct{}
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker configured by config.
+func NewCircuitBreaker(nower Nower, config CircuitBreakerConfig, options ...CircuitBreakerOption) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	if config.HalfOpenMaxCalls <= 0 {
+		config.HalfOpenMaxCalls = 1
+	}
+	if config.SuccessThreshold <= 0 {
+		config.SuccessThreshold = 1
+	}
+	cb := &CircuitBreaker{
+		nower:  nower,
+		config: config,
+	}
+	for _, opt := range options {
+		opt(cb)
+	}
+	if cb.timer != nil {
+		cb.stopCh = make(chan struct{})
+		cb.done = make(chan struct{})
+		go cb.run()
+	}
+	return cb
+}
+
+func (cb *CircuitBreaker) run() {
+	defer close(cb.done)
+	for {
+		select {
+		case <-cb.stopCh:
+			return
+		case <-cb.timer.C():
+			cb.mu.Lock()
+			if cb.state == CircuitOpen {
+				cb.setState(CircuitHalfOpen)
+			}
+			cb.unlock()
+		}
+	}
+}
+
+// unlock unlocks cb.mu and then reports transitions made while it was held.
+func (cb *CircuitBreaker) unlock() {
+	pending := cb.pending
+	cb.pending = nil
+	cb.mu.Unlock()
+	if cb.onChange != nil {
+		for _, p := range pending {
+			cb.onChange(p[0], p[1])
+		}
+	}
+}
+
+// setState moves cb to s. cb.mu must be held.
+func (cb *CircuitBreaker) setState(s CircuitState) {
+	if cb.state == s {
+		return
+	}
+	cb.pending = append(cb.pending, [2]CircuitState{cb.state, s})
+	cb.state = s
+	cb.generation++
+	cb.failures, cb.successes, cb.probes = 0, 0, 0
+	if s == CircuitOpen {
+		cb.openedAt = cb.nower.Now()
+		if cb.timer != nil && !cb.stopped {
+			cb.timer.Reset(cb.config.Cooldown)
+		}
+	}
+}
+
+// current returns the state, moving from open to half-open if the cooldown has elapsed.
+// cb.mu must be held.
+func (cb *CircuitBreaker) current() CircuitState {
+	if cb.state == CircuitOpen && !cb.nower.Now().Before(cb.openedAt.Add(cb.config.Cooldown)) {
+		cb.setState(CircuitHalfOpen)
+	}
+	return cb.state
+}
+
+// State returns the current state.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.unlock()
+	return cb.current()
+}
+
+// Allow reports whether a call may proceed.
+// If it may, err is nil and the caller must report the outcome of the call through done.
+// Otherwise Allow returns ErrCircuitOpen.
+// Outcomes reported after the state has changed since Allow are ignored.
+func (cb *CircuitBreaker) Allow() (done func(success bool), err error) {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	switch cb.current() {
+	case CircuitOpen:
+		return nil, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.probes >= cb.config.HalfOpenMaxCalls {
+			return nil, ErrCircuitOpen
+		}
+		cb.probes++
+	}
+
+	generation := cb.generation
+	var once sync.Once
+	return func(success bool) {
+		once.Do(func() { cb.report(generation, success) })
+	}, nil
+}
+
+func (cb *CircuitBreaker) report(generation uint64, success bool) {
+	cb.mu.Lock()
+	defer cb.unlock()
+	if generation != cb.generation {
+		return
+	}
+	switch cb.state {
+	case CircuitClosed:
+		if success {
+			cb.failures = 0
+			return
+		}
+		cb.failures++
+		if cb.failures >= cb.config.FailureThreshold {
+			cb.setState(CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		if !success {
+			cb.setState(CircuitOpen)
+			return
+		}
+		cb.probes--
+		cb.successes++
+		if cb.successes >= cb.config.SuccessThreshold {
+			cb.setState(CircuitClosed)
+		}
+	}
+}
+
+// Do calls fn if the circuit allows it, and records whether fn returned a non-nil error.
+// It returns ErrCircuitOpen without calling fn if the circuit rejects the call.
+func (cb *CircuitBreaker) Do(fn func() error) error {
+	done, err := cb.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	done(err == nil)
+	return err
+}
+
+// Failures returns the number of consecutive failures while closed.
+func (cb *CircuitBreaker) Failures() int {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.current()
+	return cb.failures
+}
+
+// Stop stops the timer given by WithCircuitBreakerTimer.
+// After Stop, the circuit still becomes half-open lazily on calls.
+func (cb *CircuitBreaker) Stop() {
+	if cb.timer == nil {
+		return
+	}
+	cb.mu.Lock()
+	if cb.stopped {
+		cb.mu.Unlock()
+		return
+	}
+	cb.stopped = true
+	cb.timer.Stop()
+	close(cb.stopCh)
+	cb.mu.Unlock()
+	<-cb.done
+}
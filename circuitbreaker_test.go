@@ -0,0 +1,120 @@
+package mockable_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(now)
+
+	var transitions []string
+	cb := mockable.NewCircuitBreaker(
+		nower,
+		mockable.CircuitBreakerConfig{FailureThreshold: 3, Cooldown: 10 * time.Second, SuccessThreshold: 2},
+		mockable.WithCircuitStateChange(func(from, to mockable.CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		}),
+	)
+
+	sampleErr := errors.New("sample")
+	fail := func() error { return sampleErr }
+	succeed := func() error { return nil }
+
+	require.ErrorIs(cb.Do(fail), sampleErr)
+	require.ErrorIs(cb.Do(fail), sampleErr)
+	require.NoError(cb.Do(succeed))
+	require.Equal(0, cb.Failures())
+
+	for i := 0; i < 3; i++ {
+		require.ErrorIs(cb.Do(fail), sampleErr)
+	}
+	require.Equal(mockable.CircuitOpen, cb.State())
+
+	called := false
+	require.ErrorIs(cb.Do(func() error { called = true; return nil }), mockable.ErrCircuitOpen)
+	require.False(called)
+
+	nower.SetNow(now.Add(10*time.Second - 1))
+	require.Equal(mockable.CircuitOpen, cb.State())
+	nower.SetNow(now.Add(10 * time.Second))
+	require.Equal(mockable.CircuitHalfOpen, cb.State())
+
+	// Only one probe at a time.
+	done, err := cb.Allow()
+	require.NoError(err)
+	_, err = cb.Allow()
+	require.ErrorIs(err, mockable.ErrCircuitOpen)
+
+	// A failed probe opens the circuit again.
+	done(false)
+	require.Equal(mockable.CircuitOpen, cb.State())
+
+	nower.SetNow(now.Add(20 * time.Second))
+	require.NoError(cb.Do(succeed))
+	require.Equal(mockable.CircuitHalfOpen, cb.State())
+	require.NoError(cb.Do(succeed))
+	require.Equal(mockable.CircuitClosed, cb.State())
+
+	require.Equal(
+		[]string{
+			"closed->open",
+			"open->half-open",
+			"half-open->open",
+			"open->half-open",
+			"half-open->closed",
+		},
+		transitions,
+	)
+}
+
+func TestCircuitBreaker_stale_outcome(t *testing.T) {
+	require := require.New(t)
+
+	nower := &mockable.NowerFake{}
+	nower.SetNow(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	cb := mockable.NewCircuitBreaker(nower, mockable.CircuitBreakerConfig{FailureThreshold: 1})
+
+	slow, err := cb.Allow()
+	require.NoError(err)
+	require.Error(cb.Do(func() error { return errors.New("sample") }))
+	require.Equal(mockable.CircuitOpen, cb.State())
+
+	// The call started before opening does not affect the state.
+	slow(true)
+	require.Equal(mockable.CircuitOpen, cb.State())
+}
+
+func TestCircuitBreaker_timer(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	changed := make(chan mockable.CircuitState, 1)
+	cb := mockable.NewCircuitBreaker(
+		clock,
+		mockable.CircuitBreakerConfig{FailureThreshold: 1, Cooldown: time.Minute},
+		mockable.WithCircuitBreakerTimer(clock),
+		mockable.WithCircuitStateChange(func(from, to mockable.CircuitState) { changed <- to }),
+	)
+	defer cb.Stop()
+
+	require.Error(cb.Do(func() error { return errors.New("sample") }))
+	require.Equal(mockable.CircuitOpen, <-changed)
+	require.Equal(time.Minute, <-clock.ResetCh)
+
+	// Half-open without any call.
+	clock.Send()
+	require.Equal(mockable.CircuitHalfOpen, <-changed)
+	require.Equal(mockable.CircuitHalfOpen, cb.State())
+}
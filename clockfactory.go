@@ -0,0 +1,35 @@
+package mockable
+
+// ClockFactory creates Clocks, for components needing a Clock per operation,
+// such as per-request timeouts, where a single Clock can not serve concurrent operations.
+type ClockFactory interface {
+	NewClock() Clock
+}
+
+var (
+	_ ClockFactory = ClockFactoryReal{}
+	_ ClockFactory = ClockFactoryFunc(nil)
+	_ ClockFactory = (*VirtualClock)(nil)
+)
+
+// ClockFactoryReal creates ClockReal.
+type ClockFactoryReal struct{}
+
+// NewClock implements ClockFactory.
+func (ClockFactoryReal) NewClock() Clock {
+	return NewClockReal()
+}
+
+// ClockFactoryFunc adapts a function to ClockFactory.
+// In tests, a function handing out ClockFakes through a channel lets the test drive each of them.
+type ClockFactoryFunc func() Clock
+
+// NewClock implements ClockFactory.
+func (f ClockFactoryFunc) NewClock() Clock {
+	return f()
+}
+
+// NewClock implements ClockFactory. It returns NewTimer().
+func (v *VirtualClock) NewClock() Clock {
+	return v.NewTimer()
+}
@@ -0,0 +1,22 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockFactory(t *testing.T) {
+	require := require.New(t)
+
+	require.IsType(&mockable.ClockReal{}, mockable.ClockFactoryReal{}.NewClock())
+
+	v := mockable.NewVirtualClock(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	a, b := v.NewClock(), v.NewClock()
+	require.NotSame(a, b)
+	a.Reset(time.Second)
+	b.Reset(time.Minute)
+	require.Equal(2, v.Pending())
+}
@@ -0,0 +1,144 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// ClockState is a state of ClockFake.
+type ClockState int
+
+const (
+	// ClockIdle is the state where the timer is never Reset or is Stopped.
+	ClockIdle ClockState = iota
+	// ClockScheduled is the state where the timer is Reset and not fired yet.
+	ClockScheduled
+	// ClockSending is the state where Send is waiting for the fire to be received.
+	ClockSending
+	// ClockFired is the state where the last fire is delivered and the timer is not Reset since then.
+	ClockFired
+)
+
+func (s ClockState) String() string {
+	switch s {
+	case ClockIdle:
+		return "idle"
+	case ClockScheduled:
+		return "scheduled"
+	case ClockSending:
+		return "sending"
+	case ClockFired:
+		return "fired"
+	}
+	return "unknown"
+}
+
+// ClockStateChange is a state transition of ClockFake.
+type ClockStateChange struct {
+	From, To ClockState
+	// At is the fake current time when the transition happened.
+	At time.Time
+}
+
+// State returns the current state of c.
+func (c *ClockFake) State() ClockState {
+	c.Lock()
+	defer c.Unlock()
+	return c.stateLocked()
+}
+
+func (c *ClockFake) stateLocked() ClockState {
+	switch {
+	case c.sending:
+		return ClockSending
+	case c.scheduled:
+		return ClockScheduled
+	case c.fired:
+		return ClockFired
+	}
+	return ClockIdle
+}
+
+// unlockState unlocks c after notifying subscribers of the transition from before, if any.
+func (c *ClockFake) unlockState(before ClockState) {
+	if after := c.stateLocked(); after != before {
+		change := ClockStateChange{From: before, To: after, At: c.current}
+		for _, sub := range c.subs {
+			sub.push(change)
+		}
+	}
+	c.Unlock()
+}
+
+// Subscribe returns a channel delivering every state transition of c from now on, in order.
+// Transitions are buffered without bound, so c never blocks on slow subscribers.
+// Calling cancel stops the subscription and closes the channel; undelivered transitions are discarded.
+func (c *ClockFake) Subscribe() (changes <-chan ClockStateChange, cancel func()) {
+	sub := &stateSub{
+		signal: make(chan struct{}, 1),
+		out:    make(chan ClockStateChange),
+		done:   make(chan struct{}),
+	}
+	c.Lock()
+	c.subs = append(c.subs, sub)
+	c.Unlock()
+	go sub.run()
+
+	var once sync.Once
+	return sub.out, func() {
+		once.Do(func() {
+			c.Lock()
+			for i, s := range c.subs {
+				if s == sub {
+					c.subs = append(c.subs[:i], c.subs[i+1:]...)
+					break
+				}
+			}
+			c.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
+type stateSub struct {
+	mu     sync.Mutex
+	queue  []ClockStateChange
+	signal chan struct{}
+	out    chan ClockStateChange
+	done   chan struct{}
+}
+
+func (s *stateSub) push(change ClockStateChange) {
+	s.mu.Lock()
+	s.queue = append(s.queue, change)
+	s.mu.Unlock()
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *stateSub) run() {
+	defer close(s.out)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.signal:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		next := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.out <- next:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockFake_State(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := mockable.NewClockFake(now)
+	require.Equal(mockable.ClockIdle, c.State())
+
+	changes, cancel := c.Subscribe()
+	defer cancel()
+
+	c.Reset(time.Second)
+	require.Equal(mockable.ClockScheduled, c.State())
+
+	go c.Send()
+	require.Equal(
+		mockable.ClockStateChange{From: mockable.ClockIdle, To: mockable.ClockScheduled, At: now},
+		<-changes,
+	)
+	require.Equal(
+		mockable.ClockStateChange{From: mockable.ClockScheduled, To: mockable.ClockSending, At: now.Add(time.Second + 1)},
+		<-changes,
+	)
+	require.Equal(mockable.ClockSending, c.State())
+
+	<-c.C()
+	require.Equal(
+		mockable.ClockStateChange{From: mockable.ClockSending, To: mockable.ClockFired, At: now.Add(time.Second + 1)},
+		<-changes,
+	)
+	require.Equal(mockable.ClockFired, c.State())
+
+	c.Reset(time.Second)
+	c.Reset(time.Second)
+	c.Stop()
+	require.Equal(mockable.ClockFired, (<-changes).From)
+	change := <-changes
+	require.Equal(mockable.ClockScheduled, change.From)
+	require.Equal(mockable.ClockIdle, change.To)
+	require.Equal("idle", change.To.String())
+
+	cancel()
+	for range changes {
+	}
+}
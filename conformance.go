@@ -0,0 +1,174 @@
+package mockable
+
+import (
+	"testing"
+	"time"
+)
+
+// RunClockConformance runs a test suite verifying that Clocks created by newClock
+// conform to the contract of Clock and Timer documented in this package:
+//
+//   - a Clock is stopped at creation, so Stop returns false and C does not fire;
+//   - Reset(d) makes C fire once, no earlier than d after Reset, with a time not after Now;
+//   - Stop on an armed Clock returns true and prevents it from firing;
+//   - Stop after the fire has been received returns false;
+//   - Reset re-arms a Clock whether it is armed, stopped or fired;
+//   - Reset(0) makes C fire promptly;
+//   - Reset with a negative duration fires promptly as well, with a time not before Reset.
+//
+// If a Clock implements Advancer, its time is advanced by Advance, so fakes can be verified without waiting.
+// Otherwise it is assumed to run in real time and the suite takes a few hundred milliseconds.
+// newClock is called once per subtest.
+func RunClockConformance(t *testing.T, newClock func() Clock) {
+	t.Helper()
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, c *conformanceClock)
+	}{
+		{"stopped at creation", func(t *testing.T, c *conformanceClock) {
+			if c.Stop() {
+				t.Errorf("Stop on a new clock returned true")
+			}
+			c.expectNoFire(t, 2)
+		}},
+		{"Reset fires once", func(t *testing.T, c *conformanceClock) {
+			before := c.Now()
+			c.Reset(c.unit)
+			fired, ok := c.expectFire(t, 2)
+			if !ok {
+				return
+			}
+			if fired.Before(before.Add(c.unit)) {
+				t.Errorf("fired at %s, earlier than %s after Reset at %s", fired, c.unit, before)
+			}
+			if now := c.Now(); fired.After(now) {
+				t.Errorf("fired at %s, after Now %s", fired, now)
+			}
+			if c.Stop() {
+				t.Errorf("Stop after the fire was received returned true")
+			}
+			c.expectNoFire(t, 2)
+		}},
+		{"Stop prevents fire", func(t *testing.T, c *conformanceClock) {
+			c.Reset(c.unit)
+			if !c.Stop() {
+				t.Errorf("Stop on an armed clock returned false")
+			}
+			c.expectNoFire(t, 2)
+			if c.Stop() {
+				t.Errorf("Stop on a stopped clock returned true")
+			}
+		}},
+		{"Reset re-arms", func(t *testing.T, c *conformanceClock) {
+			c.Reset(100 * c.unit)
+			c.Reset(c.unit)
+			if _, ok := c.expectFire(t, 2); !ok {
+				return
+			}
+			c.Reset(c.unit)
+			if _, ok := c.expectFire(t, 2); !ok {
+				return
+			}
+			c.Reset(c.unit)
+			c.Stop()
+			c.Reset(c.unit)
+			if _, ok := c.expectFire(t, 2); !ok {
+				return
+			}
+			c.expectNoFire(t, 2)
+		}},
+		{"Reset zero fires promptly", func(t *testing.T, c *conformanceClock) {
+			c.Reset(0)
+			c.expectFire(t, 1)
+		}},
+		{"Reset negative fires at Reset", func(t *testing.T, c *conformanceClock) {
+			before := c.Now()
+			c.Reset(-c.unit)
+			fired, ok := c.expectFire(t, 1)
+			if ok && fired.Before(before) {
+				t.Errorf("fired at %s, before Reset at %s", fired, before)
+			}
+		}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := &conformanceClock{Clock: newClock(), unit: 20 * time.Millisecond}
+			c.advancer, c.virtual = c.Clock.(Advancer)
+			if c.virtual {
+				c.unit = time.Second
+			}
+			tc.fn(t, c)
+		})
+	}
+}
+
+type conformanceClock struct {
+	Clock
+	advancer Advancer
+	virtual  bool
+	unit     time.Duration
+}
+
+// receive receives from C until n units have passed, or slack more in real time.
+// It advances the time if c is virtual.
+func (c *conformanceClock) receive(n int, slack time.Duration) (fired time.Time, ok bool) {
+	span := time.Duration(n) * c.unit
+	if !c.virtual {
+		timer := time.NewTimer(span + slack)
+		defer timer.Stop()
+		select {
+		case fired = <-c.C():
+			return fired, true
+		case <-timer.C:
+			return time.Time{}, false
+		}
+	}
+
+	// A receiver must be ready before Advance, since some fakes block until the fire is received.
+	result := make(chan time.Time, 1)
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case fired := <-c.C():
+			result <- fired
+		case <-cancel:
+		}
+	}()
+	c.advancer.Advance(span)
+	timer := time.NewTimer(slack)
+	defer timer.Stop()
+	select {
+	case fired = <-result:
+		ok = true
+	case <-timer.C:
+	}
+	close(cancel)
+	<-done
+	if !ok {
+		select {
+		case fired = <-result:
+			ok = true
+		default:
+		}
+	}
+	return fired, ok
+}
+
+func (c *conformanceClock) expectFire(t *testing.T, n int) (time.Time, bool) {
+	t.Helper()
+	fired, ok := c.receive(n, time.Second)
+	if !ok {
+		t.Errorf("C did not fire within %s", time.Duration(n)*c.unit)
+	}
+	return fired, ok
+}
+
+func (c *conformanceClock) expectNoFire(t *testing.T, n int) {
+	t.Helper()
+	if fired, ok := c.receive(n, 10*time.Millisecond); ok {
+		t.Errorf("C unexpectedly fired at %s", fired)
+	}
+}
@@ -0,0 +1,41 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+)
+
+type advancingTimer struct {
+	*mockable.VirtualTimer
+	v *mockable.VirtualClock
+}
+
+func (t advancingTimer) Advance(d time.Duration) int {
+	return t.v.Advance(d)
+}
+
+func TestRunClockConformance(t *testing.T) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	t.Run("ClockReal", func(t *testing.T) {
+		mockable.RunClockConformance(t, func() mockable.Clock { return mockable.NewClockReal() })
+	})
+	t.Run("ClockRealWall", func(t *testing.T) {
+		mockable.RunClockConformance(t, func() mockable.Clock { return mockable.NewClockRealWall(0) })
+	})
+	t.Run("ClockFake", func(t *testing.T) {
+		mockable.RunClockConformance(t, func() mockable.Clock { return mockable.NewClockFake(start) })
+	})
+	t.Run("VirtualTimer", func(t *testing.T) {
+		mockable.RunClockConformance(t, func() mockable.Clock {
+			v := mockable.NewVirtualClock(start)
+			return advancingTimer{v.NewTimer(), v}
+		})
+	})
+	t.Run("VirtualTimer real time", func(t *testing.T) {
+		mockable.RunClockConformance(t, func() mockable.Clock {
+			return mockable.NewVirtualClock(start, mockable.WithRealTime()).NewTimer()
+		})
+	})
+}
@@ -0,0 +1,113 @@
+package mockable
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+var _ net.Conn = (*ConnFake)(nil)
+
+// ConnFake wraps a net.Conn so that its deadlines are evaluated against injected Clocks.
+//
+// A deadline set by SetDeadline, SetReadDeadline or SetWriteDeadline is converted to
+// a duration relative to Clock.Now() and armed on the Clock.
+// When the Clock fires, a deadline in the past is set to the wrapped net.Conn,
+// which makes pending and future operations fail with os.ErrDeadlineExceeded.
+// With ClockFake, deadline-based timeouts can then be triggered by calling Send instead of sleeping.
+//
+// The wrapped net.Conn must support deadlines, e.g. one created by net.Pipe.
+// Read and write deadlines need their own Clock since each Clock is a single timer.
+type ConnFake struct {
+	net.Conn
+	read  *clockDeadline
+	write *clockDeadline
+}
+
+// NewConnFake returns a ConnFake wrapping conn.
+// Read deadlines are measured by readClock and write deadlines by writeClock.
+func NewConnFake(conn net.Conn, readClock, writeClock Clock) *ConnFake {
+	return &ConnFake{
+		Conn:  conn,
+		read:  &clockDeadline{clock: readClock, set: conn.SetReadDeadline},
+		write: &clockDeadline{clock: writeClock, set: conn.SetWriteDeadline},
+	}
+}
+
+func (c *ConnFake) SetDeadline(t time.Time) error {
+	if err := c.read.Set(t); err != nil {
+		return err
+	}
+	return c.write.Set(t)
+}
+
+func (c *ConnFake) SetReadDeadline(t time.Time) error {
+	return c.read.Set(t)
+}
+
+func (c *ConnFake) SetWriteDeadline(t time.Time) error {
+	return c.write.Set(t)
+}
+
+func (c *ConnFake) Close() error {
+	c.read.Set(time.Time{})
+	c.write.Set(time.Time{})
+	return c.Conn.Close()
+}
+
+// aLongTimeAgo is a non-zero time, far in the past, used for immediate expiration of deadlines.
+var aLongTimeAgo = time.Unix(1, 0)
+
+type clockDeadline struct {
+	mu     sync.Mutex
+	clock  Clock
+	set    func(t time.Time) error
+	cancel chan struct{}
+	done   chan struct{}
+}
+
+func (d *clockDeadline) Set(t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel != nil {
+		d.clock.Stop()
+		close(d.cancel)
+		// The waiter must exit before the clock is reset,
+		// otherwise it may steal the next fire.
+		<-d.done
+		d.cancel, d.done = nil, nil
+	}
+
+	if t.IsZero() {
+		return d.set(time.Time{})
+	}
+
+	remaining := t.Sub(d.clock.Now())
+	if remaining <= 0 {
+		return d.set(aLongTimeAgo)
+	}
+
+	if err := d.set(time.Time{}); err != nil {
+		return err
+	}
+
+	cancel, done := make(chan struct{}), make(chan struct{})
+	d.cancel, d.done = cancel, done
+	d.clock.Reset(remaining)
+	go func() {
+		defer close(done)
+		select {
+		case <-d.clock.C():
+		case <-cancel:
+			return
+		}
+		select {
+		case <-cancel:
+			return
+		default:
+		}
+		_ = d.set(aLongTimeAgo)
+	}()
+	return nil
+}
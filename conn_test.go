@@ -0,0 +1,68 @@
+package mockable_test
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnFake(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	readClock := mockable.NewClockFake(now)
+	writeClock := mockable.NewClockFake(now)
+
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := mockable.NewConnFake(client, readClock, writeClock)
+	defer conn.Close()
+
+	read := func() chan error {
+		ch := make(chan error)
+		go func() {
+			_, err := conn.Read(make([]byte, 1))
+			ch <- err
+		}()
+		return ch
+	}
+
+	require.NoError(conn.SetReadDeadline(now.Add(time.Minute)))
+	require.Equal(time.Minute, <-readClock.ResetCh)
+
+	errCh := read()
+	select {
+	case err := <-errCh:
+		t.Fatalf("read returned before the clock fires: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	readClock.Send()
+	require.ErrorIs(<-errCh, os.ErrDeadlineExceeded)
+
+	// Resetting the deadline re-arms the clock, discarding the earlier one.
+	require.NoError(conn.SetReadDeadline(readClock.Now().Add(time.Second)))
+	require.Equal(time.Second, <-readClock.ResetCh)
+	require.NoError(conn.SetReadDeadline(readClock.Now().Add(2 * time.Second)))
+	require.Equal(2*time.Second, <-readClock.ResetCh)
+
+	errCh = read()
+	_, err := server.Write([]byte("a"))
+	require.NoError(err)
+	require.NoError(<-errCh)
+
+	// Zero value clears the deadline.
+	require.NoError(conn.SetReadDeadline(time.Time{}))
+	errCh = read()
+	_, err = server.Write([]byte("b"))
+	require.NoError(err)
+	require.NoError(<-errCh)
+
+	// A deadline in the past expires immediately.
+	require.NoError(conn.SetWriteDeadline(writeClock.Now().Add(-time.Second)))
+	_, err = conn.Write([]byte("c"))
+	require.ErrorIs(err, os.ErrDeadlineExceeded)
+}
@@ -0,0 +1,71 @@
+package mockable
+
+import (
+	"context"
+	"time"
+)
+
+// WithDeadline is like context.WithDeadline but the deadline is measured by clock.
+//
+// Deadline of the returned context reports d, which is a time in terms of clock.Now,
+// and its Done channel is closed when clock fires after it is reset to d.Sub(clock.Now()),
+// when the returned cancel function is called, or when the parent's Done channel is closed,
+// whichever happens first.
+// When the deadline passes, Err returns context.DeadlineExceeded.
+//
+// The clock is dedicated to the returned context until the context is done;
+// it must not be used for other purposes meanwhile.
+// With ClockFake, tests can expire the context by calling Send.
+func WithDeadline(parent context.Context, clock Clock, d time.Time) (context.Context, context.CancelFunc) {
+	inner, cancel := context.WithCancelCause(parent)
+
+	if cur, ok := parent.Deadline(); ok && cur.Before(d) {
+		// The parent deadline is already sooner than the new one.
+		return &clockCtx{Context: inner, deadline: cur}, func() { cancel(context.Canceled) }
+	}
+
+	ctx := &clockCtx{Context: inner, deadline: d}
+
+	remaining := d.Sub(clock.Now())
+	if remaining <= 0 {
+		cancel(context.DeadlineExceeded)
+		return ctx, func() { cancel(context.Canceled) }
+	}
+
+	clock.Reset(remaining)
+	go func() {
+		select {
+		case <-clock.C():
+			cancel(context.DeadlineExceeded)
+		case <-inner.Done():
+			clock.Stop()
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// WithTimeout returns WithDeadline(parent, clock, clock.Now().Add(timeout)).
+func WithTimeout(parent context.Context, clock Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, clock, clock.Now().Add(timeout))
+}
+
+type clockCtx struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c *clockCtx) Deadline() (deadline time.Time, ok bool) {
+	return c.deadline, true
+}
+
+func (c *clockCtx) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+func (c *clockCtx) String() string {
+	return "mockable.WithDeadline(" + c.deadline.String() + ")"
+}
@@ -0,0 +1,71 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDeadline(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	ctx, cancel := mockable.WithTimeout(context.Background(), clock, time.Hour)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(ok)
+	require.Equal(now.Add(time.Hour), deadline)
+	require.Equal(time.Hour, <-clock.ResetCh)
+	require.NoError(ctx.Err())
+
+	clock.Send()
+	<-ctx.Done()
+	require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+
+	// Cancelled before the deadline.
+	ctx, cancel = mockable.WithDeadline(context.Background(), clock, clock.Now().Add(time.Minute))
+	require.Equal(time.Minute, <-clock.ResetCh)
+	cancel()
+	<-ctx.Done()
+	require.ErrorIs(ctx.Err(), context.Canceled)
+	<-clock.StopCh
+
+	// The deadline has already passed.
+	ctx, cancel = mockable.WithDeadline(context.Background(), clock, clock.Now().Add(-time.Minute))
+	defer cancel()
+	<-ctx.Done()
+	require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+
+	// The parent deadline is sooner.
+	parent, cancelParent := mockable.WithTimeout(context.Background(), clock, time.Second)
+	defer cancelParent()
+	<-clock.ResetCh
+	ctx, cancel = mockable.WithTimeout(parent, mockable.NewClockFake(clock.Now()), time.Hour)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	parentDeadline, _ := parent.Deadline()
+	require.Equal(parentDeadline, deadline)
+	clock.Send()
+	<-ctx.Done()
+	require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+}
+
+func TestWithDeadline_real(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := mockable.WithTimeout(context.Background(), mockable.NewClockReal(), time.Millisecond)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context is not done")
+	}
+	require.ErrorIs(ctx.Err(), context.DeadlineExceeded)
+}
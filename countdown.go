@@ -0,0 +1,117 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// Countdown counts down to a deadline, publishing the remaining time on every tick of a Ticker.
+//
+// Updates are meant for progress displays and expiry warnings: only the latest remaining time is kept
+// for a slow receiver, and stale ones are replaced. Done is closed on the first tick at or after the deadline,
+// so the cadence should divide the total duration for Done to be closed right at the deadline.
+//
+// With TickerFake, each Send makes the next update, so a countdown is driven tick by tick in tests.
+type Countdown struct {
+	nower    Nower
+	ticker   Ticker
+	deadline time.Time
+
+	updates  chan time.Duration
+	done     chan struct{}
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	exited   chan struct{}
+}
+
+// NewCountdown starts counting down total from the current time of nower,
+// publishing the remaining time every cadence.
+// ticker is exclusively used by the Countdown until it is done or stopped.
+// If total is not positive, the Countdown is done at once.
+func NewCountdown(nower Nower, ticker Ticker, total, cadence time.Duration) *Countdown {
+	c := &Countdown{
+		nower:    nower,
+		ticker:   ticker,
+		deadline: nower.Now().Add(total),
+		updates:  make(chan time.Duration, 1),
+		done:     make(chan struct{}),
+		stopCh:   make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	if total <= 0 {
+		c.publish(0)
+		close(c.done)
+		close(c.exited)
+		return c
+	}
+	ticker.Reset(cadence)
+	go c.run()
+	return c
+}
+
+func (c *Countdown) run() {
+	defer close(c.exited)
+	defer c.ticker.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case t := <-c.ticker.C():
+			remaining := c.deadline.Sub(t)
+			if remaining < 0 {
+				remaining = 0
+			}
+			c.publish(remaining)
+			if remaining == 0 {
+				close(c.done)
+				return
+			}
+		}
+	}
+}
+
+// publish replaces the unreceived update, if any, with remaining.
+func (c *Countdown) publish(remaining time.Duration) {
+	select {
+	case <-c.updates:
+	default:
+	}
+	c.updates <- remaining
+}
+
+// Updates returns a channel to which the remaining time is sent on every tick.
+// The last update, sent as Done is closed, is 0.
+func (c *Countdown) Updates() <-chan time.Duration {
+	return c.updates
+}
+
+// Done returns a channel closed when the deadline is reached. It is never closed if c is stopped before.
+func (c *Countdown) Done() <-chan struct{} {
+	return c.done
+}
+
+// Deadline returns the time the countdown reaches zero.
+func (c *Countdown) Deadline() time.Time {
+	return c.deadline
+}
+
+// Remaining returns the time remaining until the deadline, measured by the Nower. It is never negative.
+func (c *Countdown) Remaining() time.Duration {
+	remaining := c.deadline.Sub(c.nower.Now())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Stop stops the countdown and its ticker. It returns true if c was stopped before it was done.
+func (c *Countdown) Stop() bool {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+	<-c.exited
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
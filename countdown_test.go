@@ -0,0 +1,72 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountdown(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	ticker := mockable.NewTickerFake(now)
+	countdown := mockable.NewCountdown(ticker, ticker, 3*time.Second, time.Second)
+	require.Equal(now.Add(3*time.Second), countdown.Deadline())
+	require.Equal(3*time.Second, countdown.Remaining())
+	require.Equal(time.Second, <-ticker.ResetCh)
+
+	for _, want := range []time.Duration{2 * time.Second, time.Second} {
+		ticker.Send()
+		require.Equal(want, <-countdown.Updates())
+		require.False(closed(countdown.Done()))
+	}
+	ticker.Send()
+	require.Equal(time.Duration(0), <-countdown.Updates())
+	<-countdown.Done()
+	<-ticker.StopCh
+	require.Equal(time.Duration(0), countdown.Remaining())
+	require.False(countdown.Stop())
+}
+
+func TestCountdown_coalesce(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	ticker := mockable.NewTickerFake(now)
+	countdown := mockable.NewCountdown(ticker, ticker, 3*time.Second, time.Second)
+
+	// Updates not received are replaced by the latest one.
+	for i := 0; i < 3; i++ {
+		ticker.Send()
+	}
+	<-countdown.Done()
+	require.Equal(time.Duration(0), <-countdown.Updates())
+	select {
+	case <-countdown.Updates():
+		t.Fatal("stale update is left")
+	default:
+	}
+}
+
+func TestCountdown_stop(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	ticker := mockable.NewTickerFake(now)
+	countdown := mockable.NewCountdown(ticker, ticker, time.Minute, 10*time.Second)
+	ticker.Send()
+	require.Equal(50*time.Second, <-countdown.Updates())
+
+	require.True(countdown.Stop())
+	require.False(ticker.IsRunning())
+	require.False(closed(countdown.Done()))
+
+	// A non-positive total is done at once.
+	countdown = mockable.NewCountdown(ticker, ticker, 0, time.Second)
+	<-countdown.Done()
+	require.Equal(time.Duration(0), <-countdown.Updates())
+	require.False(countdown.Stop())
+}
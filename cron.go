@@ -0,0 +1,259 @@
+package mockable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The Schedule describes a recurring schedule for Scheduler.
+type Schedule interface {
+	// Next returns the next activation time strictly later than t,
+	// or the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule activating every d.
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic("non-positive interval for Every")
+	}
+	return everySchedule(d)
+}
+
+type everySchedule time.Duration
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// CronSchedule is a Schedule described by a standard 5-field cron expression.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{0, 59, nil}
+	cronHour   = cronField{0, 23, nil}
+	cronDom    = cronField{1, 31, nil}
+	cronMonth  = cronField{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is also Sunday.
+	cronDow = cronField{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a cron expression.
+//
+// The expression consists of 5 space-separated fields: minute, hour, day of month, month and day of week.
+// Each field accepts "*", values, ranges ("a-b"), steps ("*/n", "a-b/n", "a/n") and comma-separated lists of them.
+// Months and days of week also accept 3-letter English names. Both 0 and 7 mean Sunday.
+// Descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are accepted as well.
+//
+// As is for the traditional cron, if both day of month and day of week are restricted,
+// a day matching either of them matches.
+func ParseCron(expr string) (*CronSchedule, error) {
+	if d, ok := cronDescriptors[strings.ToLower(strings.TrimSpace(expr))]; ok {
+		expr = d
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("mockable: cron expression %q must have 5 fields, but has %d", expr, len(fields))
+	}
+
+	var (
+		s   CronSchedule
+		err error
+	)
+	if s.minute, _, err = parseCronField(fields[0], cronMinute); err != nil {
+		return nil, err
+	}
+	if s.hour, _, err = parseCronField(fields[1], cronHour); err != nil {
+		return nil, err
+	}
+	if s.dom, s.domStar, err = parseCronField(fields[2], cronDom); err != nil {
+		return nil, err
+	}
+	if s.month, _, err = parseCronField(fields[3], cronMonth); err != nil {
+		return nil, err
+	}
+	if s.dow, s.dowStar, err = parseCronField(fields[4], cronDow); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return &s, nil
+}
+
+// MustParseCron is like ParseCron but panics if expr cannot be parsed.
+func MustParseCron(expr string) *CronSchedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parseCronField(field string, f cronField) (bits uint64, star bool, err error) {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("mockable: invalid step %q in cron field %q", stepPart, field)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+			star = star || !hasStep
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			if lo, err = f.value(loPart); err != nil {
+				return 0, false, err
+			}
+			if hi, err = f.value(hiPart); err != nil {
+				return 0, false, err
+			}
+		default:
+			if lo, err = f.value(rangePart); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			if hasStep {
+				hi = f.max
+			}
+		}
+		if lo > hi {
+			return 0, false, fmt.Errorf("mockable: invalid range %q in cron field %q", rangePart, field)
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, star, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("mockable: cron value %q out of range [%d, %d]", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the next time matching s strictly later than t, in t's location.
+// It returns the zero time if nothing matches within 5 years.
+//
+// Wall clock times skipped by a daylight saving time transition never match.
+// Wall clock times repeated by a transition match only at their first occurrence.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	yearLimit := t.Year() + 5
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = startOfDay(t, t.Month()+1, 1)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		month := t.Month()
+		t = startOfDay(t, t.Month(), t.Day()+1)
+		if t.Month() != month {
+			goto WRAP
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		day := t.Day()
+		// Absolute arithmetic steps over hours skipped or repeated by transitions.
+		t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute)
+		if t.Day() != day {
+			goto WRAP
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		hour := t.Hour()
+		t = t.Add(time.Minute)
+		if t.Hour() != hour {
+			goto WRAP
+		}
+	}
+
+	if repeatedWallClock(t) {
+		t = t.Add(time.Minute)
+		goto WRAP
+	}
+	return t
+}
+
+// startOfDay returns the first instant of the given day in t's location, which is later than t.
+// If midnight of the day is skipped by a transition, it returns the first hour after that.
+func startOfDay(t time.Time, month time.Month, day int) time.Time {
+	s := time.Date(t.Year(), month, day, 0, 0, 0, 0, t.Location())
+	for !s.After(t) {
+		// time.Date normalizes a skipped wall clock backwards.
+		s = s.Add(time.Hour)
+	}
+	return s
+}
+
+// repeatedWallClock reports whether the wall clock of t has already been shown
+// at an earlier instant because of a backward transition of the zone offset.
+func repeatedWallClock(t time.Time) bool {
+	_, off := t.Zone()
+	// Transitions are assumed to shift the offset by at most 2 hours.
+	_, offBefore := t.Add(-2 * time.Hour).Zone()
+	if offBefore <= off {
+		return false
+	}
+	earlier := t.Add(-time.Duration(offBefore-off) * time.Second)
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := earlier.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2 &&
+		t.Hour() == earlier.Hour() && t.Minute() == earlier.Minute()
+}
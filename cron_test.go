@@ -0,0 +1,97 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	require := require.New(t)
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "foo * * * *"} {
+		_, err := mockable.ParseCron(expr)
+		require.Error(err, "expr = %q", expr)
+	}
+
+	base := time.Date(2023, 5, 1, 12, 34, 56, 789, time.UTC) // Monday
+	for _, tc := range []struct {
+		expr string
+		next []time.Time
+	}{
+		{"* * * * *", []time.Time{
+			time.Date(2023, 5, 1, 12, 35, 0, 0, time.UTC),
+			time.Date(2023, 5, 1, 12, 36, 0, 0, time.UTC),
+		}},
+		{"*/20 9-17 * * mon-fri", []time.Time{
+			time.Date(2023, 5, 1, 12, 40, 0, 0, time.UTC),
+			time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC),
+		}},
+		{"0 0 * * 7", []time.Time{
+			time.Date(2023, 5, 7, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 5, 14, 0, 0, 0, 0, time.UTC),
+		}},
+		// day of month or day of week.
+		{"0 0 15 * sat", []time.Time{
+			time.Date(2023, 5, 6, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 5, 13, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC),
+		}},
+		{"0 12 29 feb *", []time.Time{
+			time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+			time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC),
+		}},
+		{"@monthly", []time.Time{
+			time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{"0 0 31 2 *", []time.Time{{}}},
+	} {
+		s, err := mockable.ParseCron(tc.expr)
+		require.NoError(err, "expr = %q", tc.expr)
+		cur := base
+		for _, want := range tc.next {
+			cur = s.Next(cur)
+			require.Equal(want, cur, "expr = %q", tc.expr)
+		}
+	}
+}
+
+func TestCronSchedule_dst(t *testing.T) {
+	require := require.New(t)
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(err)
+
+	collect := func(expr string, from time.Time, n int) []string {
+		s := mockable.MustParseCron(expr)
+		var out []string
+		for i := 0; i < n; i++ {
+			from = s.Next(from)
+			out = append(out, from.Format("2006-01-02 15:04 MST"))
+		}
+		return out
+	}
+
+	// 02:30 does not exist on 2023-03-12.
+	require.Equal(
+		[]string{"2023-03-11 02:30 EST", "2023-03-13 02:30 EDT"},
+		collect("30 2 * * *", time.Date(2023, 3, 11, 0, 0, 0, 0, ny), 2),
+	)
+	// 01:30 occurs twice on 2023-11-05.
+	require.Equal(
+		[]string{"2023-11-05 01:30 EDT", "2023-11-06 01:30 EST"},
+		collect("30 1 * * *", time.Date(2023, 11, 5, 0, 0, 0, 0, ny), 2),
+	)
+	require.Equal(
+		[]string{"2023-11-05 00:00 EDT", "2023-11-05 01:00 EDT", "2023-11-05 02:00 EST"},
+		collect("0 * * * *", time.Date(2023, 11, 4, 23, 30, 0, 0, ny), 3),
+	)
+	require.Equal(
+		[]string{"2023-03-12 01:00 EST", "2023-03-12 03:00 EDT"},
+		collect("0 * * * *", time.Date(2023, 3, 12, 0, 30, 0, 0, ny), 2),
+	)
+}
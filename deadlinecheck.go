@@ -0,0 +1,77 @@
+package mockable
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ArmedDeadliner is implemented by fakes which can report the deadlines of their armed timers.
+// ClockFake and VirtualClock implement it.
+type ArmedDeadliner interface {
+	ArmedDeadlines() []time.Time
+}
+
+var (
+	_ ArmedDeadliner = (*ClockFake)(nil)
+	_ ArmedDeadliner = (*VirtualClock)(nil)
+)
+
+// DeadlineViolationError is returned from CheckDeadlines
+// when armed timers outlive the deadline of the context.
+type DeadlineViolationError struct {
+	// CtxDeadline is the deadline of the context.
+	CtxDeadline time.Time
+	// Deadlines are deadlines of armed timers later than CtxDeadline.
+	Deadlines []time.Time
+}
+
+func (e *DeadlineViolationError) Error() string {
+	s := make([]string, len(e.Deadlines))
+	for i, d := range e.Deadlines {
+		s[i] = fmt.Sprintf("%s (+%s)", d.Format(time.RFC3339Nano), d.Sub(e.CtxDeadline))
+	}
+	return fmt.Sprintf(
+		"mockable: timers armed beyond the context deadline %s: %s",
+		e.CtxDeadline.Format(time.RFC3339Nano), strings.Join(s, ", "),
+	)
+}
+
+// CheckDeadlines reports whether every armed timer of sources fires no later than the deadline of ctx.
+// It returns a *DeadlineViolationError listing the offending deadlines, or nil if there is none
+// or ctx has no deadline.
+//
+// The deadline of ctx must be in terms of the fake time,
+// as is for contexts derived by WithDeadline or WithTimeout with the same fake.
+func CheckDeadlines(ctx context.Context, sources ...ArmedDeadliner) error {
+	ctxDeadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	var violations []time.Time
+	for _, s := range sources {
+		for _, d := range s.ArmedDeadlines() {
+			if d.After(ctxDeadline) {
+				violations = append(violations, d)
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &DeadlineViolationError{CtxDeadline: ctxDeadline, Deadlines: violations}
+}
+
+// AssertDeadlines marks t as failed if CheckDeadlines returns an error,
+// which is typically a timeout longer than the caller's budget.
+// It reports whether the check passed.
+func AssertDeadlines(t testing.TB, ctx context.Context, sources ...ArmedDeadliner) bool {
+	t.Helper()
+	if err := CheckDeadlines(ctx, sources...); err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package mockable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDeadlines(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	fake := mockable.NewClockFake(start)
+
+	// No deadline, nothing to check.
+	fake.Reset(time.Hour)
+	require.NoError(mockable.CheckDeadlines(context.Background(), v, fake))
+
+	ctx, cancel := mockable.WithTimeout(context.Background(), v.NewTimer(), 5*time.Second)
+	defer cancel()
+
+	fake.Reset(3 * time.Second)
+	inBudget := v.NewTimer()
+	inBudget.Reset(5 * time.Second)
+	require.NoError(mockable.CheckDeadlines(ctx, v, fake))
+	require.True(mockable.AssertDeadlines(t, ctx, v, fake))
+
+	overBudget := v.NewTimer()
+	overBudget.Reset(10 * time.Second)
+	fake.Reset(6 * time.Second)
+
+	err := mockable.CheckDeadlines(ctx, v, fake)
+	var violation *mockable.DeadlineViolationError
+	require.True(errors.As(err, &violation))
+	require.Equal(start.Add(5*time.Second), violation.CtxDeadline)
+	require.Equal([]time.Time{start.Add(10 * time.Second), start.Add(6 * time.Second)}, violation.Deadlines)
+
+	tb := &fakeTB{TB: t}
+	require.False(mockable.AssertDeadlines(tb, ctx, v, fake))
+	require.True(tb.failed)
+	require.Contains(tb.logs[0], "+5s")
+
+	overBudget.Stop()
+	fake.Stop()
+	require.NoError(mockable.CheckDeadlines(ctx, v, fake))
+}
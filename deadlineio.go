@@ -0,0 +1,164 @@
+package mockable
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// DeadlineError is returned from readers and writers wrapped by ReadWithDeadline and WriteWithDeadline
+// when an operation does not complete within the timeout.
+// It matches ErrTimeout and os.ErrDeadlineExceeded with errors.Is, and reports Timeout() == true like net.Error.
+type DeadlineError struct {
+	Op string
+}
+
+func (e *DeadlineError) Error() string {
+	return "mockable: " + e.Op + " deadline exceeded"
+}
+
+// Timeout reports true.
+func (e *DeadlineError) Timeout() bool {
+	return true
+}
+
+func (e *DeadlineError) Is(target error) bool {
+	return target == ErrTimeout || target == os.ErrDeadlineExceeded
+}
+
+type ioResult struct {
+	buf []byte
+	n   int
+	err error
+}
+
+// awaitIO waits for ch within d measured by timer. d <= 0 means no timeout.
+func awaitIO(timer Timer, d time.Duration, ch <-chan ioResult) (ioResult, bool) {
+	if d <= 0 {
+		return <-ch, true
+	}
+	timer.Reset(d)
+	select {
+	case res := <-ch:
+		if !timer.Stop() {
+			select {
+			case <-timer.C():
+			default:
+			}
+		}
+		return res, true
+	case <-timer.C():
+		return ioResult{}, false
+	}
+}
+
+// ReadWithDeadline wraps r so that every Read returns a *DeadlineError
+// if it does not complete within d measured by timer. d <= 0 means no timeout.
+// This gives streams lacking SetReadDeadline per-operation timeouts testable with ClockFake.
+//
+// A timed-out Read keeps running on r in the background, and its data is returned by following Reads,
+// so no data is lost. Reads are serialized. timer is exclusively used by the returned reader.
+func ReadWithDeadline(r io.Reader, timer Timer, d time.Duration) io.Reader {
+	return &deadlineReader{r: r, timer: timer, d: d}
+}
+
+type deadlineReader struct {
+	mu      sync.Mutex
+	r       io.Reader
+	timer   Timer
+	d       time.Duration
+	pending chan ioResult
+	// rest is data read but not yet returned, and restErr is the error to return after it.
+	rest    []byte
+	restErr error
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.rest) > 0 {
+		n := copy(p, r.rest)
+		r.rest = r.rest[n:]
+		return n, nil
+	}
+	if err := r.restErr; err != nil {
+		r.restErr = nil
+		return 0, err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if r.pending == nil {
+		ch := make(chan ioResult, 1)
+		buf := make([]byte, len(p))
+		go func() {
+			n, err := r.r.Read(buf)
+			ch <- ioResult{buf: buf[:n], n: n, err: err}
+		}()
+		r.pending = ch
+	}
+	res, ok := awaitIO(r.timer, r.d, r.pending)
+	if !ok {
+		return 0, &DeadlineError{Op: "read"}
+	}
+	r.pending = nil
+	n := copy(p, res.buf)
+	if n < len(res.buf) {
+		r.rest, r.restErr = res.buf[n:], res.err
+		return n, nil
+	}
+	return n, res.err
+}
+
+// WriteWithDeadline wraps w so that every Write returns a *DeadlineError
+// if it does not complete within d measured by timer. d <= 0 means no timeout.
+//
+// A timed-out Write keeps running on w in the background, so its data may still be written.
+// A following Write first waits for it, within its own timeout, and returns its error if it has failed.
+// Writes are serialized. timer is exclusively used by the returned writer.
+func WriteWithDeadline(w io.Writer, timer Timer, d time.Duration) io.Writer {
+	return &deadlineWriter{w: w, timer: timer, d: d}
+}
+
+type deadlineWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	timer   Timer
+	d       time.Duration
+	pending chan ioResult
+}
+
+func (w *deadlineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pending != nil {
+		res, ok := awaitIO(w.timer, w.d, w.pending)
+		if !ok {
+			return 0, &DeadlineError{Op: "write"}
+		}
+		w.pending = nil
+		if res.err != nil {
+			return 0, res.err
+		}
+	}
+
+	ch := make(chan ioResult, 1)
+	buf := append([]byte(nil), p...)
+	go func() {
+		n, err := w.w.Write(buf)
+		if err == nil && n < len(buf) {
+			err = io.ErrShortWrite
+		}
+		ch <- ioResult{n: n, err: err}
+	}()
+	res, ok := awaitIO(w.timer, w.d, ch)
+	if !ok {
+		w.pending = ch
+		return 0, &DeadlineError{Op: "write"}
+	}
+	return res.n, res.err
+}
@@ -0,0 +1,94 @@
+package mockable_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWithDeadline(t *testing.T) {
+	require := require.New(t)
+
+	pr, pw := io.Pipe()
+	clock := mockable.NewClockFake(time.Now())
+	r := mockable.ReadWithDeadline(pr, clock, time.Second)
+
+	type result struct {
+		n   int
+		err error
+	}
+	buf := make([]byte, 4)
+	read := func() <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			n, err := r.Read(buf)
+			ch <- result{n, err}
+		}()
+		return ch
+	}
+
+	ch := read()
+	require.Equal(time.Second, <-clock.ResetCh)
+	clock.Send()
+	res := <-ch
+	require.Equal(0, res.n)
+	require.ErrorIs(res.err, mockable.ErrTimeout)
+	require.ErrorIs(res.err, os.ErrDeadlineExceeded)
+	var timeout interface{ Timeout() bool }
+	require.True(errors.As(res.err, &timeout) && timeout.Timeout())
+
+	// The timed-out read continues in the background; its data is not lost.
+	go func() {
+		_, _ = pw.Write([]byte("foobar"))
+		pw.Close()
+	}()
+	ch = read()
+	res = <-ch
+	require.NoError(res.err)
+	require.Equal("foob", string(buf[:res.n]))
+
+	rest, err := io.ReadAll(r)
+	require.NoError(err)
+	require.Equal("ar", string(rest))
+}
+
+func TestWriteWithDeadline(t *testing.T) {
+	require := require.New(t)
+
+	pr, pw := io.Pipe()
+	clock := mockable.NewClockFake(time.Now())
+	w := mockable.WriteWithDeadline(pw, clock, time.Second)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := w.Write([]byte("foo"))
+		errCh <- err
+	}()
+	require.Equal(time.Second, <-clock.ResetCh)
+	clock.Send()
+	require.ErrorIs(<-errCh, mockable.ErrTimeout)
+
+	read := make(chan string, 1)
+	go func() {
+		b, _ := io.ReadAll(pr)
+		read <- string(b)
+	}()
+
+	// The next Write waits for the timed-out one.
+	n, err := w.Write([]byte("bar"))
+	require.NoError(err)
+	require.Equal(3, n)
+	pw.Close()
+	require.Equal("foobar", <-read)
+
+	// Without a timeout, the writer is transparent.
+	pr, pw = io.Pipe()
+	pr.CloseWithError(io.ErrUnexpectedEOF)
+	_, err = mockable.WriteWithDeadline(pw, clock, 0).Write([]byte("baz"))
+	require.ErrorIs(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,65 @@
+package mockable
+
+import (
+	"context"
+	"time"
+)
+
+// DeadlinePolicy derives the timeout of a child operation from the time remaining until the parent's deadline.
+type DeadlinePolicy func(remaining time.Duration) time.Duration
+
+// FractionOfRemaining gives the child the fraction f of the remaining time.
+func FractionOfRemaining(f float64) DeadlinePolicy {
+	return func(remaining time.Duration) time.Duration {
+		return time.Duration(float64(remaining) * f)
+	}
+}
+
+// MinusOverhead reserves overhead of the remaining time for the parent, e.g. to handle the child's failure.
+func MinusOverhead(overhead time.Duration) DeadlinePolicy {
+	return func(remaining time.Duration) time.Duration {
+		return remaining - overhead
+	}
+}
+
+// ClampTimeout clamps the child's timeout into [lo, hi]. Zero hi means no upper bound.
+// A child context never outlives its parent, so lo beyond the parent's deadline
+// is capped by WithChildDeadline.
+func ClampTimeout(lo, hi time.Duration) DeadlinePolicy {
+	return func(remaining time.Duration) time.Duration {
+		if hi > 0 && remaining > hi {
+			remaining = hi
+		}
+		if remaining < lo {
+			remaining = lo
+		}
+		return remaining
+	}
+}
+
+// ChildTimeout applies policies in order to the time remaining until the deadline of parent, measured by nower.
+// ok is false if parent has no deadline.
+// The result may be zero or negative, meaning the child should not be started.
+func ChildTimeout(parent context.Context, nower Nower, policies ...DeadlinePolicy) (timeout time.Duration, ok bool) {
+	deadline, ok := parent.Deadline()
+	if !ok {
+		return 0, false
+	}
+	timeout = deadline.Sub(nower.Now())
+	for _, p := range policies {
+		timeout = p(timeout)
+	}
+	return timeout, true
+}
+
+// WithChildDeadline returns a context for a child operation whose deadline is derived by ChildTimeout
+// and measured by clock, as WithDeadline does.
+// If parent has no deadline, it returns a context canceled only with parent or by the returned cancel function.
+// If the derived timeout is not positive, the returned context is already done with context.DeadlineExceeded.
+func WithChildDeadline(parent context.Context, clock Clock, policies ...DeadlinePolicy) (context.Context, context.CancelFunc) {
+	timeout, ok := ChildTimeout(parent, clock, policies...)
+	if !ok {
+		return context.WithCancel(parent)
+	}
+	return WithDeadline(parent, clock, clock.Now().Add(timeout))
+}
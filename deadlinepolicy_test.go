@@ -0,0 +1,74 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildTimeout(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	parentClock := mockable.NewClockFake(start)
+	parent, cancel := mockable.WithTimeout(context.Background(), parentClock, 10*time.Second)
+	defer cancel()
+
+	nower := &mockable.NowerFake{}
+	nower.SetNow(start.Add(2 * time.Second))
+
+	for _, tc := range []struct {
+		policies []mockable.DeadlinePolicy
+		expected time.Duration
+	}{
+		{nil, 8 * time.Second},
+		{[]mockable.DeadlinePolicy{mockable.FractionOfRemaining(0.5)}, 4 * time.Second},
+		{[]mockable.DeadlinePolicy{mockable.FractionOfRemaining(0.5), mockable.MinusOverhead(time.Second)}, 3 * time.Second},
+		{[]mockable.DeadlinePolicy{mockable.MinusOverhead(time.Second), mockable.FractionOfRemaining(0.5)}, 3500 * time.Millisecond},
+		{[]mockable.DeadlinePolicy{mockable.ClampTimeout(0, 5*time.Second)}, 5 * time.Second},
+		{[]mockable.DeadlinePolicy{mockable.MinusOverhead(9 * time.Second), mockable.ClampTimeout(time.Second, 0)}, time.Second},
+		{[]mockable.DeadlinePolicy{mockable.MinusOverhead(9 * time.Second)}, -time.Second},
+	} {
+		timeout, ok := mockable.ChildTimeout(parent, nower, tc.policies...)
+		require.True(ok)
+		require.Equal(tc.expected, timeout)
+	}
+
+	_, ok := mockable.ChildTimeout(context.Background(), nower)
+	require.False(ok)
+}
+
+func TestWithChildDeadline(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	parent, cancel := mockable.WithTimeout(context.Background(), mockable.NewClockFake(start), 10*time.Second)
+	defer cancel()
+
+	clock := mockable.NewClockFake(start.Add(2 * time.Second))
+	child, cancelChild := mockable.WithChildDeadline(parent, clock, mockable.FractionOfRemaining(0.5), mockable.MinusOverhead(time.Second))
+	defer cancelChild()
+	deadline, ok := child.Deadline()
+	require.True(ok)
+	require.Equal(start.Add(5*time.Second), deadline)
+	require.Equal(3*time.Second, <-clock.ResetCh)
+	clock.Send()
+	<-child.Done()
+	require.ErrorIs(child.Err(), context.DeadlineExceeded)
+	require.NoError(parent.Err())
+
+	// No time is left for the child.
+	late, cancelLate := mockable.WithChildDeadline(parent, clock, mockable.MinusOverhead(time.Hour))
+	defer cancelLate()
+	require.ErrorIs(late.Err(), context.DeadlineExceeded)
+
+	// The parent has no deadline.
+	free, cancelFree := mockable.WithChildDeadline(context.Background(), clock, mockable.MinusOverhead(time.Hour))
+	_, ok = free.Deadline()
+	require.False(ok)
+	cancelFree()
+	require.ErrorIs(free.Err(), context.Canceled)
+}
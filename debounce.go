@@ -0,0 +1,130 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer coalesces bursts of calls into a single invocation of a callback
+// which happens after a quiet period measured by the injected Timer.
+//
+// With ClockFake, tests end the quiet period by calling Send.
+type Debouncer struct {
+	mu      sync.Mutex
+	fnMu    sync.Mutex
+	timer   Timer
+	d       time.Duration
+	fn      func()
+	pending bool
+	stopped bool
+	// callback is the ID of the goroutine running fn, or 0.
+	callback uint64
+	stopCh   chan struct{}
+	done     chan struct{}
+}
+
+// NewDebouncer returns a Debouncer which invokes fn after d has elapsed since the last Call.
+// timer is exclusively used by the returned Debouncer until Stop is called.
+func NewDebouncer(timer Timer, d time.Duration, fn func()) *Debouncer {
+	deb := &Debouncer{
+		timer:  timer,
+		d:      d,
+		fn:     fn,
+		stopCh: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go deb.run()
+	return deb
+}
+
+func (d *Debouncer) run() {
+	defer close(d.done)
+	for {
+		select {
+		case <-d.stopCh:
+			return
+		case <-d.timer.C():
+			d.mu.Lock()
+			pending := d.pending
+			d.pending = false
+			d.mu.Unlock()
+			if pending {
+				d.invoke()
+			}
+		}
+	}
+}
+
+func (d *Debouncer) invoke() {
+	d.fnMu.Lock()
+	defer d.fnMu.Unlock()
+	d.mu.Lock()
+	d.callback = goroutineID()
+	d.mu.Unlock()
+	defer func() {
+		d.mu.Lock()
+		d.callback = 0
+		d.mu.Unlock()
+	}()
+	d.fn()
+}
+
+// Call schedules an invocation of the callback after the quiet period,
+// postponing the one already scheduled.
+// A Call racing with the expiration of the quiet period may be coalesced into
+// the invocation in progress. Call after Stop is ignored.
+func (d *Debouncer) Call() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return
+	}
+	d.pending = true
+	d.timer.Reset(d.d)
+}
+
+// Flush invokes the callback immediately if an invocation is scheduled,
+// and cancels the scheduled one. It reports whether the callback was invoked.
+func (d *Debouncer) Flush() bool {
+	d.mu.Lock()
+	pending := d.pending
+	if pending {
+		d.pending = false
+		d.timer.Stop()
+	}
+	d.mu.Unlock()
+	if pending {
+		d.invoke()
+	}
+	return pending
+}
+
+// Pending reports whether an invocation is scheduled.
+func (d *Debouncer) Pending() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pending
+}
+
+// Stop discards the scheduled invocation, if any, and releases the timer.
+// It waits for an invocation in progress to return,
+// unless called from the callback itself, in which case it returns immediately.
+func (d *Debouncer) Stop() {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return
+	}
+	d.stopped = true
+	d.pending = false
+	d.timer.Stop()
+	close(d.stopCh)
+	fromCallback := d.callback != 0 && d.callback == goroutineID()
+	d.mu.Unlock()
+	if fromCallback {
+		return
+	}
+	<-d.done
+	d.fnMu.Lock()
+	defer d.fnMu.Unlock()
+}
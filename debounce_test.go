@@ -0,0 +1,98 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebouncer(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+	called := make(chan struct{}, 10)
+	deb := mockable.NewDebouncer(clock, time.Second, func() { called <- struct{}{} })
+	defer deb.Stop()
+
+	require.False(deb.Pending())
+
+	deb.Call()
+	deb.Call()
+	deb.Call()
+	require.True(deb.Pending())
+	require.Equal([]time.Duration{time.Second, time.Second, time.Second}, derefDurations(clock.CloneResetArg()))
+
+	clock.ExhaustCh()
+	clock.Send()
+	<-called
+	require.False(deb.Pending())
+
+	// The fire without pending Call does nothing.
+	clock.Send()
+	select {
+	case <-called:
+		t.Fatal("called without Call")
+	case <-time.After(time.Millisecond):
+	}
+
+	deb.Call()
+	require.True(deb.Flush())
+	<-called
+	require.False(deb.Flush())
+
+	deb.Call()
+	deb.Stop()
+	deb.Call()
+	require.False(deb.Pending())
+	require.Len(called, 0)
+}
+
+func TestDebouncer_Stop_from_callback(t *testing.T) {
+	require := require.New(t)
+
+	for _, flush := range []bool{false, true} {
+		clock := mockable.NewClockFake(time.Now())
+		stopped := make(chan struct{})
+		var deb *mockable.Debouncer
+		deb = mockable.NewDebouncer(clock, time.Second, func() {
+			// Stop must not wait for this very callback.
+			deb.Stop()
+			close(stopped)
+		})
+
+		deb.Call()
+		if flush {
+			require.True(deb.Flush())
+		} else {
+			clock.Send()
+		}
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatalf("Stop from the callback deadlocked, flush = %t", flush)
+		}
+		deb.Call()
+		require.False(deb.Pending())
+		deb.Stop()
+	}
+}
+
+func TestDebouncer_real(t *testing.T) {
+	called := make(chan struct{})
+	deb := mockable.NewDebouncer(mockable.NewClockReal(), time.Millisecond, func() { close(called) })
+	defer deb.Stop()
+	deb.Call()
+	<-called
+}
+
+func derefDurations(durs []*time.Duration) []time.Duration {
+	out := make([]time.Duration, 0, len(durs))
+	for _, d := range durs {
+		if d != nil {
+			out = append(out, *d)
+		}
+	}
+	return out
+}
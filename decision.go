@@ -0,0 +1,235 @@
+package mockable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DecisionKind is a kind of Decision.
+type DecisionKind int
+
+const (
+	// DecisionOrder is the choice of which timer fires first among those sharing a deadline, made by the chaos mode.
+	// N is the index of the chosen timer among them, in creation order.
+	DecisionOrder DecisionKind = iota
+	// DecisionLatency is the latency added to a Reset by the chaos mode. N is the latency in nanoseconds.
+	DecisionLatency
+	// DecisionAdvance is a time jump made by the auto-advance mode. At is the target.
+	DecisionAdvance
+)
+
+func (k DecisionKind) String() string {
+	switch k {
+	case DecisionOrder:
+		return "order"
+	case DecisionLatency:
+		return "latency"
+	case DecisionAdvance:
+		return "advance"
+	}
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k DecisionKind) MarshalText() ([]byte, error) {
+	s := k.String()
+	if s == "unknown" {
+		return nil, fmt.Errorf("mockable: unknown DecisionKind %d", int(k))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *DecisionKind) UnmarshalText(text []byte) error {
+	for _, c := range []DecisionKind{DecisionOrder, DecisionLatency, DecisionAdvance} {
+		if c.String() == string(text) {
+			*k = c
+			return nil
+		}
+	}
+	return fmt.Errorf("mockable: unknown DecisionKind %q", string(text))
+}
+
+// Decision is a nondeterministic choice made by VirtualClock.
+type Decision struct {
+	Kind DecisionKind
+	N    int64
+	At   time.Time
+}
+
+// ErrReplayDiverged is returned from VirtualClock.ReplayErr when the run deviated from the replayed decisions.
+var ErrReplayDiverged = errors.New("mockable: replay diverged")
+
+// ReplayDecisions makes VirtualClock take decisions from log, usually obtained from Decisions of a failing run,
+// instead of drawing them from the chaos generator or observing the auto-advance timing.
+//
+// Decisions of each kind are consumed in their recorded order independently of other kinds,
+// so a run arming timers in the same order makes the same choices.
+// Once decisions of a kind are exhausted, or the run diverges from the log
+// (see ReplayErr), VirtualClock falls back to its usual behavior.
+//
+// In the auto-advance mode, each recorded jump is made when the detector reports quiescence
+// and the earliest deadline equals the recorded target, so it waits for the code under test
+// to arm the same timer it had armed in the recorded run.
+func ReplayDecisions(log []Decision) VirtualClockOption {
+	return func(v *VirtualClock) {
+		v.replay = make(map[DecisionKind][]Decision)
+		for _, d := range log {
+			v.replay[d.Kind] = append(v.replay[d.Kind], d)
+		}
+	}
+}
+
+// Decisions returns the decisions made so far by the chaos and the auto-advance modes, in the order they were made.
+func (v *VirtualClock) Decisions() []Decision {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	out := make([]Decision, len(v.decisions))
+	copy(out, v.decisions)
+	return out
+}
+
+// ReplayErr returns an error wrapping ErrReplayDiverged if the run has deviated from decisions
+// given by ReplayDecisions, or nil otherwise.
+func (v *VirtualClock) ReplayErr() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.replayErr
+}
+
+// LogDecisionsOnFailure logs decisions made by v when t fails,
+// in the form accepted by ParseDecisions, so that the run can be replayed with ReplayDecisions.
+func LogDecisionsOnFailure(t testing.TB, v *VirtualClock) {
+	t.Helper()
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+		t.Logf("mockable: VirtualClock decisions: %s", FormatDecisions(v.Decisions()))
+	})
+}
+
+// FormatDecisions formats log in a compact single-line form, which ParseDecisions parses.
+func FormatDecisions(log []Decision) string {
+	parts := make([]string, len(log))
+	for i, d := range log {
+		switch d.Kind {
+		case DecisionAdvance:
+			parts[i] = fmt.Sprintf("%s:%d", d.Kind, d.At.UnixNano())
+		default:
+			parts[i] = fmt.Sprintf("%s:%d", d.Kind, d.N)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseDecisions parses s formatted by FormatDecisions.
+// Targets of DecisionAdvance are parsed in UTC.
+func ParseDecisions(s string) ([]Decision, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	var out []Decision
+	for _, part := range strings.Split(s, ",") {
+		kind, num, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("mockable: invalid decision %q", part)
+		}
+		var d Decision
+		if err := d.Kind.UnmarshalText([]byte(kind)); err != nil {
+			return nil, err
+		}
+		var n int64
+		if _, err := fmt.Sscan(num, &n); err != nil {
+			return nil, fmt.Errorf("mockable: invalid decision %q: %w", part, err)
+		}
+		if d.Kind == DecisionAdvance {
+			d.At = time.Unix(0, n).UTC()
+		} else {
+			d.N = n
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// nextReplay returns the next replayed decision of kind without consuming it. v.mu must be held.
+func (v *VirtualClock) nextReplay(kind DecisionKind) (Decision, bool) {
+	if v.replayErr != nil || len(v.replay[kind]) == 0 {
+		return Decision{}, false
+	}
+	return v.replay[kind][0], true
+}
+
+// consumeReplay consumes the next replayed decision of kind and records it. v.mu must be held.
+func (v *VirtualClock) consumeReplay(kind DecisionKind) {
+	v.decisions = append(v.decisions, v.replay[kind][0])
+	v.replay[kind] = v.replay[kind][1:]
+}
+
+// diverge stops replaying. v.mu must be held.
+func (v *VirtualClock) diverge(format string, args ...any) {
+	v.replayErr = fmt.Errorf("%w: "+format, append([]any{ErrReplayDiverged}, args...)...)
+}
+
+// pick chooses the index of the timer to fire first among n timers sharing a deadline. v.mu must be held.
+func (v *VirtualClock) pick(n int) int {
+	if d, ok := v.nextReplay(DecisionOrder); ok {
+		if d.N < int64(n) {
+			v.consumeReplay(DecisionOrder)
+			return int(d.N)
+		}
+		v.diverge("order %d out of %d timers", d.N, n)
+	}
+	if v.chaos == nil {
+		return 0
+	}
+	i := v.chaos.Intn(n)
+	v.decisions = append(v.decisions, Decision{Kind: DecisionOrder, N: int64(i)})
+	return i
+}
+
+// latency returns the latency added to a Reset. v.mu must be held.
+func (v *VirtualClock) latency() time.Duration {
+	if d, ok := v.nextReplay(DecisionLatency); ok {
+		v.consumeReplay(DecisionLatency)
+		return time.Duration(d.N)
+	}
+	if v.chaos == nil || v.maxLatency <= 0 {
+		return 0
+	}
+	l := time.Duration(v.chaos.Int63n(int64(v.maxLatency) + 1))
+	v.decisions = append(v.decisions, Decision{Kind: DecisionLatency, N: int64(l)})
+	return l
+}
+
+// autoAdvanceStep makes a jump of the auto-advance mode, once the detector has reported quiescence.
+func (v *VirtualClock) autoAdvanceStep() {
+	v.mu.Lock()
+	defer v.unlock()
+	next, ok := v.next()
+	if d, replaying := v.nextReplay(DecisionAdvance); replaying {
+		switch {
+		case !ok || next.After(d.At):
+			// The code under test has not armed the timer yet.
+			return
+		case next.Before(d.At):
+			v.diverge("earliest deadline %s is before the recorded jump to %s", next, d.At)
+		default:
+			if v.pauseAt == nil {
+				v.consumeReplay(DecisionAdvance)
+				v.advanceTo(next)
+			}
+			return
+		}
+	}
+	if !ok || v.pauseAt != nil {
+		return
+	}
+	v.decisions = append(v.decisions, Decision{Kind: DecisionAdvance, At: next})
+	v.advanceTo(next)
+}
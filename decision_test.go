@@ -0,0 +1,126 @@
+package mockable_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func chaosRun(options ...mockable.VirtualClockOption) (order []uint64, v *mockable.VirtualClock) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	options = append(options, mockable.WithFireHook(func(t *mockable.VirtualTimer, at time.Time) {
+		order = append(order, t.ID())
+	}))
+	v = mockable.NewVirtualClock(start, options...)
+	for i := 0; i < 8; i++ {
+		v.NewTimer().Reset(time.Second)
+	}
+	v.Advance(time.Hour)
+	return order, v
+}
+
+func TestVirtualClock_decisions(t *testing.T) {
+	require := require.New(t)
+
+	order, v := chaosRun(mockable.WithChaos(7, 2*time.Nanosecond))
+	decisions := v.Decisions()
+	var kinds []mockable.DecisionKind
+	for _, d := range decisions {
+		kinds = append(kinds, d.Kind)
+	}
+	require.Equal(8, countKind(kinds, mockable.DecisionLatency))
+	require.Equal(8, countKind(kinds, mockable.DecisionOrder))
+
+	// Without chaos, no decision is made.
+	_, plain := chaosRun()
+	require.Empty(plain.Decisions())
+
+	// Another seed, or no chaos at all, reproduces the run with the decisions.
+	log, err := mockable.ParseDecisions(mockable.FormatDecisions(decisions))
+	require.NoError(err)
+	require.Equal(decisions, log)
+	for _, options := range [][]mockable.VirtualClockOption{
+		{mockable.WithChaos(8, 2*time.Nanosecond), mockable.ReplayDecisions(log)},
+		{mockable.ReplayDecisions(log)},
+	} {
+		replayed, rv := chaosRun(options...)
+		require.Equal(order, replayed)
+		require.Equal(decisions, rv.Decisions())
+		require.NoError(rv.ReplayErr())
+	}
+
+	// A decision not applicable to the run makes the replay diverge.
+	_, rv := chaosRun(mockable.ReplayDecisions([]mockable.Decision{{Kind: mockable.DecisionOrder, N: 100}}))
+	require.True(errors.Is(rv.ReplayErr(), mockable.ErrReplayDiverged))
+
+	_, err = mockable.ParseDecisions("order:1,jitter:2")
+	require.Error(err)
+}
+
+func countKind(kinds []mockable.DecisionKind, kind mockable.DecisionKind) int {
+	var n int
+	for _, k := range kinds {
+		if k == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestVirtualClock_decisions_auto_advance(t *testing.T) {
+	require := require.New(t)
+
+	run := func(options ...mockable.VirtualClockOption) *mockable.VirtualClock {
+		start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+		options = append(options, mockable.WithAutoAdvance(mockable.IdleFor(time.Millisecond)))
+		v := mockable.NewVirtualClock(start, options...)
+		defer v.Close()
+
+		var wg sync.WaitGroup
+		for _, d := range []time.Duration{time.Minute, 90 * time.Second} {
+			wg.Add(1)
+			go func(d time.Duration) {
+				defer wg.Done()
+				timer := v.NewTimer()
+				for i := 0; i < 3; i++ {
+					if err := mockable.SleepContext(context.Background(), timer, d); err != nil {
+						panic(err)
+					}
+				}
+			}(d)
+		}
+		wg.Wait()
+		return v
+	}
+
+	recorded := run().Decisions()
+	require.NotEmpty(recorded)
+	for _, d := range recorded {
+		require.Equal(mockable.DecisionAdvance, d.Kind)
+	}
+
+	replayed := run(mockable.ReplayDecisions(recorded))
+	require.NoError(replayed.ReplayErr())
+	require.Equal(recorded, replayed.Decisions())
+}
+
+func TestLogDecisionsOnFailure(t *testing.T) {
+	require := require.New(t)
+
+	_, v := chaosRun(mockable.WithChaos(1, time.Nanosecond))
+	tb := &fakeTB{TB: t}
+	mockable.LogDecisionsOnFailure(tb, v)
+
+	tb.cleanups[0]()
+	require.Empty(tb.logs)
+
+	tb.failed = true
+	tb.cleanups[0]()
+	require.Len(tb.logs, 1)
+	require.Contains(tb.logs[0], mockable.FormatDecisions(v.Decisions()))
+}
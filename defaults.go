@@ -0,0 +1,27 @@
+package mockable
+
+// NewClock returns a Clock created by DefaultClockFactory.
+//
+// It is a stopgap for code not yet taking a Clock by injection.
+// In normal builds it returns ClockReal. Building with the mockable_fake tag, e.g. go test -tags mockable_fake,
+// makes the initial default factory a VirtualClock, so that clocks created deep inside the code
+// are driven by the test through DefaultVirtualClock.
+func NewClock() Clock {
+	return DefaultClockFactory().NewClock()
+}
+
+// NewNower returns the Nower of DefaultClockFactory if it implements Nower, as VirtualClock does,
+// or NowerReal otherwise. See NewClock for build tags.
+func NewNower() Nower {
+	if n, ok := DefaultClockFactory().(Nower); ok {
+		return n
+	}
+	return NowerReal{}
+}
+
+// DefaultVirtualClock returns DefaultClockFactory if it is a VirtualClock,
+// which is the case in builds with the mockable_fake tag unless the default is replaced.
+func DefaultVirtualClock() (v *VirtualClock, ok bool) {
+	v, ok = DefaultClockFactory().(*VirtualClock)
+	return v, ok
+}
@@ -0,0 +1,13 @@
+//go:build mockable_fake
+
+package mockable
+
+import "time"
+
+// FakeDefaults reports whether the package is built with the mockable_fake build tag,
+// where NewClock and NewNower return fakes.
+const FakeDefaults = true
+
+func initialClockFactory() ClockFactory {
+	return NewVirtualClock(time.Now())
+}
@@ -0,0 +1,11 @@
+//go:build !mockable_fake
+
+package mockable
+
+// FakeDefaults reports whether the package is built with the mockable_fake build tag,
+// where NewClock and NewNower return fakes.
+const FakeDefaults = false
+
+func initialClockFactory() ClockFactory {
+	return ClockFactoryReal{}
+}
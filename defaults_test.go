@@ -0,0 +1,35 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClock(t *testing.T) {
+	require := require.New(t)
+
+	_, isVirtual := mockable.DefaultVirtualClock()
+	require.Equal(mockable.FakeDefaults, isVirtual)
+	if !mockable.FakeDefaults {
+		require.IsType(&mockable.ClockReal{}, mockable.NewClock())
+		require.IsType(mockable.NowerReal{}, mockable.NewNower())
+	}
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	prev := mockable.SetDefaultClockFactory(v)
+	defer mockable.SetDefaultClockFactory(prev)
+
+	got, ok := mockable.DefaultVirtualClock()
+	require.True(ok)
+	require.Same(v, got)
+
+	clock := mockable.NewClock()
+	clock.Reset(time.Minute)
+	v.Advance(time.Minute)
+	require.Equal(start.Add(time.Minute), <-clock.C())
+	require.Equal(start.Add(time.Minute), mockable.NewNower().Now())
+}
@@ -0,0 +1,252 @@
+package mockable
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DelayQueueConfig configures DelayQueue. Zero fields are replaced with defaults.
+type DelayQueueConfig struct {
+	// Tick is the time span covered by a single slot of the timer wheel. Defaults to 1ms.
+	Tick time.Duration
+	// Slots is the number of slots of the timer wheel. Defaults to 1024.
+	Slots int
+}
+
+// DelayQueue is a queue of items each of which becomes available at its own time.
+//
+// Pending items are kept in a timer wheel covering Tick * Slots from the earliest item,
+// and items beyond that are kept in an overflow heap and moved into the wheel as it turns,
+// so Push is O(1) for near items regardless of how many items are pending.
+// Items due at the same time are popped in the order they were pushed.
+//
+// A single Clock is used to wait for the earliest item, so a DelayQueue is fully
+// controllable with ClockFake: each Send makes the earliest pending item due.
+type DelayQueue[T any] struct {
+	mu     sync.Mutex
+	popMu  sync.Mutex
+	clock  Clock
+	tick   time.Duration
+	origin time.Time
+	seq    uint64
+	len    int
+
+	wheel    [][]delayItem[T]
+	cursor   int64
+	overflow delayHeap[T]
+
+	wake chan struct{}
+}
+
+type delayItem[T any] struct {
+	item T
+	at   time.Time
+	seq  uint64
+}
+
+func (i delayItem[T]) before(j delayItem[T]) bool {
+	if !i.at.Equal(j.at) {
+		return i.at.Before(j.at)
+	}
+	return i.seq < j.seq
+}
+
+type delayHeap[T any] []delayItem[T]
+
+func (h delayHeap[T]) Len() int           { return len(h) }
+func (h delayHeap[T]) Less(i, j int) bool { return h[i].before(h[j]) }
+func (h delayHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *delayHeap[T]) Push(x any)        { *h = append(*h, x.(delayItem[T])) }
+func (h *delayHeap[T]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	old[len(old)-1] = delayItem[T]{}
+	*h = old[:len(old)-1]
+	return x
+}
+
+// NewDelayQueue returns an empty DelayQueue configured by config.
+// clock is exclusively used by the DelayQueue.
+func NewDelayQueue[T any](clock Clock, config DelayQueueConfig) *DelayQueue[T] {
+	if config.Tick <= 0 {
+		config.Tick = time.Millisecond
+	}
+	if config.Slots <= 0 {
+		config.Slots = 1024
+	}
+	return &DelayQueue[T]{
+		clock:  clock,
+		tick:   config.Tick,
+		origin: clock.Now(),
+		wheel:  make([][]delayItem[T], config.Slots),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+func (q *DelayQueue[T]) slotOf(t time.Time) int64 {
+	n := int64(t.Sub(q.origin) / q.tick)
+	if n < q.cursor {
+		return q.cursor
+	}
+	return n
+}
+
+func (q *DelayQueue[T]) slot(n int64) *[]delayItem[T] {
+	return &q.wheel[n%int64(len(q.wheel))]
+}
+
+// place puts i into the wheel or the overflow heap. q.mu must be held.
+func (q *DelayQueue[T]) place(i delayItem[T]) {
+	n := q.slotOf(i.at)
+	if n < q.cursor+int64(len(q.wheel)) {
+		s := q.slot(n)
+		*s = append(*s, i)
+		return
+	}
+	heap.Push(&q.overflow, i)
+}
+
+// turn moves the wheel to the slot n and moves overflowing items now in its range into it.
+// q.mu must be held.
+func (q *DelayQueue[T]) turn(n int64) {
+	q.cursor = n
+	end := q.cursor + int64(len(q.wheel))
+	for len(q.overflow) > 0 && q.slotOf(q.overflow[0].at) < end {
+		q.place(heap.Pop(&q.overflow).(delayItem[T]))
+	}
+}
+
+// earliest returns the slot and the index within it of the earliest item.
+// It turns the wheel to that slot as a side effect. q.mu must be held.
+func (q *DelayQueue[T]) earliest() (slot *[]delayItem[T], idx int, ok bool) {
+	if q.len == 0 {
+		return nil, 0, false
+	}
+	for {
+		for n := q.cursor; n < q.cursor+int64(len(q.wheel)); n++ {
+			s := q.slot(n)
+			if len(*s) == 0 {
+				continue
+			}
+			if n != q.cursor {
+				q.turn(n)
+			}
+			idx := 0
+			for i := range *s {
+				if (*s)[i].before((*s)[idx]) {
+					idx = i
+				}
+			}
+			return s, idx, true
+		}
+		// The wheel is empty; jump to the earliest overflowing item.
+		q.turn(q.slotOf(q.overflow[0].at))
+	}
+}
+
+// Push adds item to q, which becomes available at fireAt.
+func (q *DelayQueue[T]) Push(item T, fireAt time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := delayItem[T]{item: item, at: fireAt, seq: q.seq}
+	q.seq++
+
+	var isEarliest bool
+	if s, idx, ok := q.earliest(); ok {
+		isEarliest = i.before((*s)[idx])
+	} else {
+		isEarliest = true
+	}
+
+	q.place(i)
+	q.len++
+
+	if isEarliest {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Len returns the number of pending items, including those not yet due.
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.len
+}
+
+// popDue removes and returns the earliest item if it is due.
+// Otherwise it returns the time the earliest item becomes due, or ok == false if q is empty.
+// q.mu must be held.
+func (q *DelayQueue[T]) popDue() (item T, popped bool, at time.Time, ok bool) {
+	s, idx, ok := q.earliest()
+	if !ok {
+		return item, false, time.Time{}, false
+	}
+	i := (*s)[idx]
+	if i.at.After(q.clock.Now()) {
+		return item, false, i.at, true
+	}
+	last := len(*s) - 1
+	copy((*s)[idx:], (*s)[idx+1:])
+	(*s)[last] = delayItem[T]{}
+	*s = (*s)[:last]
+	q.len--
+	return i.item, true, i.at, true
+}
+
+// TryPop removes and returns the earliest item if it is due, without blocking.
+func (q *DelayQueue[T]) TryPop() (item T, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item, ok, _, _ = q.popDue()
+	return item, ok
+}
+
+// Pop removes and returns the earliest item, blocking until it becomes due.
+// Items pushed while waiting are taken into account.
+// Concurrent callers are served one at a time.
+// If ctx is done before an item becomes due, Pop returns ctx.Err().
+func (q *DelayQueue[T]) Pop(ctx context.Context) (T, error) {
+	q.popMu.Lock()
+	defer q.popMu.Unlock()
+
+	for {
+		q.mu.Lock()
+		// Pushes made so far are taken into account below.
+		select {
+		case <-q.wake:
+		default:
+		}
+		item, popped, at, ok := q.popDue()
+		q.mu.Unlock()
+
+		if popped {
+			return item, nil
+		}
+
+		var timerC <-chan time.Time
+		if ok {
+			q.clock.Reset(at.Sub(q.clock.Now()))
+			timerC = q.clock.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			if ok {
+				q.clock.Stop()
+			}
+			var zero T
+			return zero, ctx.Err()
+		case <-q.wake:
+			if ok {
+				q.clock.Stop()
+			}
+		case <-timerC:
+		}
+	}
+}
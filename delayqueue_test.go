@@ -0,0 +1,106 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayQueue(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	q := mockable.NewDelayQueue[string](clock, mockable.DelayQueueConfig{Tick: time.Second, Slots: 8})
+
+	// Some of items are beyond the wheel and some share a slot.
+	q.Push("c", now.Add(time.Hour))
+	q.Push("a", now.Add(2500*time.Millisecond))
+	q.Push("b", now.Add(2*time.Second))
+	q.Push("d", now.Add(time.Hour))
+	q.Push("e", now.Add(25*time.Hour))
+	q.Push("past", now.Add(-time.Minute))
+	require.Equal(6, q.Len())
+
+	item, ok := q.TryPop()
+	require.True(ok)
+	require.Equal("past", item)
+	_, ok = q.TryPop()
+	require.False(ok)
+
+	type result struct {
+		item string
+		err  error
+	}
+	pop := func() <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			item, err := q.Pop(context.Background())
+			ch <- result{item, err}
+		}()
+		return ch
+	}
+
+	for _, want := range []struct {
+		item string
+		wait time.Duration
+	}{
+		{"b", 2 * time.Second},
+		{"a", 500*time.Millisecond - 1},
+		{"c", time.Hour - 2500*time.Millisecond - 1},
+		{"d", 0},
+		{"e", 24*time.Hour - 1},
+	} {
+		ch := pop()
+		if want.wait > 0 {
+			require.Equal(want.wait, <-clock.ResetCh)
+			clock.Send()
+		}
+		r := <-ch
+		require.NoError(r.err)
+		require.Equal(want.item, r.item)
+	}
+	require.Equal(0, q.Len())
+}
+
+func TestDelayQueue_push_while_waiting(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	q := mockable.NewDelayQueue[int](clock, mockable.DelayQueueConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan int, 1)
+	go func() {
+		item, _ := q.Pop(ctx)
+		ch <- item
+	}()
+
+	q.Push(1, now.Add(time.Minute))
+	require.Equal(time.Minute, <-clock.ResetCh)
+
+	// An earlier item re-arms the clock.
+	q.Push(2, now.Add(time.Second))
+	<-clock.StopCh
+	require.Equal(time.Second, <-clock.ResetCh)
+
+	clock.Send()
+	require.Equal(2, <-ch)
+	require.Equal(1, q.Len())
+
+	errCh := make(chan error)
+	go func() {
+		_, err := q.Pop(ctx)
+		errCh <- err
+	}()
+	<-clock.ResetCh
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+	require.Equal(1, q.Len())
+}
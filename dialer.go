@@ -0,0 +1,149 @@
+package mockable
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// The Dialer is a mockable interface of net.Dialer.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+var _ Dialer = (*DialerReal)(nil)
+
+// DialerReal is an implementation of the Dialer interface.
+// It only wraps net.Dialer. The zero value uses zero value net.Dialer.
+type DialerReal struct {
+	Dialer net.Dialer
+}
+
+func (d *DialerReal) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return d.Dialer.DialContext(ctx, network, address)
+}
+
+var _ Dialer = (*DialerFake)(nil)
+
+// DialerFake is an implementation of the Dialer interface which returns in-memory connections.
+//
+// Addresses must be registered by Listen before dialing;
+// dialing an unknown address fails with ECONNREFUSED.
+// Each successful DialContext returns one end of a net.Pipe
+// and sends the other end to the channel returned from Listen.
+//
+// If Clock is set, connection establishment takes Delay measured by the Clock.
+// Timeout and the deadline of the context passed to DialContext are also measured by the Clock,
+// so timeout paths can be tested deterministically with ClockFake.
+type DialerFake struct {
+	mu        sync.Mutex
+	listeners map[dialerKey]chan net.Conn
+
+	// Clock is used to measure Delay and timeouts. Both are ignored if Clock is nil.
+	Clock Clock
+	// Delay is a time to establish a connection.
+	Delay time.Duration
+	// Timeout is the maximum amount of time a dial will wait for a connect to complete.
+	// Zero means no timeout, as is for net.Dialer.
+	Timeout time.Duration
+}
+
+type dialerKey struct {
+	network string
+	address string
+}
+
+// NewDialerFake returns a DialerFake without any listener.
+func NewDialerFake() *DialerFake {
+	return &DialerFake{
+		listeners: make(map[dialerKey]chan net.Conn),
+	}
+}
+
+// Listen registers address on network and returns a channel
+// to which the server side of each established connection is sent.
+// Calling Listen for an already registered address returns the same channel.
+func (d *DialerFake) Listen(network, address string) <-chan net.Conn {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := dialerKey{network, address}
+	ch, ok := d.listeners[key]
+	if !ok {
+		ch = make(chan net.Conn)
+		d.listeners[key] = ch
+	}
+	return ch
+}
+
+// Unlisten unregisters address on network.
+func (d *DialerFake) Unlisten(network, address string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.listeners, dialerKey{network, address})
+}
+
+func (d *DialerFake) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	opErr := func(err error) error {
+		return &net.OpError{Op: "dial", Net: network, Err: err}
+	}
+
+	if err := waitClock(ctx, d.Clock, d.Delay, d.Timeout); err != nil {
+		return nil, opErr(err)
+	}
+
+	d.mu.Lock()
+	ch, ok := d.listeners[dialerKey{network, address}]
+	d.mu.Unlock()
+	if !ok {
+		return nil, opErr(syscall.ECONNREFUSED)
+	}
+
+	client, server := net.Pipe()
+	select {
+	case ch <- server:
+		return client, nil
+	case <-ctx.Done():
+		_ = client.Close()
+		_ = server.Close()
+		return nil, opErr(ctx.Err())
+	}
+}
+
+// waitClock waits for delay measured by clock.
+// It returns os.ErrDeadlineExceeded if timeout or the deadline of ctx comes first
+// or ctx.Err() if ctx is cancelled.
+// If clock is nil, it only checks ctx.
+func waitClock(ctx context.Context, clock Clock, delay, timeout time.Duration) error {
+	if clock == nil {
+		return ctx.Err()
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := deadline.Sub(clock.Now()); timeout <= 0 || remaining < timeout {
+			timeout = remaining
+			if timeout <= 0 {
+				return os.ErrDeadlineExceeded
+			}
+		}
+	}
+
+	timedOut := timeout > 0 && timeout <= delay
+	wait := delay
+	if timedOut {
+		wait = timeout
+	}
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	if err := SleepContext(ctx, clock, wait); err != nil {
+		return err
+	}
+	if timedOut {
+		return os.ErrDeadlineExceeded
+	}
+	return nil
+}
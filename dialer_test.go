@@ -0,0 +1,126 @@
+package mockable_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialerReal(t *testing.T) {
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("hello"))
+		_ = conn.Close()
+	}()
+
+	var d mockable.Dialer = &mockable.DialerReal{}
+	conn, err := d.DialContext(context.Background(), "tcp", ln.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+
+	bin, err := io.ReadAll(conn)
+	require.NoError(err)
+	require.Equal("hello", string(bin))
+}
+
+func TestDialerFake(t *testing.T) {
+	require := require.New(t)
+
+	d := mockable.NewDialerFake()
+
+	_, err := d.DialContext(context.Background(), "tcp", "example.com:80")
+	require.ErrorIs(err, syscall.ECONNREFUSED)
+
+	accepted := d.Listen("tcp", "example.com:80")
+	go func() {
+		conn := <-accepted
+		_, _ = conn.Write([]byte("hello"))
+		_ = conn.Close()
+	}()
+
+	conn, err := d.DialContext(context.Background(), "tcp", "example.com:80")
+	require.NoError(err)
+	bin, err := io.ReadAll(conn)
+	require.NoError(err)
+	require.Equal("hello", string(bin))
+
+	d.Unlisten("tcp", "example.com:80")
+	_, err = d.DialContext(context.Background(), "tcp", "example.com:80")
+	require.ErrorIs(err, syscall.ECONNREFUSED)
+}
+
+func TestDialerFake_clock(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+	d := mockable.NewDialerFake()
+	d.Clock = clock
+	d.Delay = 2 * time.Second
+	accepted := d.Listen("tcp", "example.com:80")
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	dial := func(ctx context.Context) chan result {
+		ch := make(chan result)
+		go func() {
+			conn, err := d.DialContext(ctx, "tcp", "example.com:80")
+			ch <- result{conn, err}
+		}()
+		return ch
+	}
+
+	resultCh := dial(context.Background())
+	require.Equal(2*time.Second, <-clock.ResetCh)
+	clock.Send()
+	server := <-accepted
+	r := <-resultCh
+	require.NoError(r.err)
+	_ = r.conn.Close()
+	_ = server.Close()
+
+	d.Timeout = time.Second
+	resultCh = dial(context.Background())
+	require.Equal(time.Second, <-clock.ResetCh)
+	clock.Send()
+	r = <-resultCh
+	require.ErrorIs(r.err, os.ErrDeadlineExceeded)
+	var netErr net.Error
+	require.True(errors.As(r.err, &netErr))
+	require.True(netErr.Timeout())
+
+	// The deadline of ctx is measured against the Clock.
+	d.Timeout = 0
+	ctx, cancel := context.WithDeadline(context.Background(), clock.Now().Add(500*time.Millisecond))
+	defer cancel()
+	resultCh = dial(ctx)
+	require.Equal(500*time.Millisecond, <-clock.ResetCh)
+	clock.Send()
+	r = <-resultCh
+	require.ErrorIs(r.err, os.ErrDeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	resultCh = dial(ctx)
+	<-clock.ResetCh
+	cancel()
+	r = <-resultCh
+	require.ErrorIs(r.err, context.Canceled)
+}
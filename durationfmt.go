@@ -0,0 +1,208 @@
+package mockable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationFormatter formats durations and times relative to now for humans.
+//
+// Use this in UI-facing code instead of formatting time.Since(t) directly,
+// so relative-time strings become deterministic in tests.
+type DurationFormatter interface {
+	// Format formats d in a humanized form, e.g. "1h 5m".
+	Format(d time.Duration) string
+	// Relative formats t relative to the current time, e.g. "3m ago" or "in 2h".
+	Relative(t time.Time) string
+	// Parse parses a duration formatted by Format, or accepted by time.ParseDuration.
+	Parse(s string) (time.Duration, error)
+}
+
+var (
+	_ DurationFormatter = DurationFormatterReal{}
+	_ DurationFormatter = DurationFormatterFake{}
+)
+
+// DurationLocale holds words used by DurationFormatterReal.
+type DurationLocale struct {
+	Day, Hour, Minute, Second string
+	// Separator separates units.
+	Separator string
+	// Ago and In are format strings taking a formatted duration, for past and future times respectively.
+	Ago, In string
+	// JustNow is used for times within a second of now.
+	JustNow string
+}
+
+// DefaultDurationLocale is the locale used when DurationFormatterReal.Locale is nil.
+var DefaultDurationLocale = DurationLocale{
+	Day:       "d",
+	Hour:      "h",
+	Minute:    "m",
+	Second:    "s",
+	Separator: " ",
+	Ago:       "%s ago",
+	In:        "in %s",
+	JustNow:   "just now",
+}
+
+// DurationFormatterReal implements DurationFormatter.
+// Durations are formatted in at most Units largest units down to seconds, truncating the rest.
+// The zero value uses NowerReal, DefaultDurationLocale and 2 units.
+type DurationFormatterReal struct {
+	Nower  Nower
+	Locale *DurationLocale
+	Units  int
+}
+
+func (f DurationFormatterReal) locale() *DurationLocale {
+	if f.Locale == nil {
+		return &DefaultDurationLocale
+	}
+	return f.Locale
+}
+
+// Format implements DurationFormatter.
+func (f DurationFormatterReal) Format(d time.Duration) string {
+	l := f.locale()
+	units := f.Units
+	if units <= 0 {
+		units = 2
+	}
+	var sign string
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	var parts []string
+	// Counts units from the largest non-zero one, so that "1h 0m 5s" is truncated to "1h" with 2 units.
+	counted := 0
+	for _, u := range f.units() {
+		n := d / u.d
+		d -= n * u.d
+		if n == 0 && counted == 0 {
+			continue
+		}
+		if counted == units {
+			break
+		}
+		counted++
+		if n > 0 {
+			parts = append(parts, strconv.FormatInt(int64(n), 10)+u.name)
+		}
+	}
+	if len(parts) == 0 {
+		return "0" + l.Second
+	}
+	return sign + strings.Join(parts, l.Separator)
+}
+
+type durationUnit struct {
+	d    time.Duration
+	name string
+}
+
+func (f DurationFormatterReal) units() []durationUnit {
+	l := f.locale()
+	return []durationUnit{
+		{24 * time.Hour, l.Day},
+		{time.Hour, l.Hour},
+		{time.Minute, l.Minute},
+		{time.Second, l.Second},
+	}
+}
+
+// Relative implements DurationFormatter.
+func (f DurationFormatterReal) Relative(t time.Time) string {
+	nower := f.Nower
+	if nower == nil {
+		nower = NowerReal{}
+	}
+	l := f.locale()
+	d := t.Sub(nower.Now())
+	switch {
+	case d > -time.Second && d < time.Second:
+		return l.JustNow
+	case d < 0:
+		return fmt.Sprintf(l.Ago, f.Format(-d))
+	default:
+		return fmt.Sprintf(l.In, f.Format(d))
+	}
+}
+
+// Parse implements DurationFormatter.
+func (f DurationFormatterReal) Parse(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	l := f.locale()
+	rest := strings.TrimSpace(s)
+	var neg bool
+	if strings.HasPrefix(rest, "-") {
+		neg, rest = true, rest[1:]
+	}
+	var (
+		d      time.Duration
+		parsed bool
+	)
+	for rest != "" {
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, l.Separator))
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("mockable: invalid duration %q", s)
+		}
+		n, err := strconv.ParseInt(rest[:i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("mockable: invalid duration %q: %w", s, err)
+		}
+		rest = rest[i:]
+		var matched string
+		var unit time.Duration
+		for _, u := range f.units() {
+			// The longest matching unit wins, so that "min" is not taken for "m".
+			if u.name != "" && strings.HasPrefix(rest, u.name) && len(u.name) > len(matched) {
+				matched, unit = u.name, u.d
+			}
+		}
+		if matched == "" {
+			return 0, fmt.Errorf("mockable: invalid duration %q: unknown unit", s)
+		}
+		rest = rest[len(matched):]
+		d += time.Duration(n) * unit
+		parsed = true
+	}
+	if !parsed {
+		return 0, fmt.Errorf("mockable: invalid duration %q", s)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// DurationFormatterFake implements DurationFormatter with deterministic, locale-independent output.
+//
+// Format returns d.String(), and Relative returns the offset of t from Now by time.Duration.String,
+// e.g. "-3m0s" for 3 minutes ago, so tests assert on relative-time code without depending on the wording.
+type DurationFormatterFake struct {
+	Now time.Time
+}
+
+// Format implements DurationFormatter.
+func (f DurationFormatterFake) Format(d time.Duration) string {
+	return d.String()
+}
+
+// Relative implements DurationFormatter.
+func (f DurationFormatterFake) Relative(t time.Time) string {
+	return t.Sub(f.Now).String()
+}
+
+// Parse implements DurationFormatter. It only accepts the syntax of time.ParseDuration.
+func (f DurationFormatterFake) Parse(s string) (time.Duration, error) {
+	return time.ParseDuration(s)
+}
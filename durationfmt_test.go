@@ -0,0 +1,75 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationFormatterReal(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(now)
+	f := mockable.DurationFormatterReal{Nower: nower}
+
+	for d, s := range map[time.Duration]string{
+		0:                             "0s",
+		500 * time.Millisecond:        "0s",
+		3 * time.Minute:               "3m",
+		time.Hour + 5*time.Minute + 7: "1h 5m",
+		time.Hour + 5*time.Second:     "1h",
+		26*time.Hour + 30*time.Minute: "1d 2h",
+		-(90 * time.Second):           "-1m 30s",
+		49*time.Hour + 3*time.Minute + 4*time.Second: "2d 1h",
+	} {
+		require.Equal(s, f.Format(d), "%s", d)
+	}
+
+	require.Equal("3m ago", f.Relative(now.Add(-3*time.Minute)))
+	require.Equal("in 2h", f.Relative(now.Add(2*time.Hour+time.Millisecond)))
+	require.Equal("just now", f.Relative(now.Add(-time.Millisecond)))
+
+	for s, d := range map[string]time.Duration{
+		"1d 2h":   26 * time.Hour,
+		"-1m 30s": -(90 * time.Second),
+		"1h30m":   90 * time.Minute,
+		"2d":      48 * time.Hour,
+	} {
+		parsed, err := f.Parse(s)
+		require.NoError(err, s)
+		require.Equal(d, parsed, s)
+	}
+	for _, s := range []string{"", "1x", "h", "1d 2"} {
+		_, err := f.Parse(s)
+		require.Error(err, s)
+	}
+
+	ja := mockable.DurationFormatterReal{
+		Nower: nower,
+		Locale: &mockable.DurationLocale{
+			Day: "日", Hour: "時間", Minute: "分", Second: "秒",
+			Ago: "%s前", In: "%s後", JustNow: "たった今",
+		},
+		Units: 3,
+	}
+	require.Equal("1時間2分3秒前", ja.Relative(now.Add(-(time.Hour + 2*time.Minute + 3*time.Second))))
+	d, err := ja.Parse("1時間2分")
+	require.NoError(err)
+	require.Equal(time.Hour+2*time.Minute, d)
+}
+
+func TestDurationFormatterFake(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	f := mockable.DurationFormatterFake{Now: now}
+	require.Equal("1h5m0s", f.Format(time.Hour+5*time.Minute))
+	require.Equal("-3m0s", f.Relative(now.Add(-3*time.Minute)))
+	d, err := f.Parse("1h")
+	require.NoError(err)
+	require.Equal(time.Hour, d)
+}
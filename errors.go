@@ -0,0 +1,91 @@
+package mockable
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotScheduled is returned from ClockFake.TrySend in the strict mode when the clock is not armed.
+	ErrNotScheduled = errors.New("mockable: clock is not scheduled")
+	// ErrNoReceiver is returned from ClockFake.TrySend under SendError
+	// when nothing receives the value within the grace period.
+	ErrNoReceiver = errors.New("mockable: no receiver for ClockFake.Send")
+	// ErrClosed is returned from operations on closed components, such as Batcher.Add after Close.
+	ErrClosed = errors.New("mockable: closed")
+	// ErrStopped is returned from AfterFunc.Wait when the callback is stopped before it is started,
+	// and from ScriptRun.Wait when playing is stopped before the last step.
+	ErrStopped = errors.New("mockable: stopped")
+	// ErrDiscarded is returned from ClockFake.TrySend when the value in flight is discarded by Reset.
+	ErrDiscarded = errors.New("mockable: fire discarded by Reset")
+)
+
+// ContractViolationError describes a misuse of a fake breaking the contract of the interface it implements,
+// such as Reset on an armed Timer. Fakes in the strict mode panic with it.
+//
+// Recover it in tests to assert on the misuse:
+//
+//	defer func() {
+//		var cv *mockable.ContractViolationError
+//		require.True(t, errors.As(recover().(error), &cv))
+//	}()
+type ContractViolationError struct {
+	// Op is the violating call, e.g. "ClockFake.Reset".
+	Op string
+	// Err describes the violation. It may be a sentinel like ErrNotScheduled.
+	Err error
+	// File and Line locate the violating call outside this package.
+	File string
+	Line int
+}
+
+func (e *ContractViolationError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("mockable: contract violation: %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("mockable: contract violation: %s at %s:%d: %v", e.Op, e.File, e.Line, e.Err)
+}
+
+func (e *ContractViolationError) Unwrap() error {
+	return e.Err
+}
+
+// FireInvariantError reports that a value delivered by ClockFake.Send breaks its FireInvariant,
+// because the current time was moved while the value was in flight.
+type FireInvariantError struct {
+	Invariant FireInvariant
+	Fired     time.Time
+	Now       time.Time
+}
+
+func (e *FireInvariantError) Error() string {
+	rel := "before"
+	if e.Invariant == FireEqualAllowed {
+		rel = "before or equal to"
+	}
+	return fmt.Sprintf(
+		"mockable: fire invariant %q violated: fired %s is not %s Now() %s",
+		e.Invariant, e.Fired.Format(time.RFC3339Nano), rel, e.Now.Format(time.RFC3339Nano),
+	)
+}
+
+// newContractViolation returns a ContractViolationError located at the first caller outside this package.
+func newContractViolation(op string, err error) *ContractViolationError {
+	e := &ContractViolationError{Op: op, Err: err}
+	pc := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(2, pc)])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/ngicks/mockable.") {
+			e.File, e.Line = f.File, f.Line
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	return e
+}
@@ -0,0 +1,68 @@
+package mockable_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func recoverContractViolation(fn func()) (cv *mockable.ContractViolationError) {
+	defer func() {
+		if err, ok := recover().(error); ok {
+			errors.As(err, &cv)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestClockFake_Strict(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now, mockable.WithStrict())
+
+	// Not armed.
+	_, delivered, err := clock.TrySend()
+	require.False(delivered)
+	require.ErrorIs(err, mockable.ErrNotScheduled)
+	require.Equal(now, clock.Now())
+
+	cv := recoverContractViolation(func() { clock.Send() })
+	require.NotNil(cv)
+	require.Equal("ClockFake.Send", cv.Op)
+	require.ErrorIs(cv, mockable.ErrNotScheduled)
+	require.Equal("errors_test.go", filepath.Base(cv.File))
+	require.Greater(cv.Line, 0)
+
+	h := clock.SendAsync()
+	<-h.Done()
+	select {
+	case <-h.Consumed():
+		t.Fatal("consumed")
+	default:
+	}
+
+	clock.Reset(time.Second)
+	<-clock.ResetCh
+
+	// Reset on the armed clock.
+	cv = recoverContractViolation(func() { clock.Reset(time.Minute) })
+	require.NotNil(cv)
+	require.Equal("ClockFake.Reset", cv.Op)
+	require.Equal("errors_test.go", filepath.Base(cv.File))
+	require.Contains(cv.Error(), "errors_test.go")
+
+	// Stop then Reset is the correct use.
+	clock.Stop()
+	<-clock.StopCh
+	clock.Reset(time.Minute)
+	<-clock.ResetCh
+	go func() { <-clock.C() }()
+	require.Equal(now, clock.Send())
+	require.False(clock.Now().Before(now.Add(time.Minute)))
+}
@@ -0,0 +1,124 @@
+package mockable
+
+import (
+	"testing"
+	"time"
+)
+
+// Advancer is a fake time source which can be moved forward, firing timers due meanwhile.
+// ClockFake and VirtualClock implement it.
+type Advancer interface {
+	Nower
+	// Advance moves the time forward by d and returns the number of fired timers.
+	Advance(d time.Duration) int
+}
+
+var (
+	_ Advancer = (*ClockFake)(nil)
+	_ Advancer = (*VirtualClock)(nil)
+)
+
+// EventuallyOption configures Eventually and Never.
+type EventuallyOption func(c *eventuallyConfig)
+
+type eventuallyConfig struct {
+	step  time.Duration
+	yield time.Duration
+}
+
+// WithEventuallyStep sets the virtual time advanced between polls. Defaults to a hundredth of the whole span.
+func WithEventuallyStep(d time.Duration) EventuallyOption {
+	return func(c *eventuallyConfig) {
+		c.step = d
+	}
+}
+
+// WithEventuallyYield sets the real time waited after each step so that goroutines woken by fires
+// can run before the condition is polled again. Defaults to 100µs.
+func WithEventuallyYield(d time.Duration) EventuallyOption {
+	return func(c *eventuallyConfig) {
+		c.yield = d
+	}
+}
+
+func newEventuallyConfig(span time.Duration, options []EventuallyOption) eventuallyConfig {
+	c := eventuallyConfig{step: span / 100, yield: 100 * time.Microsecond}
+	for _, opt := range options {
+		opt(&c)
+	}
+	if c.step <= 0 {
+		c.step = 1
+	}
+	return c
+}
+
+// pollVirtual polls cond, advancing clock by steps until span of virtual time has elapsed.
+// It returns true and the virtual time elapsed so far as soon as cond returns true.
+// stuck is true if it gave up because steps amounting to span did not move the time,
+// e.g. for a VirtualClock paused at a barrier. Steps are summed so that a coarse clock,
+// whose time moves only once per several steps, is not taken as stuck.
+func pollVirtual(clock Advancer, span time.Duration, cond func() bool, options []EventuallyOption) (ok bool, elapsed time.Duration, stuck bool) {
+	c := newEventuallyConfig(span, options)
+	start := clock.Now()
+	end := start.Add(span)
+	var idle time.Duration
+	for {
+		if cond() {
+			return true, clock.Now().Sub(start), false
+		}
+		now := clock.Now()
+		if !now.Before(end) {
+			return false, now.Sub(start), false
+		}
+		step := c.step
+		if rest := end.Sub(now); rest < step {
+			step = rest
+		}
+		clock.Advance(step)
+		if c.yield > 0 {
+			time.Sleep(c.yield)
+		}
+		if clock.Now().After(now) {
+			idle = 0
+			continue
+		}
+		if idle += step; idle >= span {
+			return cond(), now.Sub(start), true
+		}
+	}
+}
+
+// Eventually asserts that cond returns true within the virtual time span, reporting a failure to t otherwise.
+//
+// Unlike testify's Eventually, which waits for real time, it advances clock in steps
+// (see WithEventuallyStep) and polls cond after each step, so conditions depending on
+// timers of hours are checked within milliseconds of real time, and never time out on a slow machine.
+// It returns whether the assertion succeeded.
+// It fails, rather than spins, if advancing does not move the time, as for a VirtualClock paused at a barrier.
+func Eventually(t testing.TB, clock Advancer, within time.Duration, cond func() bool, options ...EventuallyOption) bool {
+	t.Helper()
+	ok, elapsed, stuck := pollVirtual(clock, within, cond, options)
+	switch {
+	case ok:
+	case stuck:
+		t.Errorf("mockable: condition was not satisfied and the virtual time stopped moving after %s of %s", elapsed, within)
+	default:
+		t.Errorf("mockable: condition was not satisfied within %s of virtual time (%s elapsed)", within, elapsed)
+	}
+	return ok
+}
+
+// Never asserts that cond keeps returning false during the virtual time span, reporting a failure to t otherwise.
+// The virtual time is advanced as in Eventually.
+// It returns whether the assertion succeeded.
+func Never(t testing.TB, clock Advancer, during time.Duration, cond func() bool, options ...EventuallyOption) bool {
+	t.Helper()
+	satisfied, elapsed, stuck := pollVirtual(clock, during, cond, options)
+	switch {
+	case satisfied:
+		t.Errorf("mockable: condition was satisfied after %s of virtual time", elapsed)
+	case stuck:
+		t.Errorf("mockable: the virtual time stopped moving after %s of %s", elapsed, during)
+	}
+	return !satisfied && !stuck
+}
@@ -0,0 +1,121 @@
+package mockable_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func sleepThenSet(v *mockable.VirtualClock, d time.Duration) *atomic.Bool {
+	var done atomic.Bool
+	timer := v.NewTimer()
+	go func() {
+		if err := mockable.SleepContext(context.Background(), timer, d); err != nil {
+			panic(err)
+		}
+		done.Store(true)
+	}()
+	return &done
+}
+
+func TestEventually(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	done := sleepThenSet(v, time.Hour)
+
+	realStart := time.Now()
+	require.True(mockable.Eventually(t, v, 2*time.Hour, done.Load, mockable.WithEventuallyStep(time.Minute)))
+	require.Less(time.Since(realStart), time.Second)
+	require.False(v.Now().Before(start.Add(time.Hour)))
+	require.False(v.Now().After(start.Add(time.Hour + 2*time.Minute)))
+
+	tb := &fakeTB{TB: t}
+	v = mockable.NewVirtualClock(start)
+	done = sleepThenSet(v, time.Hour)
+	require.False(mockable.Eventually(tb, v, 30*time.Minute, done.Load))
+	require.True(tb.failed)
+	require.Equal(start.Add(30*time.Minute), v.Now())
+	require.False(done.Load())
+}
+
+func TestNever(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	done := sleepThenSet(v, time.Hour)
+
+	require.True(mockable.Never(t, v, 30*time.Minute, done.Load))
+	require.Equal(start.Add(30*time.Minute), v.Now())
+
+	tb := &fakeTB{TB: t}
+	require.False(mockable.Never(tb, v, time.Hour, done.Load))
+	require.True(tb.failed)
+	require.True(v.Now().Before(start.Add(90 * time.Minute)))
+}
+
+func TestClockFake_Advance(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(start)
+
+	clock.Reset(time.Minute)
+	require.Equal(0, clock.Advance(30*time.Second))
+	require.Equal(start.Add(30*time.Second), clock.Now())
+
+	received := make(chan time.Time, 1)
+	go func() { received <- <-clock.C() }()
+	require.Equal(1, clock.Advance(time.Minute))
+	require.Equal(start.Add(time.Minute), <-received)
+	require.Equal(start.Add(90*time.Second), clock.Now())
+
+	// Not scheduled anymore.
+	require.Equal(0, clock.Advance(time.Hour))
+}
+
+func TestEventually_no_receiver(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Nothing receives the fire, which would block Send forever under SendBlock.
+	clock := mockable.NewClockFake(start, mockable.WithSendGracePeriod(10*time.Millisecond))
+	clock.Reset(time.Minute)
+
+	tb := &fakeTB{TB: t}
+	require.False(mockable.Eventually(tb, clock, time.Hour, func() bool { return false }))
+	require.True(tb.failed)
+	require.Equal(start.Add(time.Hour), clock.Now())
+	// The fire given up expires the clock.
+	require.False(clock.IsScheduled())
+
+	tb = &fakeTB{TB: t}
+	clock.Reset(time.Minute)
+	require.True(mockable.Never(tb, clock, time.Hour, func() bool { return false }))
+	require.False(tb.failed)
+}
+
+func TestEventually_stuck(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	v.AddBarrier(start.Add(10 * time.Minute))
+
+	tb := &fakeTB{TB: t}
+	require.False(mockable.Eventually(tb, v, time.Hour, func() bool { return false }))
+	require.True(tb.failed)
+	require.Contains(tb.logs[0], "stopped moving after 10m0s")
+	require.Equal(start.Add(10*time.Minute), v.Now())
+
+	tb = &fakeTB{TB: t}
+	require.False(mockable.Never(tb, v, time.Hour, func() bool { return false }))
+	require.True(tb.failed)
+	require.Contains(tb.logs[0], "stopped moving")
+}
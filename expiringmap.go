@@ -0,0 +1,131 @@
+package mockable
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ExpiringMap is a map whose entries expire after their TTLs.
+//
+// Expiration is judged against the injected Nower, so with NowerFake or ClockFake
+// expiry is tested by moving the fake time instead of sleeping.
+// Expired entries are invisible to Get and removed lazily,
+// by Sweep, or by a background sweeper started by StartSweeper.
+type ExpiringMap[K comparable, V any] struct {
+	mu      sync.Mutex
+	nower   Nower
+	ttl     time.Duration
+	entries map[K]expiringEntry[V]
+}
+
+type expiringEntry[V any] struct {
+	value    V
+	deadline time.Time
+}
+
+// NewExpiringMap returns an empty ExpiringMap whose entries expire after ttl by default.
+// Non-positive ttl means entries never expire unless set by SetWithTTL.
+func NewExpiringMap[K comparable, V any](nower Nower, ttl time.Duration) *ExpiringMap[K, V] {
+	return &ExpiringMap[K, V]{
+		nower:   nower,
+		ttl:     ttl,
+		entries: make(map[K]expiringEntry[V]),
+	}
+}
+
+// Set sets value for key, expiring after the default TTL.
+func (m *ExpiringMap[K, V]) Set(key K, value V) {
+	m.SetWithTTL(key, value, m.ttl)
+}
+
+// SetWithTTL sets value for key, expiring after ttl.
+// Non-positive ttl means the entry never expires.
+func (m *ExpiringMap[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deadline time.Time
+	if ttl > 0 {
+		deadline = m.nower.Now().Add(ttl)
+	}
+	m.entries[key] = expiringEntry[V]{value: value, deadline: deadline}
+}
+
+func (e expiringEntry[V]) expired(now time.Time) bool {
+	return !e.deadline.IsZero() && !now.Before(e.deadline)
+}
+
+// Get returns the value for key. ok is false if there is no entry or it has expired.
+func (m *ExpiringMap[K, V]) Get(key K) (value V, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return value, false
+	}
+	if e.expired(m.nower.Now()) {
+		delete(m.entries, key)
+		return value, false
+	}
+	return e.value, true
+}
+
+// ExpiresAt returns the time the entry for key expires.
+// expiresAt is zero if the entry never expires. ok is false if there is no live entry.
+func (m *ExpiringMap[K, V]) ExpiresAt(key K) (expiresAt time.Time, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok || e.expired(m.nower.Now()) {
+		return time.Time{}, false
+	}
+	return e.deadline, true
+}
+
+// Delete deletes the entry for key.
+func (m *ExpiringMap[K, V]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// Len returns the number of stored entries, including expired ones not removed yet.
+func (m *ExpiringMap[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// Sweep removes expired entries and returns the number of removed entries.
+func (m *ExpiringMap[K, V]) Sweep() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.nower.Now()
+	var n int
+	for k, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, k)
+			n++
+		}
+	}
+	return n
+}
+
+// StartSweeper starts a goroutine calling Sweep on every tick of ticker with period interval,
+// until ctx is done. The returned channel is closed when the goroutine exits.
+// ticker is exclusively used by the sweeper. See Tick for how ticks are delivered.
+// It panics if interval is not positive.
+func (m *ExpiringMap[K, V]) StartSweeper(ctx context.Context, ticker Ticker, interval time.Duration) <-chan struct{} {
+	if interval <= 0 {
+		panic("non-positive interval for ExpiringMap.StartSweeper")
+	}
+	done := make(chan struct{})
+	ticks := Tick(ctx, ticker, interval)
+	go func() {
+		defer close(done)
+		for range ticks {
+			m.Sweep()
+		}
+	}()
+	return done
+}
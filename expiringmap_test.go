@@ -0,0 +1,92 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiringMap(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(now)
+
+	m := mockable.NewExpiringMap[string, int](nower, 10*time.Second)
+	m.Set("a", 1)
+	m.SetWithTTL("b", 2, time.Minute)
+	m.SetWithTTL("forever", 3, 0)
+
+	v, ok := m.Get("a")
+	require.True(ok)
+	require.Equal(1, v)
+	at, ok := m.ExpiresAt("b")
+	require.True(ok)
+	require.Equal(now.Add(time.Minute), at)
+	at, ok = m.ExpiresAt("forever")
+	require.True(ok)
+	require.True(at.IsZero())
+
+	nower.SetNow(now.Add(10*time.Second - 1))
+	_, ok = m.Get("a")
+	require.True(ok)
+
+	nower.SetNow(now.Add(10 * time.Second))
+	_, ok = m.Get("a")
+	require.False(ok)
+	require.Equal(2, m.Len())
+
+	// Overwriting renews the TTL.
+	m.Set("b", 20)
+	nower.SetNow(now.Add(time.Hour))
+	require.Equal(2, m.Len())
+	require.Equal(1, m.Sweep())
+	require.Equal(1, m.Len())
+	v, ok = m.Get("forever")
+	require.True(ok)
+	require.Equal(3, v)
+
+	m.Delete("forever")
+	require.Equal(0, m.Len())
+}
+
+func TestExpiringMap_sweeper(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	ticker := mockable.NewTickerFake(now)
+
+	m := mockable.NewExpiringMap[string, int](ticker, time.Minute)
+	m.Set("a", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := m.StartSweeper(ctx, ticker, 30*time.Second)
+	require.Equal(30*time.Second, <-ticker.ResetCh)
+
+	ticker.Send()
+	require.Equal(1, m.Len())
+
+	ticker.Send()
+	require.Eventually(func() bool { return m.Len() == 0 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+	<-ticker.StopCh
+}
+
+func TestExpiringMap_StartSweeper_non_positive(t *testing.T) {
+	require := require.New(t)
+
+	m := mockable.NewExpiringMap[string, int](mockable.NewClockFake(time.Now()), time.Minute)
+	ticker := mockable.NewTickerFake(time.Now())
+	for _, interval := range []time.Duration{0, -time.Second} {
+		require.PanicsWithValue("non-positive interval for ExpiringMap.StartSweeper", func() {
+			m.StartSweeper(context.Background(), ticker, interval)
+		})
+	}
+	require.False(ticker.IsRunning())
+}
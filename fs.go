@@ -0,0 +1,376 @@
+package mockable
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+	"time"
+)
+
+// The FS is a mockable interface of a writable file system.
+// It extends fs.FS with operations that modify the file system.
+//
+// Names are slash-separated paths as fs.ValidPath requires.
+type FS interface {
+	fs.FS
+	// Create creates or truncates the named file.
+	Create(name string) (File, error)
+	// WriteFile writes data to the named file, creating it if necessary.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// MkdirAll creates a directory named name, along with any necessary parents.
+	MkdirAll(name string, perm fs.FileMode) error
+	// Remove removes the named file or (empty) directory.
+	Remove(name string) error
+	// Rename renames (moves) oldname to newname.
+	Rename(oldname, newname string) error
+}
+
+// File is a writable file returned from FS.Create.
+type File interface {
+	fs.File
+	io.Writer
+	// Name returns the name of the file as presented to Create.
+	Name() string
+}
+
+var _ FS = (*FSReal)(nil)
+
+// FSReal is an implementation of the FS interface backed by the os package.
+// Every name is resolved relative to Root.
+type FSReal struct {
+	Root string
+}
+
+// NewFSReal returns a FSReal rooted at root.
+func NewFSReal(root string) *FSReal {
+	return &FSReal{Root: root}
+}
+
+func (f *FSReal) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(f.Root, filepath.FromSlash(name)), nil
+}
+
+func (f *FSReal) Open(name string) (fs.File, error) {
+	return os.DirFS(f.Root).Open(name)
+}
+
+func (f *FSReal) Create(name string) (File, error) {
+	p, err := f.join("create", name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Create(p)
+	if err != nil {
+		return nil, err
+	}
+	return &fileReal{File: file, name: name}, nil
+}
+
+// fileReal is an *os.File whose Name is the name presented to FSReal.Create, rather than the host path.
+type fileReal struct {
+	*os.File
+	name string
+}
+
+func (f *fileReal) Name() string {
+	return f.name
+}
+
+func (f *FSReal) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	p, err := f.join("writefile", name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, perm)
+}
+
+func (f *FSReal) MkdirAll(name string, perm fs.FileMode) error {
+	p, err := f.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(p, perm)
+}
+
+func (f *FSReal) Remove(name string) error {
+	p, err := f.join("remove", name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+func (f *FSReal) Rename(oldname, newname string) error {
+	oldp, err := f.join("rename", oldname)
+	if err != nil {
+		return err
+	}
+	newp, err := f.join("rename", newname)
+	if err != nil {
+		return err
+	}
+	return os.Rename(oldp, newp)
+}
+
+var _ FS = (*FSFake)(nil)
+
+// FSFake is an in-memory implementation of the FS interface.
+//
+// Modification times of files and directories are taken from the injected Nower.
+// Slow I/O can be simulated by setting Clock and Latency:
+// every operation then calls Clock.Reset(Latency) and waits until Clock.C() emits,
+// so tests decide when the operation completes by calling ClockFake.Send.
+// Failing I/O can be simulated by setting Fault.
+type FSFake struct {
+	mu    sync.Mutex
+	nower Nower
+	files fstest.MapFS
+
+	// Clock is used to simulate latency. Latency is ignored if Clock is nil.
+	Clock   Clock
+	Latency time.Duration
+	// Fault, if non-nil, is called before every operation with its name ("open", "create", etc.)
+	// and the path. A non-nil error fails the operation with that error.
+	Fault func(op, name string) error
+}
+
+// NewFSFake returns an empty FSFake whose modification times are taken from nower.
+func NewFSFake(nower Nower) *FSFake {
+	return &FSFake{
+		nower: nower,
+		files: fstest.MapFS{},
+	}
+}
+
+func (f *FSFake) before(op, name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if f.Clock != nil && f.Latency > 0 {
+		f.Clock.Reset(f.Latency)
+		<-f.Clock.C()
+	}
+	if f.Fault != nil {
+		if err := f.Fault(op, name); err != nil {
+			return &fs.PathError{Op: op, Path: name, Err: err}
+		}
+	}
+	return nil
+}
+
+// isDir reports whether name is an existing directory. f.mu must be held.
+func (f *FSFake) isDir(name string) bool {
+	if name == "." {
+		return true
+	}
+	file, ok := f.files[name]
+	return ok && file.Mode.IsDir()
+}
+
+// checkParent reports an error if the parent directory of name does not exist. f.mu must be held.
+func (f *FSFake) checkParent(op, name string) error {
+	if !f.isDir(path.Dir(name)) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return nil
+}
+
+func (f *FSFake) Open(name string) (fs.File, error) {
+	if err := f.before("open", name); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// MapFS.Open returns files that keep referencing the *MapFile at the time of the call.
+	// Since writes always replace *MapFile, opened files are consistent snapshots.
+	return f.files.Open(name)
+}
+
+func (f *FSFake) Create(name string) (File, error) {
+	if err := f.before("create", name); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkParent("create", name); err != nil {
+		return nil, err
+	}
+	if f.isDir(name) {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: errors.New("is a directory")}
+	}
+	f.files[name] = &fstest.MapFile{Mode: 0o666, ModTime: f.nower.Now()}
+	return &fakeFile{fsys: f, name: name}, nil
+}
+
+func (f *FSFake) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := f.before("writefile", name); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkParent("writefile", name); err != nil {
+		return err
+	}
+	if f.isDir(name) {
+		return &fs.PathError{Op: "writefile", Path: name, Err: errors.New("is a directory")}
+	}
+	mode := perm
+	if old, ok := f.files[name]; ok {
+		mode = old.Mode
+	}
+	f.files[name] = &fstest.MapFile{
+		Data:    append([]byte(nil), data...),
+		Mode:    mode,
+		ModTime: f.nower.Now(),
+	}
+	return nil
+}
+
+func (f *FSFake) MkdirAll(name string, perm fs.FileMode) error {
+	if err := f.before("mkdir", name); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.nower.Now()
+	for dir := name; dir != "."; dir = path.Dir(dir) {
+		if file, ok := f.files[dir]; ok {
+			if !file.Mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: errors.New("not a directory")}
+			}
+			continue
+		}
+		f.files[dir] = &fstest.MapFile{Mode: fs.ModeDir | perm, ModTime: now}
+	}
+	return nil
+}
+
+func (f *FSFake) Remove(name string) error {
+	if err := f.before("remove", name); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, ok := f.files[name]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if file.Mode.IsDir() && len(f.children(name)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+	}
+	delete(f.files, name)
+	return nil
+}
+
+func (f *FSFake) Rename(oldname, newname string) error {
+	if err := f.before("rename", oldname); err != nil {
+		return err
+	}
+	if !fs.ValidPath(newname) {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, ok := f.files[oldname]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrNotExist}
+	}
+	if err := f.checkParent("rename", newname); err != nil {
+		return err
+	}
+	if file.Mode.IsDir() {
+		for _, child := range f.children(oldname) {
+			f.files[newname+strings.TrimPrefix(child, oldname)] = f.files[child]
+			delete(f.files, child)
+		}
+	}
+	delete(f.files, oldname)
+	f.files[newname] = file
+	return nil
+}
+
+// children returns all descendants of dir in lexical order. f.mu must be held.
+func (f *FSFake) children(dir string) []string {
+	var out []string
+	for name := range f.files {
+		if strings.HasPrefix(name, dir+"/") {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// write appends p to the named file. It returns fs.ErrNotExist if the file has been removed.
+func (f *FSFake) write(name string, p []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	old, ok := f.files[name]
+	if !ok {
+		return fs.ErrNotExist
+	}
+	data := make([]byte, 0, len(old.Data)+len(p))
+	data = append(data, old.Data...)
+	data = append(data, p...)
+	f.files[name] = &fstest.MapFile{
+		Data:    data,
+		Mode:    old.Mode,
+		ModTime: f.nower.Now(),
+		Sys:     old.Sys,
+	}
+	return nil
+}
+
+type fakeFile struct {
+	fsys   *FSFake
+	name   string
+	closed bool
+}
+
+func (f *fakeFile) Name() string {
+	return f.name
+}
+
+func (f *fakeFile) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrClosed}
+	}
+	if err := f.fsys.before("write", f.name); err != nil {
+		return 0, err
+	}
+	if err := f.fsys.write(f.name, p); err != nil {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: err}
+	}
+	return len(p), nil
+}
+
+func (f *fakeFile) Stat() (fs.FileInfo, error) {
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.fsys.mu.Lock()
+	defer f.fsys.mu.Unlock()
+	return fs.Stat(f.fsys.files, f.name)
+}
+
+func (f *fakeFile) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: f.name, Err: errors.New("file opened for writing")}
+}
+
+func (f *fakeFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
@@ -0,0 +1,126 @@
+package mockable_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS(t *testing.T, fsys mockable.FS) {
+	require := require.New(t)
+
+	require.NoError(fsys.MkdirAll("a/b", fs.ModePerm))
+
+	f, err := fsys.Create("a/b/foo.txt")
+	require.NoError(err)
+	require.Equal("a/b/foo.txt", f.Name())
+	_, err = io.WriteString(f, "foo")
+	require.NoError(err)
+	_, err = io.WriteString(f, "bar")
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	bin, err := fs.ReadFile(fsys, "a/b/foo.txt")
+	require.NoError(err)
+	require.Equal("foobar", string(bin))
+
+	require.NoError(fsys.WriteFile("a/bar.txt", []byte("baz"), 0o644))
+	require.NoError(fsys.Rename("a/bar.txt", "a/b/baz.txt"))
+
+	_, err = fs.Stat(fsys, "a/bar.txt")
+	require.ErrorIs(err, fs.ErrNotExist)
+
+	entries, err := fs.ReadDir(fsys, "a/b")
+	require.NoError(err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.Equal([]string{"baz.txt", "foo.txt"}, names)
+
+	require.Error(fsys.Remove("a/b"))
+	require.NoError(fsys.Remove("a/b/foo.txt"))
+	require.NoError(fsys.Remove("a/b/baz.txt"))
+	require.NoError(fsys.Remove("a/b"))
+	require.ErrorIs(fsys.Remove("a/b"), fs.ErrNotExist)
+
+	_, err = fsys.Create("nonexistent/foo.txt")
+	require.ErrorIs(err, fs.ErrNotExist)
+	_, err = fsys.Create("../foo.txt")
+	require.ErrorIs(err, fs.ErrInvalid)
+}
+
+func TestFSReal(t *testing.T) {
+	testFS(t, mockable.NewFSReal(t.TempDir()))
+}
+
+func TestFSFake(t *testing.T) {
+	testFS(t, mockable.NewFSFake(mockable.NowerReal{}))
+}
+
+func TestFSFake_modTime(t *testing.T) {
+	require := require.New(t)
+
+	nower := &mockable.NowerFake{}
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower.SetNow(now)
+
+	fsys := mockable.NewFSFake(nower)
+	require.NoError(fsys.WriteFile("foo", []byte("foo"), 0o644))
+
+	later := now.Add(time.Hour)
+	nower.SetNow(later)
+	f, err := fsys.Create("bar")
+	require.NoError(err)
+
+	stat, err := fs.Stat(fsys, "foo")
+	require.NoError(err)
+	require.True(stat.ModTime().Equal(now))
+
+	nower.SetNow(later.Add(time.Hour))
+	_, err = f.Write([]byte("bar"))
+	require.NoError(err)
+	stat, err = f.Stat()
+	require.NoError(err)
+	require.True(stat.ModTime().Equal(later.Add(time.Hour)))
+}
+
+func TestFSFake_latencyAndFault(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+	fsys := mockable.NewFSFake(clock)
+	fsys.Clock = clock
+	fsys.Latency = time.Second
+
+	done := make(chan error)
+	go func() {
+		done <- fsys.WriteFile("foo", []byte("foo"), 0o644)
+	}()
+
+	require.Equal(time.Second, <-clock.ResetCh)
+	select {
+	case <-done:
+		t.Fatal("WriteFile returned before the clock fires")
+	default:
+	}
+	clock.Send()
+	require.NoError(<-done)
+
+	fsys.Latency = 0
+	sentinel := errors.New("disk full")
+	fsys.Fault = func(op, name string) error {
+		if op == "writefile" {
+			return sentinel
+		}
+		return nil
+	}
+	require.ErrorIs(fsys.WriteFile("bar", nil, 0o644), sentinel)
+	_, err := fs.Stat(fsys, "foo")
+	require.NoError(err)
+}
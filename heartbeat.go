@@ -0,0 +1,149 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// HeartbeatOption configures Heartbeat.
+type HeartbeatOption func(h *Heartbeat)
+
+// WithHeartbeatTimer makes Heartbeat notify expiries through Expired.
+// timer is re-armed with the threshold by every Beat,
+// and is exclusively used by the Heartbeat until Stop is called.
+func WithHeartbeatTimer(timer Timer) HeartbeatOption {
+	return func(h *Heartbeat) {
+		h.timer = timer
+	}
+}
+
+// Heartbeat tracks liveness of a peer by recording beats.
+//
+// The peer is healthy while less than the threshold has elapsed since the last beat,
+// or since the Heartbeat was created if it has never beaten.
+// Elapsed time is measured by the injected Nower.
+type Heartbeat struct {
+	mu        sync.Mutex
+	nower     Nower
+	threshold time.Duration
+	created   time.Time
+	last      time.Time
+	seen      bool
+
+	timer   Timer
+	expired chan time.Time
+	stopCh  chan struct{}
+	done    chan struct{}
+}
+
+// NewHeartbeat returns a Heartbeat considering a peer dead when it has not beaten for threshold.
+func NewHeartbeat(nower Nower, threshold time.Duration, options ...HeartbeatOption) *Heartbeat {
+	h := &Heartbeat{
+		nower:     nower,
+		threshold: threshold,
+		created:   nower.Now(),
+	}
+	for _, opt := range options {
+		opt(h)
+	}
+	if h.timer != nil {
+		h.expired = make(chan time.Time, 1)
+		h.stopCh = make(chan struct{})
+		h.done = make(chan struct{})
+		h.timer.Reset(threshold)
+		go h.run()
+	}
+	return h
+}
+
+func (h *Heartbeat) run() {
+	defer close(h.done)
+	for {
+		select {
+		case <-h.stopCh:
+			return
+		case t := <-h.timer.C():
+			select {
+			case h.expired <- t:
+			default:
+			}
+		}
+	}
+}
+
+// Beat records a beat at the current time.
+// It re-arms the timer given by WithHeartbeatTimer unless Stop has been called.
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last, h.seen = h.nower.Now(), true
+	if h.timer != nil && !h.stopped() {
+		h.timer.Reset(h.threshold)
+	}
+}
+
+// stopped reports whether Stop has been called. h.mu must be held.
+func (h *Heartbeat) stopped() bool {
+	select {
+	case <-h.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// LastSeen returns the time of the last beat. ok is false if it has never beaten.
+func (h *Heartbeat) LastSeen() (last time.Time, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last, h.seen
+}
+
+func (h *Heartbeat) sinceLast() time.Duration {
+	ref := h.created
+	if h.seen {
+		ref = h.last
+	}
+	return h.nower.Now().Sub(ref)
+}
+
+// Healthy reports whether less than the threshold has elapsed since the last beat.
+func (h *Heartbeat) Healthy() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sinceLast() < h.threshold
+}
+
+// MissedCount returns the number of whole thresholds elapsed since the last beat,
+// i.e. how many beats are missed in a row.
+func (h *Heartbeat) MissedCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.threshold <= 0 {
+		return 0
+	}
+	return int(h.sinceLast() / h.threshold)
+}
+
+// Expired returns a channel to which a time is sent when the threshold elapses without beats.
+// Notifications are dropped while the previous one is not received.
+// It returns nil unless WithHeartbeatTimer is given.
+func (h *Heartbeat) Expired() <-chan time.Time {
+	return h.expired
+}
+
+// Stop stops expiry notifications and releases the timer.
+func (h *Heartbeat) Stop() {
+	if h.timer == nil {
+		return
+	}
+	h.mu.Lock()
+	if h.stopped() {
+		h.mu.Unlock()
+		return
+	}
+	h.timer.Stop()
+	close(h.stopCh)
+	h.mu.Unlock()
+	<-h.done
+}
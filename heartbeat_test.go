@@ -0,0 +1,83 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeat(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(now)
+
+	h := mockable.NewHeartbeat(nower, 10*time.Second)
+	require.Nil(h.Expired())
+	_, ok := h.LastSeen()
+	require.False(ok)
+	require.True(h.Healthy())
+
+	nower.SetNow(now.Add(10 * time.Second))
+	require.False(h.Healthy())
+	require.Equal(1, h.MissedCount())
+
+	h.Beat()
+	last, ok := h.LastSeen()
+	require.True(ok)
+	require.Equal(now.Add(10*time.Second), last)
+	require.True(h.Healthy())
+	require.Equal(0, h.MissedCount())
+
+	nower.SetNow(now.Add(45 * time.Second))
+	require.False(h.Healthy())
+	require.Equal(3, h.MissedCount())
+}
+
+func TestHeartbeat_timer(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	h := mockable.NewHeartbeat(clock, 10*time.Second, mockable.WithHeartbeatTimer(clock))
+	defer h.Stop()
+	require.Equal(10*time.Second, <-clock.ResetCh)
+
+	clock.SetNow(now.Add(3 * time.Second))
+	h.Beat()
+	require.Equal(10*time.Second, <-clock.ResetCh)
+
+	clock.Send()
+	require.Equal(now.Add(13*time.Second), <-h.Expired())
+	require.False(h.Healthy())
+	require.Equal(1, h.MissedCount())
+}
+
+func TestHeartbeat_Beat_after_Stop(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now, mockable.WithStrict())
+
+	h := mockable.NewHeartbeat(clock, 10*time.Second, mockable.WithHeartbeatTimer(clock))
+	require.Equal(10*time.Second, <-clock.ResetCh)
+	h.Stop()
+	<-clock.StopCh
+
+	// The timer is released; a late beat is recorded without re-arming it.
+	clock.SetNow(now.Add(3 * time.Second))
+	h.Beat()
+	last, ok := h.LastSeen()
+	require.True(ok)
+	require.Equal(now.Add(3*time.Second), last)
+	require.Equal(mockable.ClockIdle, clock.State())
+	select {
+	case d := <-clock.ResetCh:
+		t.Fatalf("timer re-armed with %s after Stop", d)
+	default:
+	}
+}
@@ -0,0 +1,235 @@
+package mockable
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TimeoutHandler is like http.TimeoutHandler but the timeout of each request is measured by a Clock
+// created by clocks, so timeouts are tested with fake clocks and httptest without real delays.
+//
+// h runs with a request context which is done after d. If h does not complete by then,
+// TimeoutHandler responds 503 Service Unavailable with msg, and later writes of h return http.ErrHandlerTimeout.
+// Responses of h are buffered until it returns.
+func TimeoutHandler(h http.Handler, clocks ClockFactory, d time.Duration, msg string) http.Handler {
+	return &timeoutHandler{handler: h, clocks: clocks, d: d, msg: msg}
+}
+
+type timeoutHandler struct {
+	handler http.Handler
+	clocks  ClockFactory
+	d       time.Duration
+	msg     string
+}
+
+func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	clock := h.clocks.NewClock()
+	ctx, cancel := WithTimeout(r.Context(), clock, h.d)
+	defer cancel()
+	// The handler's context is done only after tw is marked as timed out,
+	// so that the handler observing it always fails to write.
+	inner, cancelInner := context.WithCancelCause(r.Context())
+	defer cancelInner(context.Canceled)
+	deadline, _ := ctx.Deadline()
+	r = r.WithContext(&clockCtx{Context: inner, deadline: deadline})
+
+	done := make(chan struct{})
+	panicCh := make(chan any, 1)
+	tw := &timeoutWriter{header: make(http.Header)}
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panicCh <- p
+			}
+		}()
+		h.handler.ServeHTTP(tw, r)
+		close(done)
+	}()
+
+	select {
+	case p := <-panicCh:
+		panic(p)
+	case <-done:
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		dst := w.Header()
+		for k, v := range tw.header {
+			dst[k] = v
+		}
+		if !tw.wroteHeader {
+			tw.code = http.StatusOK
+		}
+		w.WriteHeader(tw.code)
+		_, _ = w.Write(tw.buf.Bytes())
+	case <-ctx.Done():
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			_, _ = io.WriteString(w, h.msg)
+			tw.err = http.ErrHandlerTimeout
+			cancelInner(context.DeadlineExceeded)
+		} else {
+			tw.err = ctx.Err()
+		}
+	}
+}
+
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+	err         error
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.err != nil {
+		return 0, tw.err
+	}
+	if !tw.wroteHeader {
+		tw.wroteHeader, tw.code = true, http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.err != nil || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader, tw.code = true, code
+}
+
+var _ http.RoundTripper = (*TimeoutTransport)(nil)
+
+// TimeoutTransport is an http.RoundTripper decorator applying a timeout to each round trip
+// and retrying responses with Retry-After, both measured by Clocks created by Clocks.
+type TimeoutTransport struct {
+	// Base is the decorated RoundTripper. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// Clocks creates a Clock for each timeout and each wait for Retry-After. If nil, ClockFactoryReal is used.
+	Clocks ClockFactory
+	// Timeout is the timeout of each round trip, including reading the response body. Zero means no timeout.
+	Timeout time.Duration
+	// MaxRetries is the number of retries made for 429 Too Many Requests and 503 Service Unavailable
+	// responses with a Retry-After header. Requests whose bodies can not be replayed are not retried.
+	MaxRetries int
+	// MaxRetryAfter caps the wait for Retry-After. Responses asking for longer waits are returned as is.
+	// Zero means no cap.
+	MaxRetryAfter time.Duration
+}
+
+func (t *TimeoutTransport) clocks() ClockFactory {
+	if t.Clocks == nil {
+		return ClockFactoryReal{}
+	}
+	return t.Clocks
+}
+
+// RoundTrip implements http.RoundTripper.
+// If the timeout elapses, it returns a *DeadlineError.
+func (t *TimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		cancel := context.CancelFunc(func() {})
+		var ctx context.Context
+		if t.Timeout > 0 {
+			ctx, cancel = WithTimeout(req.Context(), t.clocks().NewClock(), t.Timeout)
+			r = r.WithContext(ctx)
+		}
+		resp, err := base.RoundTrip(r)
+		if err != nil {
+			cancel()
+			if ctx != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && req.Context().Err() == nil {
+				return nil, &DeadlineError{Op: "round trip"}
+			}
+			return nil, err
+		}
+
+		var (
+			clock Clock
+			wait  time.Duration
+		)
+		retry := attempt < t.MaxRetries &&
+			(resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) &&
+			resp.Header.Get("Retry-After") != "" &&
+			(req.Body == nil || req.GetBody != nil)
+		if retry {
+			clock = t.clocks().NewClock()
+			wait, retry = retryAfter(resp, clock.Now())
+			retry = retry && (t.MaxRetryAfter <= 0 || wait <= t.MaxRetryAfter)
+		}
+		if !retry {
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		cancel()
+		if err := SleepContext(req.Context(), clock, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryAfter parses the Retry-After header of resp, either in seconds or as an HTTP date relative to now.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// cancelBody cancels the timeout of a round trip when the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
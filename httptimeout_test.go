@@ -0,0 +1,142 @@
+package mockable_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClocks returns a ClockFactory handing out ClockFakes through the returned channel.
+func fakeClocks(now time.Time) (mockable.ClockFactory, <-chan *mockable.ClockFake) {
+	ch := make(chan *mockable.ClockFake, 16)
+	return mockable.ClockFactoryFunc(func() mockable.Clock {
+		c := mockable.NewClockFake(now)
+		ch <- c
+		return c
+	}), ch
+}
+
+func TestTimeoutHandler(t *testing.T) {
+	require := require.New(t)
+
+	clocks, created := fakeClocks(time.Now())
+	handlerErr := make(chan error, 1)
+	h := mockable.TimeoutHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fast" {
+			w.Header().Set("X-Foo", "bar")
+			w.WriteHeader(http.StatusTeapot)
+			_, _ = io.WriteString(w, "fast")
+			return
+		}
+		<-r.Context().Done()
+		_, err := io.WriteString(w, "late")
+		handlerErr <- err
+	}), clocks, time.Second, "timed out")
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/fast")
+	require.NoError(err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(http.StatusTeapot, resp.StatusCode)
+	require.Equal("bar", resp.Header.Get("X-Foo"))
+	require.Equal("fast", string(body))
+	<-created
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(server.URL + "/slow")
+		resCh <- result{resp, err}
+	}()
+	clock := <-created
+	require.Equal(time.Second, <-clock.ResetCh)
+	clock.Send()
+	res := <-resCh
+	require.NoError(res.err)
+	body, _ = io.ReadAll(res.resp.Body)
+	res.resp.Body.Close()
+	require.Equal(http.StatusServiceUnavailable, res.resp.StatusCode)
+	require.Equal("timed out", string(body))
+	require.ErrorIs(<-handlerErr, http.ErrHandlerTimeout)
+}
+
+func TestTimeoutTransport(t *testing.T) {
+	require := require.New(t)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/hang":
+			<-r.Context().Done()
+		case calls.Add(1) == 1:
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	clocks, created := fakeClocks(time.Now())
+	client := &http.Client{Transport: &mockable.TimeoutTransport{
+		Clocks:     clocks,
+		Timeout:    10 * time.Second,
+		MaxRetries: 1,
+	}}
+
+	type result struct {
+		body string
+		err  error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, err := client.Post(server.URL, "text/plain", strings.NewReader("hello"))
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		resCh <- result{string(body), err}
+	}()
+
+	// The timeout of the first attempt, then the wait for Retry-After.
+	<-created
+	sleep := <-created
+	require.Equal(120*time.Second, <-sleep.ResetCh)
+	sleep.Send()
+	res := <-resCh
+	require.NoError(res.err)
+	require.Equal("hello", res.body)
+	require.Equal(int32(2), calls.Load())
+
+	// The timeout of a round trip.
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Get(server.URL + "/hang")
+		errCh <- err
+	}()
+	// Skips the timeout of the second attempt above.
+	<-created
+	c := <-created
+	require.Equal(10*time.Second, <-c.ResetCh)
+	c.Send()
+	err := <-errCh
+	require.ErrorIs(err, mockable.ErrTimeout)
+	var de *mockable.DeadlineError
+	require.True(errors.As(err, &de))
+}
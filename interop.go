@@ -0,0 +1,88 @@
+package mockable
+
+import "time"
+
+// Adapters in this file expose a Nower, Timer or Clock in the shapes taken by other packages,
+// so a single fake drives every component of a test without per-package glue code.
+
+// GetNower is the shape of a time source taking the current time by GetNow.
+type GetNower interface {
+	GetNow() time.Time
+}
+
+var _ GetNower = GetNowAdapter{}
+
+// GetNowAdapter exposes Nower as GetNower.
+type GetNowAdapter struct {
+	Nower Nower
+}
+
+// GetNow implements GetNower.
+func (a GetNowAdapter) GetNow() time.Time {
+	return a.Nower.Now()
+}
+
+// DeadlineTimer is the shape of a timer armed by an absolute deadline, as used by schedulers:
+// Reset arms it to fire at to, where now is the current time of the caller.
+type DeadlineTimer interface {
+	GetChan() <-chan time.Time
+	Reset(to, now time.Time)
+	Stop()
+}
+
+var _ DeadlineTimer = (*DeadlineTimerAdapter)(nil)
+
+// DeadlineTimerAdapter exposes Timer as DeadlineTimer.
+type DeadlineTimerAdapter struct {
+	Timer Timer
+}
+
+// NewDeadlineTimerAdapter returns a DeadlineTimerAdapter wrapping t.
+func NewDeadlineTimerAdapter(t Timer) *DeadlineTimerAdapter {
+	return &DeadlineTimerAdapter{Timer: t}
+}
+
+// GetChan implements DeadlineTimer.
+func (a *DeadlineTimerAdapter) GetChan() <-chan time.Time {
+	return a.Timer.C()
+}
+
+// Reset implements DeadlineTimer. It arms the Timer for to.Sub(now), which fires immediately if to is not after now.
+func (a *DeadlineTimerAdapter) Reset(to, now time.Time) {
+	a.Timer.Stop()
+	a.Timer.Reset(to.Sub(now))
+}
+
+// Stop implements DeadlineTimer.
+func (a *DeadlineTimerAdapter) Stop() {
+	a.Timer.Stop()
+}
+
+// StdTimer mirrors the API of *time.Timer over a Timer,
+// for code reading the C field and using the results of Stop and Reset.
+//
+// Unlike time.NewTimer, NewStdTimer does not arm the Timer.
+type StdTimer struct {
+	C <-chan time.Time
+	t Timer
+}
+
+// NewStdTimer returns a StdTimer wrapping t.
+// C is taken from t once, so t must return the same channel from every C call, as ClockFake does unless WithLatch is given.
+func NewStdTimer(t Timer) *StdTimer {
+	return &StdTimer{C: t.C(), t: t}
+}
+
+// Stop prevents the timer from firing, as time.Timer.Stop does.
+// It returns true if the call stops the timer, false if it has already expired or been stopped.
+func (s *StdTimer) Stop() bool {
+	return s.t.Stop()
+}
+
+// Reset changes the timer to expire after d, as time.Timer.Reset does.
+// It returns true if the timer had been active.
+func (s *StdTimer) Reset(d time.Duration) bool {
+	active := s.t.Stop()
+	s.t.Reset(d)
+	return active
+}
@@ -0,0 +1,39 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterop(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	var getNower mockable.GetNower = mockable.GetNowAdapter{Nower: clock}
+	require.Equal(now, getNower.GetNow())
+
+	// A component arming a deadline timer.
+	var deadlineTimer mockable.DeadlineTimer = mockable.NewDeadlineTimerAdapter(clock)
+	deadlineTimer.Reset(now.Add(time.Minute), getNower.GetNow())
+	require.Equal(time.Minute, <-clock.ResetCh)
+	go clock.Send()
+	require.Equal(now.Add(time.Minute), <-deadlineTimer.GetChan())
+	deadlineTimer.Stop()
+	<-clock.StopCh
+
+	// A component written against *time.Timer.
+	stdTimer := mockable.NewStdTimer(clock)
+	clock.ExhaustCh()
+	require.False(stdTimer.Reset(time.Second))
+	require.True(stdTimer.Reset(2 * time.Second))
+	armedAt := clock.Now()
+	go clock.Send()
+	require.Equal(armedAt.Add(2*time.Second), <-stdTimer.C)
+	require.False(stdTimer.Stop())
+	require.True(getNower.GetNow().After(armedAt.Add(2 * time.Second)))
+}
@@ -0,0 +1,185 @@
+package mockable
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLeaseHeld is returned from Lease.Acquire when the lease is held by another holder.
+	ErrLeaseHeld = errors.New("mockable: lease is held")
+	// ErrLeaseLost is returned from Lease.Renew and Lease.Release when the token no longer holds the lease,
+	// because it has expired or been released.
+	ErrLeaseLost = errors.New("mockable: lease is lost")
+)
+
+// LeaseToken identifies an acquisition of a Lease.
+type LeaseToken uint64
+
+// Lease is a lock which expires unless renewed within its ttl.
+//
+// Expiry is decided by the Now of the injected Clock, so Expired, Renew and Acquire agree
+// on whether the lease has expired regardless of when the timer fires.
+// The timer only closes the channel returned by Done as soon as the lease expires.
+// With ClockFake, Send expires the current acquisition.
+type Lease struct {
+	mu        sync.Mutex
+	clock     Clock
+	token     LeaseToken
+	held      bool
+	expiresAt time.Time
+	done      chan struct{}
+
+	stopped bool
+	stopCh  chan struct{}
+	runDone chan struct{}
+}
+
+// NewLease returns a Lease not held by anyone. clock is exclusively used by the Lease until Stop is called.
+func NewLease(clock Clock) *Lease {
+	l := &Lease{
+		clock:   clock,
+		done:    make(chan struct{}),
+		stopCh:  make(chan struct{}),
+		runDone: make(chan struct{}),
+	}
+	close(l.done)
+	go l.run()
+	return l
+}
+
+func (l *Lease) run() {
+	defer close(l.runDone)
+	for {
+		select {
+		case <-l.stopCh:
+			return
+		case <-l.clock.C():
+			l.mu.Lock()
+			if l.held {
+				if rest := l.expiresAt.Sub(l.clock.Now()); rest > 0 {
+					// Renewed after the timer was armed.
+					l.clock.Reset(rest)
+				} else {
+					l.expireLocked()
+				}
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+// expireLocked releases the lease and closes its Done channel. l.mu must be held.
+func (l *Lease) expireLocked() {
+	if !l.held {
+		return
+	}
+	l.held = false
+	close(l.done)
+	if !l.stopped {
+		l.clock.Stop()
+	}
+}
+
+// checkLocked expires the lease if its ttl has elapsed. l.mu must be held.
+func (l *Lease) checkLocked() {
+	if l.held && !l.clock.Now().Before(l.expiresAt) {
+		l.expireLocked()
+	}
+}
+
+// arm re-arms the timer for ttl. l.mu must be held.
+func (l *Lease) arm(ttl time.Duration) {
+	l.expiresAt = l.clock.Now().Add(ttl)
+	if l.stopped {
+		return
+	}
+	if !l.clock.Stop() {
+		select {
+		case <-l.clock.C():
+		default:
+		}
+	}
+	l.clock.Reset(ttl)
+}
+
+// Acquire acquires the lease for ttl and returns the token of this acquisition.
+// It returns ErrLeaseHeld if the lease is held and not expired.
+func (l *Lease) Acquire(ttl time.Duration) (LeaseToken, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.checkLocked()
+	if l.held {
+		return 0, ErrLeaseHeld
+	}
+	l.token++
+	l.held = true
+	l.done = make(chan struct{})
+	l.arm(ttl)
+	return l.token, nil
+}
+
+// Renew extends the lease acquired as token to ttl from now.
+// It returns ErrLeaseLost if the lease has expired or been released, even if it has been acquired again since then.
+func (l *Lease) Renew(token LeaseToken, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.checkLocked()
+	if !l.held || l.token != token {
+		return ErrLeaseLost
+	}
+	l.arm(ttl)
+	return nil
+}
+
+// Release releases the lease acquired as token. It returns ErrLeaseLost if it has already expired or been released.
+func (l *Lease) Release(token LeaseToken) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.checkLocked()
+	if !l.held || l.token != token {
+		return ErrLeaseLost
+	}
+	l.expireLocked()
+	return nil
+}
+
+// Expired reports whether the lease is not held, because it has expired, been released or never been acquired.
+func (l *Lease) Expired() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.checkLocked()
+	return !l.held
+}
+
+// ExpiresAt returns the time the current acquisition expires at. ok is false if the lease is not held.
+func (l *Lease) ExpiresAt() (expiresAt time.Time, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.checkLocked()
+	return l.expiresAt, l.held
+}
+
+// Done returns a channel closed when the current acquisition expires or is released.
+// If the lease is not held, the returned channel is already closed.
+func (l *Lease) Done() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.checkLocked()
+	return l.done
+}
+
+// Stop stops the timer goroutine. After Stop, expiry is only detected lazily by calls to l.
+func (l *Lease) Stop() {
+	l.mu.Lock()
+	if l.stopped {
+		l.mu.Unlock()
+		return
+	}
+	l.stopped = true
+	l.clock.Stop()
+	close(l.stopCh)
+	l.mu.Unlock()
+	<-l.runDone
+}
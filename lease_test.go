@@ -0,0 +1,93 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLease(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(start)
+	l := mockable.NewLease(clock)
+	defer l.Stop()
+
+	require.True(l.Expired())
+	require.True(closed(l.Done()))
+
+	a, err := l.Acquire(10 * time.Second)
+	require.NoError(err)
+	require.Equal(10*time.Second, <-clock.ResetCh)
+	done := l.Done()
+	require.False(l.Expired())
+	_, err = l.Acquire(time.Second)
+	require.ErrorIs(err, mockable.ErrLeaseHeld)
+
+	// Renewed before expiry.
+	clock.SetNow(start.Add(9 * time.Second))
+	require.NoError(l.Renew(a, 10*time.Second))
+	require.Equal(10*time.Second, <-clock.ResetCh)
+	expiresAt, ok := l.ExpiresAt()
+	require.True(ok)
+	require.Equal(start.Add(19*time.Second), expiresAt)
+
+	// The timer fires; the lease expires and Done is closed.
+	clock.Send()
+	<-done
+	require.True(l.Expired())
+	require.ErrorIs(l.Renew(a, time.Second), mockable.ErrLeaseLost)
+
+	// A stale holder can not renew a lease acquired by another.
+	b, err := l.Acquire(time.Minute)
+	require.NoError(err)
+	require.NotEqual(a, b)
+	require.ErrorIs(l.Renew(a, time.Minute), mockable.ErrLeaseLost)
+	require.ErrorIs(l.Release(a), mockable.ErrLeaseLost)
+	require.NoError(l.Release(b))
+	require.True(l.Expired())
+	require.ErrorIs(l.Release(b), mockable.ErrLeaseLost)
+}
+
+func TestLease_renewal_race(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(start)
+	l := mockable.NewLease(clock)
+	defer l.Stop()
+
+	// The ttl elapses before the timer fires: the lease is expired by Now
+	// and a late Renew fails, while Done is closed.
+	a, err := l.Acquire(10 * time.Second)
+	require.NoError(err)
+	done := l.Done()
+	clock.SetNow(start.Add(10 * time.Second))
+	require.ErrorIs(l.Renew(a, 10*time.Second), mockable.ErrLeaseLost)
+	require.True(closed(done))
+
+	// A fire of the timer armed before Renew does not expire the renewed lease.
+	b, err := l.Acquire(10 * time.Second)
+	require.NoError(err)
+	clock.SetNow(start.Add(15 * time.Second))
+	require.NoError(l.Renew(b, 10*time.Second))
+	clock.SetNow(start.Add(10 * time.Second))
+	// Sends start+20s, as if the timer armed at start+10s has fired late.
+	clock.Send()
+	require.False(l.Expired())
+	expiresAt, ok := l.ExpiresAt()
+	require.True(ok)
+	require.Equal(start.Add(25*time.Second), expiresAt)
+}
+
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,229 @@
+package mockable
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ManualChanRecord is a value sent by ManualChan.
+type ManualChanRecord[T any] struct {
+	Value T
+	// SentAt is the real time the send started.
+	SentAt time.Time
+	// ReceivedAt is the real time the value was received from C. It is zero unless Delivered is true.
+	ReceivedAt time.Time
+	Delivered  bool
+	// Drained is true if the value was taken by Drain while in flight, in which case Delivered is false.
+	Drained bool
+}
+
+// ManualChan is a channel of events of type T sent manually by a test, generalizing TimeCh of ClockFake
+// to event types other than time.Time, such as file events or messages.
+//
+// The code under test receives from C, and the test sends by Send, TrySend or SendTimeout,
+// which tell whether the value was received. Every value received from C is also copied to subscribers
+// (see Subscribe), and every send is recorded in the history.
+type ManualChan[T any] struct {
+	// Ch is the channel returned by C.
+	Ch chan T
+
+	mu      sync.Mutex
+	subs    map[chan T]struct{}
+	history []ManualChanRecord[T]
+	// drained holds values Drain took from sends in flight, to be recognized by their senders.
+	drained []T
+
+	// sendMu is held for reading by senders and for writing by Close,
+	// so that Ch is closed only after every send has returned.
+	sendMu  sync.RWMutex
+	closed  bool
+	closeCh chan struct{}
+}
+
+// NewManualChan returns a ManualChan whose channel is buffered with size of buffer.
+// An unbuffered one, i.e. buffer == 0, makes each send tell whether the code under test has received the value.
+func NewManualChan[T any](buffer int) *ManualChan[T] {
+	return newManualChan(make(chan T, buffer))
+}
+
+func newManualChan[T any](ch chan T) *ManualChan[T] {
+	return &ManualChan[T]{
+		Ch:      ch,
+		subs:    make(map[chan T]struct{}),
+		closeCh: make(chan struct{}),
+	}
+}
+
+// C returns the channel the code under test receives from.
+func (m *ManualChan[T]) C() <-chan T {
+	return m.Ch
+}
+
+// Send sends v, blocking until it is received or buffered.
+// It returns ErrClosed if m is closed before that.
+func (m *ManualChan[T]) Send(v T) error {
+	return m.send(v, true, nil, nil)
+}
+
+// TrySend sends v only if a receiver is ready or the buffer has room, without blocking.
+// It reports whether v was sent.
+func (m *ManualChan[T]) TrySend(v T) bool {
+	return m.send(v, false, nil, nil) == nil
+}
+
+// SendTimeout is Send giving up after d in the real time, in which case it returns ErrNoReceiver.
+func (m *ManualChan[T]) SendTimeout(v T, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	return m.send(v, true, timer.C, nil)
+}
+
+// errSendCanceled is returned from send when it is canceled.
+var errSendCanceled = errors.New("mockable: send canceled")
+
+// sendCancel is SendTimeout, with a nil timeout meaning no timeout,
+// giving up with errSendCanceled when cancel is closed.
+// cancel is checked first, so a send is never started after cancel is closed.
+func (m *ManualChan[T]) sendCancel(v T, timeout <-chan time.Time, cancel <-chan struct{}) error {
+	return m.send(v, true, timeout, cancel)
+}
+
+// send sends v. If wait is true, it waits until v is received, m is closed, stop receives or cancel is closed.
+func (m *ManualChan[T]) send(v T, wait bool, stop <-chan time.Time, cancel <-chan struct{}) error {
+	m.sendMu.RLock()
+	defer m.sendMu.RUnlock()
+	record := ManualChanRecord[T]{Value: v, SentAt: time.Now()}
+	m.mu.Lock()
+	closed := m.closed
+	m.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	select {
+	case <-cancel:
+		return errSendCanceled
+	default:
+	}
+	var err error
+	select {
+	case m.Ch <- v:
+		record.ReceivedAt, record.Delivered = time.Now(), true
+	default:
+		if !wait {
+			err = ErrNoReceiver
+			break
+		}
+		select {
+		case m.Ch <- v:
+			record.ReceivedAt, record.Delivered = time.Now(), true
+		case <-stop:
+			err = ErrNoReceiver
+		case <-m.closeCh:
+			err = ErrClosed
+		case <-cancel:
+			err = errSendCanceled
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record.Delivered && m.takeDrained(v) {
+		record.ReceivedAt, record.Delivered, record.Drained = time.Time{}, false, true
+	}
+	m.history = append(m.history, record)
+	if record.Delivered {
+		for sub := range m.subs {
+			select {
+			case sub <- v:
+			default:
+			}
+		}
+	}
+	return err
+}
+
+// Drain takes a value left in the buffer of C, or from a send in flight, without blocking.
+//
+// A value taken from a send in flight is dropped: it is not copied to subscribers,
+// and its sender records it as Drained rather than Delivered.
+// A value left in the buffer has been copied to subscribers already when it was buffered.
+func (m *ManualChan[T]) Drain() (v T, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Senders block only while the buffer is full, so a value can be taken from a send in flight
+	// only if the buffer is empty.
+	buffered := len(m.Ch) > 0
+	select {
+	case v, ok = <-m.Ch:
+	default:
+	}
+	if ok && !buffered {
+		// m.mu is held, so the sender recognizes v only after it is recorded here.
+		m.drained = append(m.drained, v)
+	}
+	return v, ok
+}
+
+// takeDrained reports whether v has been taken by Drain, forgetting it. m.mu must be held.
+// Equal values sent concurrently are indistinguishable, so any one of them is taken as drained.
+func (m *ManualChan[T]) takeDrained(v T) bool {
+	for i, d := range m.drained {
+		if reflect.DeepEqual(d, v) {
+			m.drained = append(m.drained[:i], m.drained[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe returns a channel buffered with size of buffer, to which every value received from C is copied.
+// Values are dropped for a subscriber whose buffer is full. cancel unsubscribes and closes the channel.
+// The channel is also closed by Close.
+func (m *ManualChan[T]) Subscribe(buffer int) (values <-chan T, cancel func()) {
+	ch := make(chan T, buffer)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	m.subs[ch] = struct{}{}
+	return ch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := m.subs[ch]; ok {
+			delete(m.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// History returns every send made so far, in the order of completion.
+func (m *ManualChan[T]) History() []ManualChanRecord[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ManualChanRecord[T](nil), m.history...)
+}
+
+// Close closes C and every subscription. Sends in flight return ErrClosed, as do later ones.
+// Close is idempotent.
+func (m *ManualChan[T]) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.closeCh)
+	for sub := range m.subs {
+		close(sub)
+	}
+	m.subs = nil
+	m.mu.Unlock()
+
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+	close(m.Ch)
+}
@@ -0,0 +1,188 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+type fileEvent struct {
+	Name string
+	Op   string
+}
+
+func TestManualChan(t *testing.T) {
+	require := require.New(t)
+
+	m := mockable.NewManualChan[fileEvent](0)
+	sub, cancel := m.Subscribe(4)
+
+	require.False(m.TrySend(fileEvent{"a", "create"}))
+	require.ErrorIs(m.SendTimeout(fileEvent{"a", "create"}, time.Millisecond), mockable.ErrNoReceiver)
+
+	received := make(chan fileEvent)
+	go func() {
+		for e := range m.C() {
+			received <- e
+		}
+		close(received)
+	}()
+	require.NoError(m.Send(fileEvent{"a", "write"}))
+	require.Equal(fileEvent{"a", "write"}, <-received)
+	require.Equal(fileEvent{"a", "write"}, <-sub)
+
+	cancel()
+	_, ok := <-sub
+	require.False(ok)
+
+	history := m.History()
+	require.Len(history, 3)
+	require.False(history[0].Delivered)
+	require.False(history[1].Delivered)
+	require.True(history[2].Delivered)
+	require.Equal(fileEvent{"a", "write"}, history[2].Value)
+	require.False(history[2].ReceivedAt.Before(history[2].SentAt))
+
+	m.Close()
+	m.Close()
+	_, ok = <-received
+	require.False(ok)
+	require.ErrorIs(m.Send(fileEvent{"a", "remove"}), mockable.ErrClosed)
+}
+
+func TestManualChan_Close_in_flight(t *testing.T) {
+	require := require.New(t)
+
+	m := mockable.NewManualChan[string](0)
+	sub, _ := m.Subscribe(1)
+	result := make(chan error)
+	go func() { result <- m.Send("message") }()
+	// Nothing receives, so the send stays in flight until Close.
+	time.Sleep(5 * time.Millisecond)
+	require.Empty(m.History())
+	m.Close()
+	require.ErrorIs(<-result, mockable.ErrClosed)
+	_, ok := <-sub
+	require.False(ok)
+}
+
+func TestManualChan_buffered(t *testing.T) {
+	require := require.New(t)
+
+	m := mockable.NewManualChan[int](1)
+	require.True(m.TrySend(1))
+	require.False(m.TrySend(2))
+	v, ok := m.Drain()
+	require.True(ok)
+	require.Equal(1, v)
+	_, ok = m.Drain()
+	require.False(ok)
+}
+
+func TestClockFake_Chan(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	fires, cancel := clock.Chan().Subscribe(2)
+	defer cancel()
+
+	clock.Reset(time.Second)
+	go clock.Send()
+	require.Equal(now.Add(time.Second), <-clock.C())
+	require.Equal(now.Add(time.Second), <-fires)
+	require.Len(clock.Chan().History(), 1)
+}
+
+func TestManualChan_Drain_in_flight(t *testing.T) {
+	require := require.New(t)
+
+	m := mockable.NewManualChan[string](0)
+	sub, cancel := m.Subscribe(2)
+	defer cancel()
+
+	result := make(chan error)
+	go func() { result <- m.Send("discarded") }()
+	var (
+		v  string
+		ok bool
+	)
+	require.Eventually(func() bool {
+		v, ok = m.Drain()
+		return ok
+	}, time.Second, time.Millisecond)
+	require.Equal("discarded", v)
+	require.NoError(<-result)
+
+	history := m.History()
+	require.Len(history, 1)
+	require.False(history[0].Delivered)
+	require.True(history[0].Drained)
+	require.True(history[0].ReceivedAt.IsZero())
+	select {
+	case v := <-sub:
+		t.Fatalf("drained value %q is fanned out", v)
+	default:
+	}
+
+	// Later sends of the same value are not mistaken for drained ones.
+	go func() { result <- m.Send("discarded") }()
+	require.Equal("discarded", <-m.C())
+	require.NoError(<-result)
+	require.Equal("discarded", <-sub)
+}
+
+func TestClockFake_Chan_literal(t *testing.T) {
+	require := require.New(t)
+
+	// A ClockFake made by a struct literal works as before.
+	clock := &mockable.ClockFake{TimeCh: make(chan time.Time, 1)}
+	fires, cancel := clock.Chan().Subscribe(2)
+	defer cancel()
+	clock.Reset(time.Second)
+	clock.Send()
+	require.Equal(time.Time{}.Add(time.Second), <-clock.C())
+	require.Equal(time.Time{}.Add(time.Second), <-fires)
+
+	// Replacing TimeCh takes effect.
+	clock.TimeCh = make(chan time.Time)
+	clock.Reset(time.Second)
+	go clock.Send()
+	require.Equal(time.Time{}.Add(2*time.Second+1), <-clock.TimeCh)
+}
+
+func TestClockFake_Chan_discarded(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	fires, cancel := clock.Chan().Subscribe(2)
+	defer cancel()
+
+	// A fire discarded by Reset does not reach subscribers.
+	clock.Reset(time.Second)
+	result := make(chan error)
+	go func() {
+		_, _, err := clock.TrySend()
+		result <- err
+	}()
+	// Reset until it catches the fire in flight.
+	var err error
+	require.Eventually(func() bool {
+		clock.Reset(time.Second)
+		select {
+		case err = <-result:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	require.ErrorIs(err, mockable.ErrDiscarded)
+	select {
+	case v := <-fires:
+		t.Fatalf("discarded fire %s is fanned out", v)
+	default:
+	}
+}
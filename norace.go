@@ -0,0 +1,6 @@
+//go:build !race
+
+package mockable
+
+// RaceEnabled reports whether the race detector is enabled, i.e. the binary is built with -race.
+const RaceEnabled = false
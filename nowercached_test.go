@@ -0,0 +1,64 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNowerCached(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	base := &mockable.NowerFake{}
+	base.SetNow(now)
+	ticker := mockable.NewTickerFake(now)
+
+	n := mockable.NewNowerCached(base, ticker, 10*time.Millisecond)
+	defer n.Stop()
+	require.Equal(10*time.Millisecond, <-ticker.ResetCh)
+	require.Equal(now, n.Now())
+
+	// Stale until the next tick.
+	base.SetNow(now.Add(5 * time.Millisecond))
+	require.Equal(now, n.Now())
+
+	ticker.Send()
+	require.Eventually(
+		func() bool { return n.Now().Equal(now.Add(5 * time.Millisecond)) },
+		time.Second, time.Millisecond,
+	)
+
+	base.SetNow(now.Add(7 * time.Millisecond))
+	n.Refresh()
+	require.Equal(now.Add(7*time.Millisecond), n.Now())
+
+	n.Stop()
+	<-ticker.StopCh
+	n.Stop()
+}
+
+func TestNowerCachedExact(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	base := &mockable.NowerFake{}
+	base.SetNow(now)
+
+	n := mockable.NewNowerCachedExact(base)
+	require.Equal(now, n.Now())
+	base.SetNow(now.Add(time.Nanosecond))
+	require.Equal(now.Add(time.Nanosecond), n.Now())
+	n.Stop()
+}
+
+func BenchmarkNowerCached(b *testing.B) {
+	n := mockable.NewNowerCached(mockable.NowerReal{}, mockable.NewTickerReal(), time.Millisecond)
+	defer n.Stop()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = n.Now()
+	}
+}
@@ -0,0 +1,55 @@
+package mockable
+
+import (
+	"log"
+	"time"
+)
+
+var _ Nower = NowerFunc(nil)
+
+// NowerFunc is an adapter to use an ordinary function as Nower.
+type NowerFunc func() time.Time
+
+// Now implements Nower.
+func (f NowerFunc) Now() time.Time {
+	return f()
+}
+
+// ComposeNower decorates base with middlewares.
+// The first middleware is the outermost one: ComposeNower(base, m1, m2) is m1(m2(base)).
+func ComposeNower(base Nower, mw ...func(Nower) Nower) Nower {
+	n := base
+	for i := len(mw) - 1; i >= 0; i-- {
+		n = mw[i](n)
+	}
+	return n
+}
+
+// OffsetNower returns a middleware shifting times by d.
+func OffsetNower(d time.Duration) func(Nower) Nower {
+	return func(next Nower) Nower {
+		return NowerFunc(func() time.Time {
+			return next.Now().Add(d)
+		})
+	}
+}
+
+// TruncateNower returns a middleware rounding times down to a multiple of d, as time.Time.Truncate does.
+func TruncateNower(d time.Duration) func(Nower) Nower {
+	return func(next Nower) Nower {
+		return NowerFunc(func() time.Time {
+			return next.Now().Truncate(d)
+		})
+	}
+}
+
+// LogNower returns a middleware logging every time it returns to logger, prefixed by name.
+func LogNower(logger *log.Logger, name string) func(Nower) Nower {
+	return func(next Nower) Nower {
+		return NowerFunc(func() time.Time {
+			t := next.Now()
+			logger.Printf("%s: Now() = %s", name, t.Format(time.RFC3339Nano))
+			return t
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package mockable_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeNower(t *testing.T) {
+	require := require.New(t)
+
+	base := &mockable.NowerFake{}
+	base.SetNow(time.Date(2023, 5, 1, 12, 34, 56, 789, time.UTC))
+
+	require.Equal(base.Now(), mockable.ComposeNower(base).Now())
+
+	var buf bytes.Buffer
+	n := mockable.ComposeNower(
+		base,
+		mockable.LogNower(log.New(&buf, "", 0), "clock"),
+		mockable.TruncateNower(time.Minute),
+		mockable.OffsetNower(time.Hour),
+	)
+	require.Equal(time.Date(2023, 5, 1, 13, 34, 0, 0, time.UTC), n.Now())
+	require.Equal("clock: Now() = 2023-05-01T13:34:00Z\n", buf.String())
+
+	// The order matters.
+	n = mockable.ComposeNower(
+		base,
+		mockable.OffsetNower(30*time.Second),
+		mockable.TruncateNower(time.Minute),
+	)
+	require.Equal(time.Date(2023, 5, 1, 12, 34, 30, 0, time.UTC), n.Now())
+
+	fixed := mockable.NowerFunc(func() time.Time { return time.Time{} })
+	require.True(fixed.Now().IsZero())
+}
@@ -0,0 +1,63 @@
+package mockable
+
+import "time"
+
+// emitFormat is the precision and the location of times emitted by a fake.
+type emitFormat struct {
+	precision time.Duration
+	loc       *time.Location
+}
+
+// apply truncates t to the precision and converts it to the location.
+func (f emitFormat) apply(t time.Time) time.Time {
+	if f.precision > 0 {
+		t = t.Truncate(f.precision)
+	}
+	if f.loc != nil {
+		t = t.In(f.loc)
+	}
+	return t
+}
+
+// step returns how far the current time is stepped after a fire time
+// so that the fire time stays strictly before the current time once both are truncated.
+func (f emitFormat) step() time.Duration {
+	if f.precision > 0 {
+		return f.precision
+	}
+	return 1
+}
+
+// WithPrecision truncates times emitted on C and returned by Now to multiples of d, e.g. time.Second,
+// for code storing timestamps of a coarse precision. d <= 0 disables truncation, which is the default.
+//
+// Under FireBefore, Send steps the current time by d rather than 1ns,
+// so that the truncated fire time stays before Now.
+func WithPrecision(d time.Duration) ClockFakeOption {
+	return func(c *ClockFake) {
+		c.format.precision = d
+	}
+}
+
+// WithLocation converts times emitted on C and returned by Now to loc.
+// A nil loc keeps the location of times given to the ClockFake, which is the default.
+func WithLocation(loc *time.Location) ClockFakeOption {
+	return func(c *ClockFake) {
+		c.format.loc = loc
+	}
+}
+
+// WithTickerPrecision is WithPrecision for TickerFake.
+// Ticks are still scheduled at exact multiples of the period, and truncated only when emitted.
+func WithTickerPrecision(d time.Duration) TickerFakeOption {
+	return func(t *TickerFake) {
+		t.format.precision = d
+	}
+}
+
+// WithTickerLocation is WithLocation for TickerFake.
+func WithTickerLocation(loc *time.Location) TickerFakeOption {
+	return func(t *TickerFake) {
+		t.format.loc = loc
+	}
+}
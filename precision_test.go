@@ -0,0 +1,81 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockFake_precision(t *testing.T) {
+	require := require.New(t)
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2023, 5, 1, 12, 0, 0, 123456789, time.UTC)
+
+	clock := mockable.NewClockFake(now, mockable.WithPrecision(time.Second), mockable.WithLocation(tokyo))
+	require.Equal(time.Date(2023, 5, 1, 21, 0, 0, 0, tokyo), clock.Now())
+	require.Equal(tokyo, clock.Now().Location())
+
+	clock.Reset(1500 * time.Millisecond)
+	go clock.Send()
+	fired := <-clock.C()
+	require.Equal(time.Date(2023, 5, 1, 21, 0, 1, 0, tokyo), fired)
+	require.Equal(tokyo, fired.Location())
+	// The current time is stepped by the precision, so the fire stays before Now.
+	require.True(fired.Before(clock.Now()))
+	require.Equal(time.Date(2023, 5, 1, 21, 0, 2, 0, tokyo), clock.Now())
+
+	// Consecutive fires never collapse into the same truncated time.
+	clock.Reset(time.Millisecond)
+	go clock.Send()
+	next := <-clock.C()
+	require.True(next.After(fired))
+	require.True(next.Before(clock.Now()))
+
+	// FireEqualAllowed keeps the fire equal to Now.
+	clock = mockable.NewClockFake(now, mockable.WithPrecision(time.Millisecond), mockable.WithFireInvariant(mockable.FireEqualAllowed))
+	clock.Reset(time.Second)
+	go clock.Send()
+	fired = <-clock.C()
+	require.Equal(now.Add(time.Second).Truncate(time.Millisecond), fired)
+	require.Equal(fired, clock.Now())
+}
+
+func TestTickerFake_precision(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 500000000, time.UTC)
+
+	ticker := mockable.NewTickerFake(now, mockable.WithTickerPrecision(time.Second), mockable.WithTickerLocation(time.UTC))
+	require.Equal(now.Truncate(time.Second), ticker.Now())
+
+	ticker.Reset(300 * time.Millisecond)
+	var ticks []time.Time
+	for i := 0; i < 3; i++ {
+		go ticker.Send()
+		tick := <-ticker.C()
+		require.True(tick.Before(ticker.Now()))
+		ticks = append(ticks, tick)
+	}
+	// Ticks are scheduled at 800ms, 1.1s and 1.4s and only truncated when emitted.
+	require.Equal([]time.Time{
+		now.Truncate(time.Second),
+		now.Truncate(time.Second).Add(time.Second),
+		now.Truncate(time.Second).Add(time.Second),
+	}, ticks)
+}
+
+func TestClockFake_precision_Advance(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now, mockable.WithPrecision(time.Second))
+
+	// Steps shorter than the precision add up.
+	for i := 0; i < 10; i++ {
+		clock.Advance(100 * time.Millisecond)
+	}
+	require.Equal(now.Add(time.Second), clock.Now())
+}
@@ -0,0 +1,96 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// QuiescenceEvent tells that the code under test driven by a VirtualClock has become idle.
+type QuiescenceEvent struct {
+	// Now is the virtual time when the code became idle.
+	Now time.Time
+	// Next is the earliest pending deadline, valid only if HasNext is true.
+	Next    time.Time
+	HasNext bool
+	// Pending is the number of armed timers and pending alarms.
+	Pending int
+}
+
+// OnQuiescent starts a goroutine owned by v calling fn each time detector reports the code under test
+// has become idle, until stop is called or v is closed.
+//
+// It lets custom deterministic schedulers and simulation frameworks embedding v decide when and how far to advance,
+// as the auto-advance mode does by itself. fn is called once per idle period:
+// it is called again only after something happens to timers of v or the virtual time moves.
+// fn is called on the owned goroutine, so it may call methods of v, including Advance.
+func (v *VirtualClock) OnQuiescent(detector QuiescenceDetector, fn func(e QuiescenceEvent)) (stop func()) {
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v.watchQuiescence(detector, stopCh, fn)
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stopCh) })
+		<-done
+	}
+}
+
+// QuiescentCh is like OnQuiescent but delivers events to the returned channel.
+// The owned goroutine blocks until each event is received, and the channel is closed after stop is called
+// or v is closed.
+func (v *VirtualClock) QuiescentCh(detector QuiescenceDetector) (events <-chan QuiescenceEvent, stop func()) {
+	ch := make(chan QuiescenceEvent)
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(ch)
+		v.watchQuiescence(detector, stopCh, func(e QuiescenceEvent) {
+			select {
+			case ch <- e:
+			case <-stopCh:
+			case <-v.closeCh:
+			}
+		})
+	}()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { close(stopCh) })
+		<-done
+	}
+}
+
+// watchQuiescence polls detector until stop or v.closeCh is closed, and calls fn once per idle period.
+func (v *VirtualClock) watchQuiescence(detector QuiescenceDetector, stop <-chan struct{}, fn func(e QuiescenceEvent)) {
+	ticker := time.NewTicker(autoAdvancePoll)
+	defer ticker.Stop()
+	var (
+		reported             bool
+		lastActive, lastTime time.Time
+	)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-v.closeCh:
+			return
+		case <-ticker.C:
+		}
+		if !detector.Quiescent(v) {
+			reported = false
+			continue
+		}
+		v.mu.Lock()
+		e := QuiescenceEvent{Now: v.now, Pending: len(v.timers) + len(v.alarms)}
+		e.Next, e.HasNext = v.next()
+		active := v.lastActivity
+		v.mu.Unlock()
+		if reported && active.Equal(lastActive) && e.Now.Equal(lastTime) {
+			continue
+		}
+		reported, lastActive, lastTime = true, active, e.Now
+		fn(e)
+	}
+}
@@ -0,0 +1,84 @@
+package mockable_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualClock_QuiescentCh(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	defer v.Close()
+
+	var (
+		mu    sync.Mutex
+		woken []time.Time
+		wg    sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		timer := v.NewTimer()
+		for i := 0; i < 3; i++ {
+			if err := mockable.SleepContext(context.Background(), timer, time.Minute); err != nil {
+				panic(err)
+			}
+			mu.Lock()
+			woken = append(woken, timer.Now())
+			mu.Unlock()
+		}
+	}()
+
+	// A custom scheduler stepping the virtual time by each idle period.
+	events, stop := v.QuiescentCh(mockable.ArmedAtLeast(1))
+	var steps []time.Time
+	for e := range events {
+		require.True(e.HasNext)
+		require.Equal(1, e.Pending)
+		steps = append(steps, e.Now)
+		v.AdvanceTo(e.Next)
+		if len(steps) == 3 {
+			break
+		}
+	}
+	wg.Wait()
+	stop()
+	_, ok := <-events
+	require.False(ok)
+
+	require.Equal([]time.Time{start, start.Add(time.Minute), start.Add(2 * time.Minute)}, steps)
+	require.Equal([]time.Time{start.Add(time.Minute), start.Add(2 * time.Minute), start.Add(3 * time.Minute)}, woken)
+}
+
+func TestVirtualClock_OnQuiescent(t *testing.T) {
+	require := require.New(t)
+
+	v := mockable.NewVirtualClock(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	timer := v.NewTimer()
+	timer.Reset(time.Second)
+
+	var calls atomic.Int32
+	stop := v.OnQuiescent(mockable.ArmedAtLeast(1), func(e mockable.QuiescenceEvent) {
+		calls.Add(1)
+	})
+	require.Eventually(func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Called once per idle period.
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(int32(1), calls.Load())
+
+	timer.Reset(time.Minute)
+	require.Eventually(func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+
+	// Close stops it as well.
+	v.Close()
+	stop()
+}
@@ -0,0 +1,108 @@
+package mockable
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// The Rand is a mockable interface of a pseudo-random number source.
+type Rand interface {
+	// Int63n returns a non-negative pseudo-random number in [0,n). It panics if n <= 0.
+	Int63n(n int64) int64
+	// Float64 returns a pseudo-random number in [0.0,1.0).
+	Float64() float64
+}
+
+var _ Rand = RandReal{}
+
+// RandReal is an implementation of the Rand interface.
+// It only wraps top-level functions of the math/rand package.
+type RandReal struct{}
+
+func (RandReal) Int63n(n int64) int64 {
+	return rand.Int63n(n)
+}
+
+func (RandReal) Float64() float64 {
+	return rand.Float64()
+}
+
+var _ Rand = (*RandFake)(nil)
+
+// RandFake is an implementation of the Rand interface returning scripted values.
+//
+// Float64 returns values set by SetFloat64 in order, and Int63n returns values set by SetInt63 modulo n.
+// Both start over from the first value after the last one is returned.
+// If no value is set, they return 0.
+type RandFake struct {
+	mu       sync.Mutex
+	floats   []float64
+	ints     []int64
+	fi, ii   int
+	numCalls int
+}
+
+// NewRandFake returns a RandFake whose Float64 returns floats in order.
+func NewRandFake(floats ...float64) *RandFake {
+	r := &RandFake{}
+	r.SetFloat64(floats...)
+	return r
+}
+
+// SetFloat64 sets values returned from Float64. Values must be in [0.0,1.0).
+func (r *RandFake) SetFloat64(floats ...float64) {
+	for _, f := range floats {
+		if f < 0 || f >= 1 {
+			panic("RandFake: Float64 value out of range [0.0,1.0)")
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.floats, r.fi = append([]float64(nil), floats...), 0
+}
+
+// SetInt63 sets values returned from Int63n. Values must be non-negative.
+func (r *RandFake) SetInt63(ints ...int64) {
+	for _, i := range ints {
+		if i < 0 {
+			panic("RandFake: negative Int63 value")
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ints, r.ii = append([]int64(nil), ints...), 0
+}
+
+func (r *RandFake) Int63n(n int64) int64 {
+	if n <= 0 {
+		panic("invalid argument to Int63n")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.numCalls++
+	if len(r.ints) == 0 {
+		return 0
+	}
+	v := r.ints[r.ii%len(r.ints)]
+	r.ii++
+	return v % n
+}
+
+func (r *RandFake) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.numCalls++
+	if len(r.floats) == 0 {
+		return 0
+	}
+	v := r.floats[r.fi%len(r.floats)]
+	r.fi++
+	return v
+}
+
+// NumCalls returns how many times Int63n and Float64 are called in total.
+func (r *RandFake) NumCalls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.numCalls
+}
@@ -0,0 +1,40 @@
+package mockable_test
+
+import (
+	"testing"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandReal(t *testing.T) {
+	require := require.New(t)
+
+	var r mockable.Rand = mockable.RandReal{}
+	for i := 0; i < 100; i++ {
+		f := r.Float64()
+		require.True(0 <= f && f < 1)
+		n := r.Int63n(10)
+		require.True(0 <= n && n < 10)
+	}
+}
+
+func TestRandFake(t *testing.T) {
+	require := require.New(t)
+
+	r := mockable.NewRandFake()
+	require.Equal(0.0, r.Float64())
+	require.Equal(int64(0), r.Int63n(5))
+
+	r.SetFloat64(0.25, 0.5)
+	r.SetInt63(3, 12)
+	require.Equal(0.25, r.Float64())
+	require.Equal(0.5, r.Float64())
+	require.Equal(0.25, r.Float64())
+	require.Equal(int64(3), r.Int63n(5))
+	require.Equal(int64(2), r.Int63n(5))
+	require.Equal(7, r.NumCalls())
+
+	require.Panics(func() { r.SetFloat64(1) })
+	require.Panics(func() { r.Int63n(0) })
+}
@@ -0,0 +1,208 @@
+package mockable
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter whose refill is computed from the injected Clock.
+//
+// The bucket holds up to burst tokens and is refilled at r tokens per second.
+// Its API follows golang.org/x/time/rate.Limiter.
+// Since refill is a pure function of Clock.Now, tests can manipulate the bucket by SetNow,
+// and Wait is unblocked by Send of ClockFake.
+type RateLimiter struct {
+	mu     sync.Mutex
+	waitMu sync.Mutex
+	clock  Clock
+	r      float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing events up to rate r per second
+// and permitting bursts of at most burst tokens. The bucket is initially full.
+// clock's timer is exclusively used by Wait and WaitN.
+func NewRateLimiter(clock Clock, r float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		clock:  clock,
+		r:      r,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}
+}
+
+// Limit returns the rate in tokens per second.
+func (l *RateLimiter) Limit() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r
+}
+
+// Burst returns the bucket size.
+func (l *RateLimiter) Burst() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.burst
+}
+
+// Tokens returns the number of tokens available now. It can be negative when tokens are reserved.
+func (l *RateLimiter) Tokens() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(l.clock.Now())
+	return l.tokens
+}
+
+// advance refills the bucket up to now. l.mu must be held.
+func (l *RateLimiter) advance(now time.Time) {
+	if !now.After(l.last) {
+		return
+	}
+	l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.r)
+	l.last = now
+}
+
+func (l *RateLimiter) durationFromTokens(tokens float64) time.Duration {
+	if l.r <= 0 {
+		return math.MaxInt64
+	}
+	return time.Duration(tokens / l.r * float64(time.Second))
+}
+
+// Allow is shorthand for AllowN(1).
+func (l *RateLimiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, and consumes n tokens if so.
+func (l *RateLimiter) AllowN(n int) bool {
+	return l.reserveN(l.clock.Now(), n, 0).ok
+}
+
+// Reserve is shorthand for ReserveN(1).
+func (l *RateLimiter) Reserve() *Reservation {
+	return l.ReserveN(1)
+}
+
+// ReserveN returns a Reservation that indicates how long the caller must wait before n events happen.
+// The RateLimiter takes this Reservation into account when allowing future events.
+// The returned Reservation is not OK if n exceeds the burst.
+func (l *RateLimiter) ReserveN(n int) *Reservation {
+	return l.reserveN(l.clock.Now(), n, math.MaxInt64)
+}
+
+func (l *RateLimiter) reserveN(now time.Time, n int, maxWait time.Duration) *Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(now)
+	tokens := l.tokens - float64(n)
+	var wait time.Duration
+	if tokens < 0 {
+		wait = l.durationFromTokens(-tokens)
+	}
+
+	r := &Reservation{
+		lim:    l,
+		tokens: n,
+		ok:     n <= l.burst && wait <= maxWait,
+	}
+	if r.ok {
+		l.tokens = tokens
+		r.timeToAct = now.Add(wait)
+	}
+	return r
+}
+
+// Wait is shorthand for WaitN(ctx, 1).
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n events may happen.
+// It returns an error if n exceeds the burst, ctx is done,
+// or the wait would exceed the deadline of ctx measured by the Clock.
+// Concurrent callers are served one at a time.
+func (l *RateLimiter) WaitN(ctx context.Context, n int) error {
+	l.waitMu.Lock()
+	defer l.waitMu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := l.clock.Now()
+	maxWait := time.Duration(math.MaxInt64)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = deadline.Sub(now)
+	}
+
+	r := l.reserveN(now, n, maxWait)
+	if !r.ok {
+		if n > l.Burst() {
+			return errors.New("mockable: WaitN count exceeds limiter burst")
+		}
+		return errors.New("mockable: WaitN would exceed context deadline")
+	}
+
+	if err := SleepContext(ctx, l.clock, r.DelayFrom(now)); err != nil {
+		r.Cancel()
+		return err
+	}
+	return nil
+}
+
+// Reservation holds information about events permitted by a RateLimiter after a delay.
+type Reservation struct {
+	lim       *RateLimiter
+	ok        bool
+	tokens    int
+	timeToAct time.Time
+}
+
+// OK reports whether the limiter can provide the requested number of tokens.
+// If OK is false, Delay returns math.MaxInt64 and Cancel does nothing.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay is shorthand for DelayFrom(Now()) of the Clock.
+func (r *Reservation) Delay() time.Duration {
+	return r.DelayFrom(r.lim.clock.Now())
+}
+
+// DelayFrom returns the duration for which the reservation holder must wait
+// before taking the reserved action, measured from now.
+func (r *Reservation) DelayFrom(now time.Time) time.Duration {
+	if !r.ok {
+		return math.MaxInt64
+	}
+	if delay := r.timeToAct.Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// Cancel indicates that the reservation holder will not perform the reserved action,
+// and returns the reserved tokens to the limiter as long as the action time has not passed.
+func (r *Reservation) Cancel() {
+	if !r.ok {
+		return
+	}
+	l := r.lim
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	if !r.timeToAct.After(now) {
+		return
+	}
+	l.advance(now)
+	l.tokens = math.Min(float64(l.burst), l.tokens+float64(r.tokens))
+	r.ok = false
+}
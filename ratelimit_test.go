@@ -0,0 +1,94 @@
+package mockable_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_allow(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	l := mockable.NewRateLimiter(clock, 10, 3)
+
+	// Burst.
+	require.True(l.Allow())
+	require.True(l.AllowN(2))
+	require.False(l.Allow())
+
+	clock.SetNow(now.Add(99 * time.Millisecond))
+	require.False(l.Allow())
+	clock.SetNow(now.Add(100 * time.Millisecond))
+	require.True(l.Allow())
+	require.False(l.Allow())
+
+	// Refill never exceeds the burst.
+	clock.SetNow(now.Add(time.Hour))
+	require.InDelta(3, l.Tokens(), 1e-9)
+	require.False(l.AllowN(4))
+	require.True(l.AllowN(3))
+}
+
+func TestRateLimiter_reserve(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	l := mockable.NewRateLimiter(clock, 2, 1)
+
+	r := l.Reserve()
+	require.True(r.OK())
+	require.Equal(time.Duration(0), r.Delay())
+
+	r = l.Reserve()
+	require.True(r.OK())
+	require.Equal(500*time.Millisecond, r.Delay())
+	r2 := l.Reserve()
+	require.Equal(time.Second, r2.Delay())
+
+	r2.Cancel()
+	require.InDelta(-1, l.Tokens(), 1e-9)
+
+	clock.SetNow(now.Add(200 * time.Millisecond))
+	require.Equal(300*time.Millisecond, r.Delay())
+
+	r = l.ReserveN(2)
+	require.False(r.OK())
+	require.Equal(time.Duration(math.MaxInt64), r.Delay())
+}
+
+func TestRateLimiter_wait(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	l := mockable.NewRateLimiter(clock, 4, 1)
+
+	require.NoError(l.Wait(context.Background()))
+
+	errCh := make(chan error)
+	go func() { errCh <- l.Wait(context.Background()) }()
+	require.Equal(250*time.Millisecond, <-clock.ResetCh)
+	clock.Send()
+	require.NoError(<-errCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { errCh <- l.Wait(ctx) }()
+	<-clock.ResetCh
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+	// tokens reserved by the cancelled Wait are returned.
+	require.InDelta(0, l.Tokens(), 1e-6)
+
+	require.Error(l.WaitN(context.Background(), 2))
+
+	ctx, cancel = mockable.WithTimeout(context.Background(), mockable.NewClockFake(clock.Now()), 100*time.Millisecond)
+	defer cancel()
+	require.Error(l.Wait(ctx))
+}
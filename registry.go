@@ -0,0 +1,168 @@
+package mockable
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// StateDumper is implemented by fakes describing their state for debugging.
+type StateDumper interface {
+	// Name returns the label of the fake.
+	Name() string
+	// DumpState returns a single-line description of the current state.
+	DumpState() string
+}
+
+var (
+	_ StateDumper = (*ClockFake)(nil)
+	_ StateDumper = (*TickerFake)(nil)
+)
+
+// WithName labels ClockFake with name, which is shown by DumpState.
+func WithName(name string) ClockFakeOption {
+	return func(c *ClockFake) {
+		c.name = name
+	}
+}
+
+// Name returns the name given by WithName, or "ClockFake" if none is given.
+func (c *ClockFake) Name() string {
+	if c.name == "" {
+		return "ClockFake"
+	}
+	return c.name
+}
+
+// DumpState implements StateDumper.
+func (c *ClockFake) DumpState() string {
+	c.Lock()
+	defer c.Unlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s, now %s", c.Name(), c.stateLocked(), c.current.Format(time.RFC3339Nano))
+	if c.scheduled {
+		for i := len(c.history); i > 0; i-- {
+			if e := c.history[i-1]; e.Kind == ClockEventReset {
+				fmt.Fprintf(&b, ", fires at %s (Reset %s)", e.At.Add(nonNegative(e.Duration)).Format(time.RFC3339Nano), e.Duration)
+				break
+			}
+		}
+	}
+	if len(c.queue) > 0 {
+		fmt.Fprintf(&b, ", %d queued", len(c.queue))
+	}
+	fmt.Fprintf(&b, ", %d events", len(c.history))
+	return b.String()
+}
+
+// WithTickerName labels TickerFake with name, which is shown by DumpState.
+func WithTickerName(name string) TickerFakeOption {
+	return func(t *TickerFake) {
+		t.name = name
+	}
+}
+
+// Name returns the name given by WithTickerName, or "TickerFake" if none is given.
+func (t *TickerFake) Name() string {
+	if t.name == "" {
+		return "TickerFake"
+	}
+	return t.name
+}
+
+// DumpState implements StateDumper.
+func (t *TickerFake) DumpState() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: ", t.Name())
+	if t.running {
+		fmt.Fprintf(&b, "running every %s, next tick at %s", t.period, t.next.Format(time.RFC3339Nano))
+	} else {
+		b.WriteString("stopped")
+	}
+	fmt.Fprintf(&b, ", now %s", t.current.Format(time.RFC3339Nano))
+	if t.count > 0 {
+		fmt.Fprintf(&b, ", %d missed", t.count)
+	}
+	return b.String()
+}
+
+// FakeRegistry is a set of fakes whose states are dumped together,
+// for debugging tests composing many timers and tickers.
+type FakeRegistry struct {
+	mu     sync.Mutex
+	lastID uint64
+	fakes  map[uint64]StateDumper
+}
+
+// DefaultFakeRegistry is the package-level FakeRegistry used by RegisterFakesT.
+var DefaultFakeRegistry = &FakeRegistry{}
+
+// Register adds f to r. Calling unregister removes it.
+func (r *FakeRegistry) Register(f StateDumper) (unregister func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fakes == nil {
+		r.fakes = make(map[uint64]StateDumper)
+	}
+	r.lastID++
+	id := r.lastID
+	r.fakes[id] = f
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.fakes, id)
+	}
+}
+
+// Dump writes the states of all registered fakes to w, one per line, in order of registration.
+func (r *FakeRegistry) Dump(w io.Writer) error {
+	r.mu.Lock()
+	ids := make([]uint64, 0, len(r.fakes))
+	for id := range r.fakes {
+		ids = append(ids, id)
+	}
+	fakes := make([]StateDumper, 0, len(ids))
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		fakes = append(fakes, r.fakes[id])
+	}
+	r.mu.Unlock()
+	return dumpStates(w, fakes)
+}
+
+func dumpStates(w io.Writer, fakes []StateDumper) error {
+	var b strings.Builder
+	for _, f := range fakes {
+		b.WriteString(f.DumpState())
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RegisterFakesT registers fakes to DefaultFakeRegistry for the duration of the test t,
+// and logs their states when t fails.
+func RegisterFakesT(t testing.TB, fakes ...StateDumper) {
+	t.Helper()
+	var unregister []func()
+	for _, f := range fakes {
+		unregister = append(unregister, DefaultFakeRegistry.Register(f))
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			var b strings.Builder
+			if err := dumpStates(&b, fakes); err == nil {
+				t.Logf("mockable: states of fakes:\n%s", b.String())
+			}
+		}
+		for _, fn := range unregister {
+			fn()
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package mockable_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockFake_DumpState(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.Equal("ClockFake", mockable.NewClockFake(now).Name())
+
+	clock := mockable.NewClockFake(now, mockable.WithName("retry"))
+	require.Equal("retry", clock.Name())
+	require.Equal("retry: idle, now 2023-05-01T12:00:00Z, 0 events", clock.DumpState())
+
+	clock.Reset(time.Second)
+	require.Equal(
+		"retry: scheduled, now 2023-05-01T12:00:00Z, fires at 2023-05-01T12:00:01Z (Reset 1s), 1 events",
+		clock.DumpState(),
+	)
+}
+
+func TestTickerFake_DumpState(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.Equal("TickerFake", mockable.NewTickerFake(now).Name())
+
+	ticker := mockable.NewTickerFake(now, mockable.WithTickerName("poll"))
+	require.Equal("poll: stopped, now 2023-05-01T12:00:00Z", ticker.DumpState())
+
+	ticker.Reset(time.Minute)
+	require.Equal(
+		"poll: running every 1m0s, next tick at 2023-05-01T12:01:00Z, now 2023-05-01T12:00:00Z",
+		ticker.DumpState(),
+	)
+}
+
+func TestFakeRegistry(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := &mockable.FakeRegistry{}
+	unregister := r.Register(mockable.NewClockFake(now, mockable.WithName("a")))
+	r.Register(mockable.NewTickerFake(now, mockable.WithTickerName("b")))
+
+	var b strings.Builder
+	require.NoError(r.Dump(&b))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(lines, 2)
+	require.True(strings.HasPrefix(lines[0], "a: "))
+	require.True(strings.HasPrefix(lines[1], "b: "))
+
+	unregister()
+	b.Reset()
+	require.NoError(r.Dump(&b))
+	require.True(strings.HasPrefix(b.String(), "b: "))
+}
+
+func TestRegisterFakesT(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now, mockable.WithName("registered"))
+	tb := &fakeTB{TB: t}
+	mockable.RegisterFakesT(tb, clock)
+
+	var b strings.Builder
+	require.NoError(mockable.DefaultFakeRegistry.Dump(&b))
+	require.Contains(b.String(), "registered: ")
+
+	tb.failed = true
+	tb.cleanups[0]()
+	require.Len(tb.logs, 1)
+	require.Contains(tb.logs[0], "registered: idle")
+
+	b.Reset()
+	require.NoError(mockable.DefaultFakeRegistry.Dump(&b))
+	require.NotContains(b.String(), "registered: ")
+}
@@ -0,0 +1,78 @@
+package mockable
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultHistogramBounds are upper bounds of histogram buckets used by ClockFake.Stats
+// when no bounds are given.
+var DefaultHistogramBounds = []time.Duration{
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	time.Minute,
+	10 * time.Minute,
+	time.Hour,
+}
+
+// HistogramBucket is a bucket of a histogram of durations.
+type HistogramBucket struct {
+	// UpperBound is the inclusive upper bound of the bucket.
+	// The last bucket has math.MaxInt64 as the upper bound to hold the rest.
+	UpperBound time.Duration
+	Count      int
+}
+
+// ResetStats is statistics over durations passed to Reset.
+type ResetStats struct {
+	Count          int
+	Min, Max, Mean time.Duration
+	// Histogram is non-cumulative: every duration is counted in the first bucket whose bound is not less than it.
+	Histogram []HistogramBucket
+}
+
+// Stats returns statistics over durations passed to Reset so far, ignoring Stop calls.
+// Histogram buckets are bounded by bounds, or DefaultHistogramBounds if none is given.
+//
+// This is useful for tests of adaptive or randomized logic,
+// where assertions on distributions are more robust than those on exact sequences.
+func (c *ClockFake) Stats(bounds ...time.Duration) ResetStats {
+	if len(bounds) == 0 {
+		bounds = DefaultHistogramBounds
+	}
+	bounds = append([]time.Duration(nil), bounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	var s ResetStats
+	s.Histogram = make([]HistogramBucket, len(bounds)+1)
+	for i, b := range bounds {
+		s.Histogram[i].UpperBound = b
+	}
+	s.Histogram[len(bounds)].UpperBound = math.MaxInt64
+
+	var sum float64
+	for _, arg := range c.CloneResetArg() {
+		if arg == nil {
+			continue
+		}
+		d := *arg
+		if s.Count == 0 || d < s.Min {
+			s.Min = d
+		}
+		if s.Count == 0 || d > s.Max {
+			s.Max = d
+		}
+		s.Count++
+		sum += float64(d)
+		i := sort.Search(len(bounds), func(i int) bool { return d <= bounds[i] })
+		s.Histogram[i].Count++
+	}
+	if s.Count > 0 {
+		s.Mean = time.Duration(sum / float64(s.Count))
+	}
+	return s
+}
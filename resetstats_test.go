@@ -0,0 +1,42 @@
+package mockable_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockFake_Stats(t *testing.T) {
+	require := require.New(t)
+
+	c := mockable.NewClockFake(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	s := c.Stats()
+	require.Equal(0, s.Count)
+	require.Len(s.Histogram, len(mockable.DefaultHistogramBounds)+1)
+
+	for _, d := range []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond,
+		1600 * time.Millisecond, 3 * time.Hour,
+	} {
+		c.Reset(d)
+		c.Stop()
+	}
+
+	s = c.Stats(time.Second, 100*time.Millisecond)
+	require.Equal(6, s.Count)
+	require.Equal(100*time.Millisecond, s.Min)
+	require.Equal(3*time.Hour, s.Max)
+	require.Equal((3100*time.Millisecond+3*time.Hour)/6, s.Mean)
+	require.Equal(
+		[]mockable.HistogramBucket{
+			{UpperBound: 100 * time.Millisecond, Count: 1},
+			{UpperBound: time.Second, Count: 3},
+			{UpperBound: math.MaxInt64, Count: 2},
+		},
+		s.Histogram,
+	)
+}
@@ -0,0 +1,63 @@
+package mockable
+
+import (
+	"context"
+	"sync"
+)
+
+var defaultClocks = struct {
+	mu sync.RWMutex
+	f  ClockFactory
+}{f: initialClockFactory()}
+
+// DefaultClockFactory returns the process-wide default ClockFactory.
+// Unless set, it is ClockFactoryReal, or a VirtualClock in builds with the mockable_fake tag.
+func DefaultClockFactory() ClockFactory {
+	defaultClocks.mu.RLock()
+	defer defaultClocks.mu.RUnlock()
+	return defaultClocks.f
+}
+
+// SetDefaultClockFactory sets the process-wide default ClockFactory and returns the previous one.
+// A nil f restores ClockFactoryReal, regardless of build tags.
+//
+// Prefer ContextWithClockFactory in tests: the default is shared by every test running in parallel.
+func SetDefaultClockFactory(f ClockFactory) (prev ClockFactory) {
+	if f == nil {
+		f = ClockFactoryReal{}
+	}
+	defaultClocks.mu.Lock()
+	defer defaultClocks.mu.Unlock()
+	prev, defaultClocks.f = defaultClocks.f, f
+	return prev
+}
+
+type clockFactoryKey struct{}
+
+// ContextWithClockFactory returns a copy of ctx carrying f, which Resolve picks for the subtree of code ctx is passed to.
+func ContextWithClockFactory(ctx context.Context, f ClockFactory) context.Context {
+	return context.WithValue(ctx, clockFactoryKey{}, f)
+}
+
+// ClockFactoryFromContext returns the ClockFactory carried by ctx.
+func ClockFactoryFromContext(ctx context.Context) (f ClockFactory, ok bool) {
+	f, ok = ctx.Value(clockFactoryKey{}).(ClockFactory)
+	return f, ok && f != nil
+}
+
+// Resolve returns the ClockFactory to use, in order of precedence:
+// explicit if non-nil, the one carried by ctx if any, or the default.
+//
+// Libraries accepting an optional clock call Resolve where they need one,
+// so that callers can inject it by a parameter, for a subtree of calls by the context, or globally.
+func Resolve(ctx context.Context, explicit ClockFactory) ClockFactory {
+	if explicit != nil {
+		return explicit
+	}
+	if ctx != nil {
+		if f, ok := ClockFactoryFromContext(ctx); ok {
+			return f
+		}
+	}
+	return DefaultClockFactory()
+}
@@ -0,0 +1,71 @@
+package mockable_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	fromCtx := mockable.NewVirtualClock(now)
+	explicit := mockable.NewVirtualClock(now.Add(time.Hour))
+	initial := mockable.DefaultClockFactory()
+
+	require.Equal(initial, mockable.Resolve(context.Background(), nil))
+	require.Equal(initial, mockable.Resolve(nil, nil))
+
+	ctx := mockable.ContextWithClockFactory(context.Background(), fromCtx)
+	require.Same(fromCtx, mockable.Resolve(ctx, nil))
+	require.Same(explicit, mockable.Resolve(ctx, explicit))
+
+	// A nil factory in the context is ignored.
+	ctx = mockable.ContextWithClockFactory(context.Background(), nil)
+	_, ok := mockable.ClockFactoryFromContext(ctx)
+	require.False(ok)
+	require.Equal(initial, mockable.Resolve(ctx, nil))
+}
+
+func TestSetDefaultClockFactory(t *testing.T) {
+	require := require.New(t)
+
+	v := mockable.NewVirtualClock(time.Time{})
+	initial := mockable.DefaultClockFactory()
+	prev := mockable.SetDefaultClockFactory(v)
+	defer mockable.SetDefaultClockFactory(prev)
+	require.Equal(initial, prev)
+
+	require.Same(v, mockable.DefaultClockFactory())
+	require.Same(v, mockable.Resolve(context.Background(), nil))
+
+	ctx := mockable.ContextWithClockFactory(context.Background(), mockable.ClockFactoryReal{})
+	require.Equal(mockable.ClockFactoryReal{}, mockable.Resolve(ctx, nil))
+
+	require.Same(v, mockable.SetDefaultClockFactory(nil))
+	require.Equal(mockable.ClockFactoryReal{}, mockable.DefaultClockFactory())
+}
+
+func ExampleResolve() {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	// A library function accepting an optional clock.
+	now := func(ctx context.Context, clocks mockable.ClockFactory) time.Time {
+		return mockable.Resolve(ctx, clocks).NewClock().Now()
+	}
+
+	fromCtx := mockable.NewVirtualClock(start)
+	explicit := mockable.NewVirtualClock(start.Add(time.Hour))
+	ctx := mockable.ContextWithClockFactory(context.Background(), fromCtx)
+
+	// The context beats the default, and an explicit parameter beats the context.
+	fmt.Println(now(ctx, nil).Format(time.Kitchen))
+	fmt.Println(now(ctx, explicit).Format(time.Kitchen))
+	// Output:
+	// 12:00PM
+	// 1:00PM
+}
@@ -0,0 +1,244 @@
+package mockable
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The Resolver is a mockable interface of net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+	LookupSRV(ctx context.Context, service, proto, name string) (cname string, addrs []*net.SRV, err error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+var _ Resolver = (*ResolverReal)(nil)
+
+// ResolverReal is an implementation of the Resolver interface.
+// It only wraps net.Resolver. If Resolver is nil, net.DefaultResolver is used.
+type ResolverReal struct {
+	Resolver *net.Resolver
+}
+
+func (r *ResolverReal) resolver() *net.Resolver {
+	if r.Resolver == nil {
+		return net.DefaultResolver
+	}
+	return r.Resolver
+}
+
+func (r *ResolverReal) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r.resolver().LookupHost(ctx, host)
+}
+
+func (r *ResolverReal) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return r.resolver().LookupSRV(ctx, service, proto, name)
+}
+
+func (r *ResolverReal) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.resolver().LookupTXT(ctx, name)
+}
+
+var _ Resolver = (*ResolverFake)(nil)
+
+// ResolverFake is an implementation of the Resolver interface serving canned records.
+//
+// Looking up a name without records fails with a *net.DNSError whose IsNotFound is true (NXDOMAIN).
+// Names set by SetUnresponsive never answer: the lookup fails with a *net.DNSError
+// whose IsTimeout is true after Timeout measured by the Clock,
+// or with ctx.Err() when the context is done first.
+//
+// If Clock is set, every lookup takes Latency measured by the Clock.
+// Timeout and the deadline of the context are also measured by the Clock.
+type ResolverFake struct {
+	mu           sync.Mutex
+	hosts        map[string][]string
+	srv          map[string]resolverSRV
+	txt          map[string][]string
+	unresponsive map[string]bool
+
+	// Clock is used to measure Latency and timeouts. Both are ignored if Clock is nil.
+	Clock   Clock
+	Latency time.Duration
+	// Timeout is the maximum amount of time a lookup waits for the answer.
+	// Zero means no timeout.
+	Timeout time.Duration
+}
+
+type resolverSRV struct {
+	cname string
+	addrs []*net.SRV
+}
+
+// NewResolverFake returns a ResolverFake without any record.
+func NewResolverFake() *ResolverFake {
+	return &ResolverFake{
+		hosts:        make(map[string][]string),
+		srv:          make(map[string]resolverSRV),
+		txt:          make(map[string][]string),
+		unresponsive: make(map[string]bool),
+	}
+}
+
+// SetHost sets addresses answered by LookupHost for host.
+func (r *ResolverFake) SetHost(host string, addrs ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hosts[host] = append([]string(nil), addrs...)
+}
+
+// SetSRV sets records answered by LookupSRV for service, proto and name.
+func (r *ResolverFake) SetSRV(service, proto, name, cname string, addrs ...*net.SRV) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.srv[srvTarget(service, proto, name)] = resolverSRV{
+		cname: cname,
+		addrs: append([]*net.SRV(nil), addrs...),
+	}
+}
+
+// SetTXT sets records answered by LookupTXT for name.
+func (r *ResolverFake) SetTXT(name string, txts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txt[name] = append([]string(nil), txts...)
+}
+
+// SetUnresponsive makes lookups for name time out.
+// For LookupSRV, name is the queried name, i.e. "_service._proto.name".
+func (r *ResolverFake) SetUnresponsive(name string, unresponsive bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if unresponsive {
+		r.unresponsive[name] = true
+	} else {
+		delete(r.unresponsive, name)
+	}
+}
+
+// Delete removes every record and behavior set for name, making it NXDOMAIN.
+// SRV records set for name under any service and proto are removed as well,
+// while SetUnresponsive for "_service._proto.name" is kept.
+func (r *ResolverFake) Delete(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.hosts, name)
+	for target := range r.srv {
+		if srvName(target) == name {
+			delete(r.srv, target)
+		}
+	}
+	delete(r.txt, name)
+	delete(r.unresponsive, name)
+}
+
+func srvTarget(service, proto, name string) string {
+	if service == "" && proto == "" {
+		return name
+	}
+	return "_" + service + "._" + proto + "." + name
+}
+
+// srvName returns name of the target built by srvTarget.
+func srvName(target string) string {
+	if !strings.HasPrefix(target, "_") {
+		return target
+	}
+	parts := strings.SplitN(target, ".", 3)
+	if len(parts) < 3 || !strings.HasPrefix(parts[1], "_") {
+		return target
+	}
+	return parts[2]
+}
+
+// wait simulates a round trip for name.
+// It returns a non-nil error if the round trip did not complete.
+func (r *ResolverFake) wait(ctx context.Context, name string) error {
+	r.mu.Lock()
+	unresponsive := r.unresponsive[name]
+	r.mu.Unlock()
+
+	delay := r.Latency
+	if unresponsive {
+		// never answers.
+		delay = 1<<63 - 1
+		_, hasDeadline := ctx.Deadline()
+		if r.Clock == nil || (r.Timeout <= 0 && !hasDeadline) {
+			// Without a deadline there is nothing to wait on the clock for.
+			<-ctx.Done()
+			return r.dnsError(name, ctx.Err())
+		}
+	}
+
+	err := waitClock(ctx, r.Clock, delay, r.Timeout)
+	if err != nil {
+		return r.dnsError(name, err)
+	}
+	return nil
+}
+
+func (r *ResolverFake) dnsError(name string, err error) error {
+	dnsErr := &net.DNSError{Name: name}
+	switch {
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		dnsErr.Err = "i/o timeout"
+		dnsErr.IsTimeout = true
+		dnsErr.IsTemporary = true
+	case err == nil:
+		dnsErr.Err = "no such host"
+		dnsErr.IsNotFound = true
+	default:
+		dnsErr.Err = err.Error()
+	}
+	return dnsErr
+}
+
+func (r *ResolverFake) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if err := r.wait(ctx, host); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addrs, ok := r.hosts[host]
+	if !ok {
+		return nil, r.dnsError(host, nil)
+	}
+	return append([]string(nil), addrs...), nil
+}
+
+func (r *ResolverFake) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	target := srvTarget(service, proto, name)
+	if err := r.wait(ctx, target); err != nil {
+		return "", nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec, ok := r.srv[target]
+	if !ok {
+		return "", nil, r.dnsError(target, nil)
+	}
+	addrs := make([]*net.SRV, len(rec.addrs))
+	for i, a := range rec.addrs {
+		cloned := *a
+		addrs[i] = &cloned
+	}
+	return rec.cname, addrs, nil
+}
+
+func (r *ResolverFake) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if err := r.wait(ctx, name); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txts, ok := r.txt[name]
+	if !ok {
+		return nil, r.dnsError(name, nil)
+	}
+	return append([]string(nil), txts...), nil
+}
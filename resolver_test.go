@@ -0,0 +1,162 @@
+package mockable_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverReal(t *testing.T) {
+	require := require.New(t)
+
+	var r mockable.Resolver = &mockable.ResolverReal{}
+	addrs, err := r.LookupHost(context.Background(), "127.0.0.1")
+	require.NoError(err)
+	require.Equal([]string{"127.0.0.1"}, addrs)
+}
+
+func TestResolverFake(t *testing.T) {
+	require := require.New(t)
+
+	r := mockable.NewResolverFake()
+	ctx := context.Background()
+
+	isNotFound := func(err error) bool {
+		var dnsErr *net.DNSError
+		return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+	}
+
+	_, err := r.LookupHost(ctx, "example.com")
+	require.True(isNotFound(err), "err = %v", err)
+
+	r.SetHost("example.com", "192.0.2.1", "192.0.2.2")
+	addrs, err := r.LookupHost(ctx, "example.com")
+	require.NoError(err)
+	require.Equal([]string{"192.0.2.1", "192.0.2.2"}, addrs)
+
+	r.SetSRV("http", "tcp", "example.com", "example.com.", &net.SRV{Target: "a.example.com.", Port: 80, Priority: 1})
+	cname, srvs, err := r.LookupSRV(ctx, "http", "tcp", "example.com")
+	require.NoError(err)
+	require.Equal("example.com.", cname)
+	require.Equal([]*net.SRV{{Target: "a.example.com.", Port: 80, Priority: 1}}, srvs)
+	_, _, err = r.LookupSRV(ctx, "ldap", "tcp", "example.com")
+	require.True(isNotFound(err), "err = %v", err)
+
+	r.SetTXT("example.com", "v=spf1 -all")
+	txts, err := r.LookupTXT(ctx, "example.com")
+	require.NoError(err)
+	require.Equal([]string{"v=spf1 -all"}, txts)
+
+	r.SetSRV("http", "tcp", "sub.example.com", "sub.example.com.", &net.SRV{Target: "b.example.com.", Port: 80})
+	r.Delete("example.com")
+	_, err = r.LookupTXT(ctx, "example.com")
+	require.True(isNotFound(err), "err = %v", err)
+	_, _, err = r.LookupSRV(ctx, "http", "tcp", "example.com")
+	require.True(isNotFound(err), "err = %v", err)
+	// Records of other names are kept.
+	_, srvs, err = r.LookupSRV(ctx, "http", "tcp", "sub.example.com")
+	require.NoError(err)
+	require.Len(srvs, 1)
+}
+
+func TestResolverFake_clock(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+	r := mockable.NewResolverFake()
+	r.Clock = clock
+	r.Latency = 10 * time.Millisecond
+	r.Timeout = time.Second
+	r.SetHost("example.com", "192.0.2.1")
+
+	type result struct {
+		addrs []string
+		err   error
+	}
+	lookup := func(ctx context.Context, host string) chan result {
+		ch := make(chan result)
+		go func() {
+			addrs, err := r.LookupHost(ctx, host)
+			ch <- result{addrs, err}
+		}()
+		return ch
+	}
+
+	resultCh := lookup(context.Background(), "example.com")
+	require.Equal(10*time.Millisecond, <-clock.ResetCh)
+	clock.Send()
+	res := <-resultCh
+	require.NoError(res.err)
+	require.Equal([]string{"192.0.2.1"}, res.addrs)
+
+	r.SetUnresponsive("example.com", true)
+	resultCh = lookup(context.Background(), "example.com")
+	require.Equal(time.Second, <-clock.ResetCh)
+	clock.Send()
+	res = <-resultCh
+	var dnsErr *net.DNSError
+	require.True(errors.As(res.err, &dnsErr))
+	require.True(dnsErr.IsTimeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resultCh = lookup(ctx, "example.com")
+	<-clock.ResetCh
+	cancel()
+	res = <-resultCh
+	require.True(errors.As(res.err, &dnsErr))
+	require.False(dnsErr.IsTimeout)
+
+	r.SetUnresponsive("example.com", false)
+	resultCh = lookup(context.Background(), "example.com")
+	<-clock.ResetCh
+	clock.Send()
+	require.NoError((<-resultCh).err)
+}
+
+func TestResolverFake_clock_unresponsive(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(
+		time.Now(),
+		mockable.WithSendPolicy(mockable.SendError),
+		mockable.WithSendGracePeriod(50*time.Millisecond),
+	)
+	r := mockable.NewResolverFake()
+	r.Clock = clock
+	r.SetHost("example.com", "192.0.2.1")
+	r.SetUnresponsive("example.com", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		_, err := r.LookupHost(ctx, "example.com")
+		errCh <- err
+	}()
+
+	// No timeout and no deadline: fires of the clock must not make the name answer.
+	// The first one may race with the lookup starting, so send a few.
+	var err error
+	for i := 0; i < 3; i++ {
+		var delivered bool
+		_, delivered, err = clock.TrySend()
+		require.False(delivered)
+	}
+	require.ErrorIs(err, mockable.ErrNoReceiver)
+	select {
+	case err := <-errCh:
+		t.Fatalf("answered after Send: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	err = <-errCh
+	var dnsErr *net.DNSError
+	require.True(errors.As(err, &dnsErr))
+	require.False(dnsErr.IsTimeout)
+	require.False(dnsErr.IsNotFound)
+}
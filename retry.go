@@ -0,0 +1,151 @@
+package mockable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy decides delays between attempts of Retry.
+// *Backoff implements RetryPolicy.
+type RetryPolicy interface {
+	// NextDelay returns the delay before the next attempt.
+	// ok is false if no more attempt should be made.
+	NextDelay() (delay time.Duration, ok bool)
+}
+
+// ConstantDelay returns a RetryPolicy which always waits d, without limit.
+func ConstantDelay(d time.Duration) RetryPolicy {
+	return constantDelay(d)
+}
+
+type constantDelay time.Duration
+
+func (c constantDelay) NextDelay() (time.Duration, bool) {
+	return time.Duration(c), true
+}
+
+// MaxRetries returns a RetryPolicy which stops policy after n retries, i.e. n+1 attempts.
+func MaxRetries(policy RetryPolicy, n int) RetryPolicy {
+	return &maxRetries{policy: policy, remaining: n}
+}
+
+type maxRetries struct {
+	mu        sync.Mutex
+	policy    RetryPolicy
+	remaining int
+}
+
+func (m *maxRetries) NextDelay() (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.remaining <= 0 {
+		return 0, false
+	}
+	m.remaining--
+	return m.policy.NextDelay()
+}
+
+// Permanent wraps err so that Retry stops retrying and returns it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// RetryAttempt is an outcome of an attempt made by Retry.
+type RetryAttempt struct {
+	// At is the time at which the attempt started, in terms of the Clock.
+	At time.Time
+	// Err is the error returned from the attempt.
+	Err error
+}
+
+// RetryError is returned from Retry when no attempt succeeded.
+type RetryError struct {
+	// Attempts holds every attempt in order.
+	Attempts []RetryAttempt
+	// Cause is the reason Retry stopped: ctx.Err(), a permanent error,
+	// or nil if the policy ran out of retries.
+	Cause error
+}
+
+func (e *RetryError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "mockable: retry failed after %d attempt(s)", len(e.Attempts))
+	if e.Cause != nil {
+		fmt.Fprintf(&b, ": %v", e.Cause)
+	}
+	if len(e.Attempts) > 0 {
+		fmt.Fprintf(&b, ": last error: %v", e.Attempts[len(e.Attempts)-1].Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns Cause and errors of every attempt.
+func (e *RetryError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Attempts)+1)
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
+	for _, a := range e.Attempts {
+		errs = append(errs, a.Err)
+	}
+	return errs
+}
+
+// Retry calls fn until it returns nil, sleeping between attempts for delays decided by policy.
+// The sleeps are measured by clock, and each attempt's start time is recorded in terms of clock.Now.
+//
+// Retry returns nil as soon as an attempt succeeds.
+// Otherwise it returns a *RetryError when the policy runs out, ctx is done,
+// or fn returns an error wrapped by Permanent.
+//
+// With ClockFake, each Send triggers the next attempt.
+func Retry(ctx context.Context, clock Clock, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	retryErr := &RetryError{}
+	for {
+		if err := ctx.Err(); err != nil {
+			retryErr.Cause = err
+			return retryErr
+		}
+
+		at := clock.Now()
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		retryErr.Attempts = append(retryErr.Attempts, RetryAttempt{At: at, Err: err})
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			retryErr.Cause = permanent.err
+			return retryErr
+		}
+
+		d, ok := policy.NextDelay()
+		if !ok {
+			return retryErr
+		}
+		if err := SleepContext(ctx, clock, d); err != nil {
+			retryErr.Cause = err
+			return retryErr
+		}
+	}
+}
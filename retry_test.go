@@ -0,0 +1,104 @@
+package mockable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	policy := mockable.NewBackoff(clock, mockable.NewRandFake(), mockable.BackoffConfig{Initial: time.Second})
+
+	count := 0
+	errCh := make(chan error)
+	go func() {
+		errCh <- mockable.Retry(context.Background(), clock, policy, func(ctx context.Context) error {
+			count++
+			if count < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+	}()
+
+	require.Equal(time.Second, <-clock.ResetCh)
+	clock.Send()
+	require.Equal(2*time.Second, <-clock.ResetCh)
+	clock.Send()
+	require.NoError(<-errCh)
+	require.Equal(3, count)
+}
+
+func TestRetry_exhausted(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	sentinel := errors.New("transient")
+	errCh := make(chan error)
+	go func() {
+		errCh <- mockable.Retry(
+			context.Background(),
+			clock,
+			mockable.MaxRetries(mockable.ConstantDelay(time.Minute), 2),
+			func(ctx context.Context) error { return sentinel },
+		)
+	}()
+
+	for i := 0; i < 2; i++ {
+		<-clock.ResetCh
+		clock.Send()
+	}
+	err := <-errCh
+
+	var retryErr *mockable.RetryError
+	require.ErrorAs(err, &retryErr)
+	require.ErrorIs(err, sentinel)
+	require.Nil(retryErr.Cause)
+	require.Equal(
+		[]mockable.RetryAttempt{
+			{At: now, Err: sentinel},
+			{At: now.Add(time.Minute + 1), Err: sentinel},
+			{At: now.Add(2*time.Minute + 2), Err: sentinel},
+		},
+		retryErr.Attempts,
+	)
+}
+
+func TestRetry_stop(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+
+	sentinel := errors.New("fatal")
+	err := mockable.Retry(context.Background(), clock, mockable.ConstantDelay(time.Second), func(ctx context.Context) error {
+		return mockable.Permanent(sentinel)
+	})
+	var retryErr *mockable.RetryError
+	require.ErrorAs(err, &retryErr)
+	require.Equal(sentinel, retryErr.Cause)
+	require.Len(retryErr.Attempts, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- mockable.Retry(ctx, clock, mockable.ConstantDelay(time.Second), func(ctx context.Context) error {
+			return errors.New("transient")
+		})
+	}()
+	<-clock.ResetCh
+	cancel()
+	err = <-errCh
+	require.ErrorIs(err, context.Canceled)
+	require.ErrorAs(err, &retryErr)
+	require.Len(retryErr.Attempts, 1)
+}
@@ -0,0 +1,85 @@
+package mockable
+
+import (
+	"context"
+	"time"
+)
+
+type runEveryConfig struct {
+	immediate   bool
+	skipOverrun bool
+	stopOnError bool
+}
+
+// RunEveryOption configures RunEvery.
+type RunEveryOption func(c *runEveryConfig)
+
+// WithImmediateRun makes RunEvery run fn once right after it is called,
+// instead of waiting for the first interval to elapse.
+func WithImmediateRun() RunEveryOption {
+	return func(c *runEveryConfig) {
+		c.immediate = true
+	}
+}
+
+// WithSkipOverrun makes RunEvery skip iterations whose scheduled time has already passed
+// when the previous one returns. Without this option, overrun iterations run immediately
+// one after another until RunEvery catches up with the schedule.
+func WithSkipOverrun() RunEveryOption {
+	return func(c *runEveryConfig) {
+		c.skipOverrun = true
+	}
+}
+
+// WithStopOnError makes RunEvery return the first non-nil error returned from fn.
+// Without this option, errors from fn are ignored.
+func WithStopOnError() RunEveryOption {
+	return func(c *runEveryConfig) {
+		c.stopOnError = true
+	}
+}
+
+// RunEvery runs fn at a fixed interval d measured by clock until ctx is done.
+//
+// Iterations are scheduled at clock.Now() + k*d where clock.Now() is taken when RunEvery is called,
+// so a slow fn does not shift later iterations.
+// RunEvery waits for fn to return before it starts the next iteration.
+//
+// RunEvery returns ctx.Err() when ctx is done,
+// or the error from fn if WithStopOnError is given.
+// The clock must not be used by others while RunEvery is running.
+//
+// With ClockFake, each Send runs exactly one iteration.
+func RunEvery(ctx context.Context, clock Clock, d time.Duration, fn func(ctx context.Context) error, options ...RunEveryOption) error {
+	if d <= 0 {
+		panic("non-positive interval for RunEvery")
+	}
+
+	var config runEveryConfig
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	next := clock.Now()
+	if !config.immediate {
+		next = next.Add(d)
+	}
+
+	for {
+		if err := SleepContext(ctx, clock, next.Sub(clock.Now())); err != nil {
+			return err
+		}
+
+		if err := fn(ctx); err != nil && config.stopOnError {
+			return err
+		}
+
+		next = next.Add(d)
+		if config.skipOverrun {
+			now := clock.Now()
+			for !next.After(now) {
+				next = next.Add(d)
+			}
+		}
+	}
+}
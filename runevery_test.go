@@ -0,0 +1,116 @@
+package mockable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEvery(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ran := make(chan time.Time)
+	errCh := make(chan error)
+	go func() {
+		errCh <- mockable.RunEvery(ctx, clock, time.Minute, func(ctx context.Context) error {
+			ran <- clock.Now()
+			return errors.New("ignored")
+		})
+	}()
+
+	require.Equal(time.Minute, <-clock.ResetCh)
+	clock.Send()
+	require.Equal(now.Add(time.Minute+1), <-ran)
+
+	// ClockFake steps 1ns past the fire time, so the next wait is 1ns shorter than the interval.
+	require.Equal(time.Minute-1, <-clock.ResetCh)
+	clock.Send()
+	require.Equal(now.Add(2*time.Minute+1), <-ran)
+
+	<-clock.ResetCh
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+}
+
+func TestRunEvery_options(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	var runAt []time.Time
+	sentinel := errors.New("stop")
+	errCh := make(chan error)
+	go func() {
+		errCh <- mockable.RunEvery(
+			context.Background(),
+			clock,
+			time.Minute,
+			func(ctx context.Context) error {
+				runAt = append(runAt, clock.Now())
+				switch len(runAt) {
+				case 1:
+					// takes 2.5 intervals.
+					clock.SetNow(clock.Now().Add(150 * time.Second))
+				case 2:
+					return sentinel
+				}
+				return nil
+			},
+			mockable.WithImmediateRun(),
+			mockable.WithSkipOverrun(),
+			mockable.WithStopOnError(),
+		)
+	}()
+
+	// Iterations at +1m and +2m are skipped.
+	require.Equal(30*time.Second, <-clock.ResetCh)
+	clock.Send()
+	require.ErrorIs(<-errCh, sentinel)
+	require.Equal([]time.Time{now, now.Add(3*time.Minute + 1)}, runAt)
+}
+
+func TestRunEvery_catchUp(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	count := 0
+	sentinel := errors.New("stop")
+	err := mockable.RunEvery(
+		context.Background(),
+		clock,
+		time.Minute,
+		func(ctx context.Context) error {
+			count++
+			if count == 1 {
+				clock.SetNow(clock.Now().Add(150 * time.Second))
+			}
+			if count == 3 {
+				return sentinel
+			}
+			return nil
+		},
+		mockable.WithImmediateRun(),
+		mockable.WithStopOnError(),
+	)
+	// Overrun iterations run without arming the clock.
+	require.ErrorIs(err, sentinel)
+	require.Equal(3, count)
+	select {
+	case <-clock.ResetCh:
+		t.Fatal("clock is reset")
+	default:
+	}
+}
@@ -0,0 +1,91 @@
+package mockable
+
+import (
+	"runtime"
+	"sync"
+)
+
+// The RuntimeInfo is a mockable interface for querying the Go runtime.
+type RuntimeInfo interface {
+	// NumCPU returns the number of logical CPUs usable by the current process.
+	NumCPU() int
+	// GOMAXPROCS returns the current GOMAXPROCS setting without changing it.
+	GOMAXPROCS() int
+	// ReadMemStats populates m with memory allocator statistics.
+	ReadMemStats(m *runtime.MemStats)
+}
+
+var _ RuntimeInfo = RuntimeInfoReal{}
+
+// RuntimeInfoReal is an implementation of the RuntimeInfo interface.
+// It only wraps functions of the runtime package.
+type RuntimeInfoReal struct{}
+
+func (RuntimeInfoReal) NumCPU() int {
+	return runtime.NumCPU()
+}
+
+func (RuntimeInfoReal) GOMAXPROCS() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+func (RuntimeInfoReal) ReadMemStats(m *runtime.MemStats) {
+	runtime.ReadMemStats(m)
+}
+
+var _ RuntimeInfo = (*RuntimeInfoFake)(nil)
+
+// RuntimeInfoFake is an implementation of the RuntimeInfo interface with settable values.
+type RuntimeInfoFake struct {
+	mu         sync.Mutex
+	numCPU     int
+	gomaxprocs int
+	memStats   runtime.MemStats
+}
+
+// NewRuntimeInfoFake returns a RuntimeInfoFake reporting numCPU for both NumCPU and GOMAXPROCS.
+func NewRuntimeInfoFake(numCPU int) *RuntimeInfoFake {
+	return &RuntimeInfoFake{
+		numCPU:     numCPU,
+		gomaxprocs: numCPU,
+	}
+}
+
+func (r *RuntimeInfoFake) NumCPU() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.numCPU
+}
+
+func (r *RuntimeInfoFake) GOMAXPROCS() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gomaxprocs
+}
+
+func (r *RuntimeInfoFake) ReadMemStats(m *runtime.MemStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*m = r.memStats
+}
+
+func (r *RuntimeInfoFake) SetNumCPU(n int) (prev int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.numCPU, prev = n, r.numCPU
+	return prev
+}
+
+func (r *RuntimeInfoFake) SetGOMAXPROCS(n int) (prev int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gomaxprocs, prev = n, r.gomaxprocs
+	return prev
+}
+
+// SetMemStats sets stats returned from ReadMemStats.
+func (r *RuntimeInfoFake) SetMemStats(m runtime.MemStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.memStats = m
+}
@@ -0,0 +1,39 @@
+package mockable_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeInfoReal(t *testing.T) {
+	require := require.New(t)
+
+	var r mockable.RuntimeInfo = mockable.RuntimeInfoReal{}
+	require.Equal(runtime.NumCPU(), r.NumCPU())
+	require.Equal(runtime.GOMAXPROCS(0), r.GOMAXPROCS())
+
+	var m runtime.MemStats
+	r.ReadMemStats(&m)
+	require.NotZero(m.Sys)
+}
+
+func TestRuntimeInfoFake(t *testing.T) {
+	require := require.New(t)
+
+	r := mockable.NewRuntimeInfoFake(128)
+	require.Equal(128, r.NumCPU())
+	require.Equal(128, r.GOMAXPROCS())
+
+	require.Equal(128, r.SetNumCPU(1))
+	require.Equal(128, r.SetGOMAXPROCS(2))
+	require.Equal(1, r.NumCPU())
+	require.Equal(2, r.GOMAXPROCS())
+
+	r.SetMemStats(runtime.MemStats{HeapAlloc: 1 << 20})
+	var m runtime.MemStats
+	r.ReadMemStats(&m)
+	require.Equal(uint64(1<<20), m.HeapAlloc)
+}
@@ -0,0 +1,191 @@
+package mockable
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Scheduler runs jobs according to their Schedules, firing them via the injected Clock.
+//
+// Schedules are evaluated in the Scheduler's location, so cron expressions follow
+// the wall clock of that location, including its daylight saving time transitions.
+// A single Clock drives every job: it is always armed for the earliest pending activation.
+//
+// With ClockFake, each Send jumps straight to the next activation,
+// so days of schedule can be verified within milliseconds of test time.
+type Scheduler struct {
+	mu      sync.Mutex
+	clock   Clock
+	loc     *time.Location
+	nextID  int
+	entries map[int]*schedulerEntry
+	wake    chan struct{}
+}
+
+type schedulerEntry struct {
+	id       int
+	schedule Schedule
+	job      func(scheduled time.Time)
+	next     time.Time
+}
+
+// NewScheduler returns a Scheduler evaluating schedules in loc.
+// If loc is nil, time.Local is used.
+// clock is exclusively used by the Scheduler while Run is running.
+func NewScheduler(clock Clock, loc *time.Location) *Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &Scheduler{
+		clock:   clock,
+		loc:     loc,
+		entries: make(map[int]*schedulerEntry),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Add registers job to run on schedule and returns its id.
+// job receives the scheduled activation time, in the Scheduler's location.
+func (s *Scheduler) Add(schedule Schedule, job func(scheduled time.Time)) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	e := &schedulerEntry{
+		id:       s.nextID,
+		schedule: schedule,
+		job:      job,
+		next:     schedule.Next(s.clock.Now().In(s.loc)),
+	}
+	s.entries[e.id] = e
+	s.notify()
+	return e.id
+}
+
+// AddCron registers job to run on the cron expression. See ParseCron for the syntax.
+func (s *Scheduler) AddCron(expr string, job func(scheduled time.Time)) (int, error) {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return 0, err
+	}
+	return s.Add(schedule, job), nil
+}
+
+// AddInterval registers job to run every d.
+func (s *Scheduler) AddInterval(d time.Duration, job func(scheduled time.Time)) int {
+	return s.Add(Every(d), job)
+}
+
+// Remove unregisters the job with id.
+func (s *Scheduler) Remove(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, id)
+	s.notify()
+}
+
+// Next returns the next activation time of the job with id.
+// ok is false if the job is not registered or will never run again.
+func (s *Scheduler) Next(id int) (next time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok || e.next.IsZero() {
+		return time.Time{}, false
+	}
+	return e.next, true
+}
+
+// notify wakes Run up to re-arm the clock. s.mu must be held.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// earliest returns the earliest pending activation. s.mu must be held.
+func (s *Scheduler) earliest() (time.Time, bool) {
+	var (
+		min time.Time
+		ok  bool
+	)
+	for _, e := range s.entries {
+		if e.next.IsZero() {
+			continue
+		}
+		if !ok || e.next.Before(min) {
+			min, ok = e.next, true
+		}
+	}
+	return min, ok
+}
+
+// due collects entries whose activation has come, ordered by activation time and then by id,
+// and advances them to their following activations. s.mu must be held.
+func (s *Scheduler) due(now time.Time) []schedulerEntry {
+	var out []schedulerEntry
+	for _, e := range s.entries {
+		if e.next.IsZero() || e.next.After(now) {
+			continue
+		}
+		out = append(out, *e)
+		next := e.schedule.Next(e.next)
+		if !next.IsZero() && !next.After(now) {
+			// Missed activations are skipped.
+			next = e.schedule.Next(now.In(s.loc))
+		}
+		e.next = next
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].next.Equal(out[j].next) {
+			return out[i].next.Before(out[j].next)
+		}
+		return out[i].id < out[j].id
+	})
+	return out
+}
+
+// Run runs jobs until ctx is done, and returns ctx.Err().
+// Jobs run sequentially on the calling goroutine, in order of their activation times.
+func (s *Scheduler) Run(ctx context.Context) error {
+	// Jobs added so far are taken into account below.
+	select {
+	case <-s.wake:
+	default:
+	}
+	for {
+		s.mu.Lock()
+		jobs := s.due(s.clock.Now())
+		s.mu.Unlock()
+		for _, e := range jobs {
+			e.job(e.next)
+		}
+
+		s.mu.Lock()
+		next, ok := s.earliest()
+		s.mu.Unlock()
+
+		if ok {
+			if d := next.Sub(s.clock.Now()); d > 0 {
+				s.clock.Reset(d)
+			} else {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if ok {
+				s.clock.Stop()
+			}
+			return ctx.Err()
+		case <-s.wake:
+			if ok {
+				s.clock.Stop()
+			}
+		case <-s.clock.C():
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	require := require.New(t)
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(err)
+
+	clock := mockable.NewClockFake(time.Date(2023, 3, 11, 0, 0, 0, 0, ny))
+	s := mockable.NewScheduler(clock, ny)
+
+	var fired []string
+	record := func(name string) func(time.Time) {
+		return func(scheduled time.Time) {
+			fired = append(fired, name+" "+scheduled.Format("01-02 15:04 MST"))
+		}
+	}
+	_, err = s.AddCron("30 2 * * *", record("cron"))
+	require.NoError(err)
+	intervalID := s.AddInterval(20*time.Hour, record("interval"))
+	_, err = s.AddCron("bogus", record("bogus"))
+	require.Error(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error)
+	go func() { errCh <- s.Run(ctx) }()
+
+	// Each Send jumps to the next activation.
+	for i := 0; i < 4; i++ {
+		<-clock.ResetCh
+		clock.Send()
+	}
+	<-clock.ResetCh
+	require.Equal(
+		[]string{
+			"cron 03-11 02:30 EST",
+			"interval 03-11 20:00 EST",
+			// 03-12 02:30 does not exist.
+			"interval 03-12 17:00 EDT",
+			"cron 03-13 02:30 EDT",
+		},
+		fired,
+	)
+
+	next, ok := s.Next(intervalID)
+	require.True(ok)
+	require.Equal(time.Date(2023, 3, 13, 13, 0, 0, 0, ny), next)
+
+	s.Remove(intervalID)
+	_, ok = s.Next(intervalID)
+	require.False(ok)
+
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+}
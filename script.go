@@ -0,0 +1,130 @@
+package mockable
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ScriptStep is a step of a timeline played by VirtualClock.Script.
+type ScriptStep struct {
+	apply func(v *VirtualClock)
+}
+
+// ScriptAdvance returns a step advancing the virtual time by d, firing timers due meanwhile.
+func ScriptAdvance(d time.Duration) ScriptStep {
+	return ScriptStep{apply: func(v *VirtualClock) { v.Advance(d) }}
+}
+
+// ScriptAdvanceTo returns a step setting the virtual time to t, firing timers due meanwhile.
+// The virtual time never goes backwards, so the step does nothing if t is in the past.
+func ScriptAdvanceTo(t time.Time) ScriptStep {
+	return ScriptStep{apply: func(v *VirtualClock) { v.AdvanceTo(t) }}
+}
+
+// ScriptFireNext returns a step advancing the virtual time to the earliest pending deadline,
+// firing the timers and alarms due then. It does nothing if none is pending.
+func ScriptFireNext() ScriptStep {
+	return ScriptStep{apply: func(v *VirtualClock) {
+		if next, ok := v.Next(); ok {
+			v.AdvanceTo(next)
+		}
+	}}
+}
+
+// ScriptBarrier returns a step advancing the virtual time to t and pausing there as a barrier does.
+// The following steps are held until Continue is called. See AddBarrier.
+func ScriptBarrier(t time.Time) ScriptStep {
+	return ScriptStep{apply: func(v *VirtualClock) {
+		v.AddBarrier(t)
+		v.AdvanceTo(t)
+	}}
+}
+
+// ScriptDo returns a step calling fn, e.g. to inject a fault at a point of the timeline.
+func ScriptDo(fn func(v *VirtualClock)) ScriptStep {
+	return ScriptStep{apply: fn}
+}
+
+// ScriptRun is a timeline being played by VirtualClock.Script.
+type ScriptRun struct {
+	mu     sync.Mutex
+	played int
+	total  int
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	done     chan struct{}
+}
+
+// Script plays steps in order on a goroutine owned by v, turning the choreography of a test
+// into a declarative timeline.
+//
+// Each step waits until v is not paused at a barrier and detector reports the code under test is idle,
+// so the code reacts to a step before the next one is played.
+// Playing stops after the last step, when Stop is called or v is closed.
+func (v *VirtualClock) Script(detector QuiescenceDetector, steps ...ScriptStep) *ScriptRun {
+	r := &ScriptRun{
+		total:  len(steps),
+		stopCh: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.play(v, detector, steps)
+	return r
+}
+
+func (r *ScriptRun) play(v *VirtualClock, detector QuiescenceDetector, steps []ScriptStep) {
+	defer close(r.done)
+	ticker := time.NewTicker(autoAdvancePoll)
+	defer ticker.Stop()
+	for _, step := range steps {
+		for {
+			select {
+			case <-r.stopCh:
+				return
+			case <-v.closeCh:
+				return
+			case <-ticker.C:
+			}
+			if _, paused := v.Paused(); !paused && detector.Quiescent(v) {
+				break
+			}
+		}
+		step.apply(v)
+		r.mu.Lock()
+		r.played++
+		r.mu.Unlock()
+	}
+}
+
+// Played returns the number of steps played so far.
+func (r *ScriptRun) Played() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.played
+}
+
+// Done returns a channel closed when playing stops.
+func (r *ScriptRun) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait blocks until playing stops, and returns nil if every step is played.
+// It returns ctx.Err() if ctx is done before that, and ErrStopped if playing stopped halfway.
+func (r *ScriptRun) Wait(ctx context.Context) error {
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if r.Played() < r.total {
+		return ErrStopped
+	}
+	return nil
+}
+
+// Stop stops playing and waits for the step being played, if any, to return.
+func (r *ScriptRun) Stop() {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+	<-r.done
+}
@@ -0,0 +1,79 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualClock_Script(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	defer v.Close()
+
+	// The code under test re-arms its timer for 1s after each fire.
+	fires := make(chan time.Time, 10)
+	timer := v.NewTimer()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			timer.Reset(time.Second)
+			select {
+			case <-stop:
+				return
+			case at := <-timer.C():
+				fires <- at
+			}
+		}
+	}()
+
+	var injected time.Time
+	run := v.Script(
+		mockable.ArmedAtLeast(1),
+		mockable.ScriptFireNext(),
+		mockable.ScriptFireNext(),
+		mockable.ScriptBarrier(start.Add(5*time.Second)),
+		mockable.ScriptDo(func(v *mockable.VirtualClock) { injected = v.Now() }),
+		mockable.ScriptAdvance(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	at, err := v.WaitBarrier(ctx)
+	require.NoError(err)
+	require.Equal(start.Add(5*time.Second), at)
+	require.Equal(start.Add(time.Second), <-fires)
+	require.Equal(start.Add(2*time.Second), <-fires)
+	require.Equal(start.Add(3*time.Second), <-fires)
+	// Steps are held while paused.
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(3, run.Played())
+
+	v.Continue()
+	require.NoError(run.Wait(ctx))
+	require.Equal(5, run.Played())
+	require.Equal(start.Add(5*time.Second), injected)
+	// The timer re-armed at the barrier fires 1s after it.
+	require.Equal(start.Add(6*time.Second), <-fires)
+}
+
+func TestVirtualClock_Script_stop(t *testing.T) {
+	require := require.New(t)
+
+	v := mockable.NewVirtualClock(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	defer v.Close()
+
+	// Nothing is armed, so the script never proceeds.
+	run := v.Script(mockable.ArmedAtLeast(1), mockable.ScriptAdvance(time.Second))
+	time.Sleep(5 * time.Millisecond)
+	run.Stop()
+	<-run.Done()
+	require.Equal(0, run.Played())
+	require.ErrorIs(run.Wait(context.Background()), mockable.ErrStopped)
+}
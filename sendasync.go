@@ -0,0 +1,134 @@
+package mockable
+
+import (
+	"context"
+	"time"
+)
+
+// SendHandle tracks a value sent by ClockFake.SendAsync.
+type SendHandle struct {
+	fired     time.Time
+	delivered bool
+	touched   bool
+
+	done     chan struct{}
+	consumed chan struct{}
+	resumed  chan struct{}
+}
+
+// SendAsync sends like Send without blocking the caller, and returns a handle
+// to know when the code under test received the value and then resumed.
+//
+// The code under test is considered to have resumed when it calls Reset or Stop after receiving the value,
+// which is what a timer loop does before waiting again.
+// Reset or Stop called while the value is in flight is attributed to the receiver.
+func (c *ClockFake) SendAsync() *SendHandle {
+	h := &SendHandle{
+		done:     make(chan struct{}),
+		consumed: make(chan struct{}),
+		resumed:  make(chan struct{}),
+	}
+	go c.trySend(h, time.Time{}, 0, false)
+	return h
+}
+
+// settleLocked records the outcome of the send tracked by h. c.Mutex must be held.
+func (c *ClockFake) settleLocked(h *SendHandle, fired time.Time, delivered bool) {
+	defer close(h.done)
+	if !delivered {
+		c.removeAwaitingLocked(h)
+		return
+	}
+	h.fired, h.delivered = fired, true
+	close(h.consumed)
+	if h.touched {
+		close(h.resumed)
+		c.removeAwaitingLocked(h)
+	}
+}
+
+// resumeLocked is called by Reset and Stop. c.Mutex must be held.
+func (c *ClockFake) resumeLocked() {
+	rest := c.awaiting[:0]
+	for _, h := range c.awaiting {
+		if h.delivered {
+			close(h.resumed)
+			continue
+		}
+		h.touched = true
+		rest = append(rest, h)
+	}
+	for i := len(rest); i < len(c.awaiting); i++ {
+		c.awaiting[i] = nil
+	}
+	c.awaiting = rest
+}
+
+func (c *ClockFake) removeAwaitingLocked(h *SendHandle) {
+	for i, a := range c.awaiting {
+		if a == h {
+			c.awaiting = append(c.awaiting[:i], c.awaiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// Done returns a channel closed when the outcome of the send is known, whether delivered or not.
+// Under SendQueue, this is when the queued value is received.
+func (h *SendHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Consumed returns a channel closed when the value has been received.
+// It is never closed if the value is dropped or given up.
+func (h *SendHandle) Consumed() <-chan struct{} {
+	return h.consumed
+}
+
+// Resumed returns a channel closed when the code under test has called Reset or Stop after receiving the value.
+func (h *SendHandle) Resumed() <-chan struct{} {
+	return h.resumed
+}
+
+// Fired blocks until the value is received, and returns it.
+func (h *SendHandle) Fired() time.Time {
+	<-h.consumed
+	return h.fired
+}
+
+// AwaitConsumed blocks until the value is received or ctx is done.
+func (h *SendHandle) AwaitConsumed(ctx context.Context) error {
+	select {
+	case <-h.consumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AwaitResumed blocks until the code under test resumes after receiving the value, or ctx is done.
+func (h *SendHandle) AwaitResumed(ctx context.Context) error {
+	select {
+	case <-h.resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Then calls fn with the value sent in a new goroutine once the value is received.
+// fn is never called if the value is not delivered.
+func (h *SendHandle) Then(fn func(fired time.Time)) {
+	go func() {
+		select {
+		case <-h.consumed:
+			fn(h.fired)
+		case <-h.done:
+			select {
+			case <-h.consumed:
+				fn(h.fired)
+			default:
+			}
+		}
+	}()
+}
@@ -0,0 +1,84 @@
+package mockable_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockFake_SendAsync(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	var (
+		mu       sync.Mutex
+		received []time.Time
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for {
+			clock.Reset(time.Second)
+			select {
+			case <-ctx.Done():
+				clock.Stop()
+				return
+			case v := <-clock.C():
+				mu.Lock()
+				received = append(received, v)
+				mu.Unlock()
+			}
+		}
+	}()
+
+	for i := 1; i <= 3; i++ {
+		<-clock.ResetCh
+		h := clock.SendAsync()
+		require.NoError(h.AwaitConsumed(ctx))
+		require.NoError(h.AwaitResumed(ctx))
+		<-h.Done()
+
+		// The loop has handled the value by the time it resumed.
+		mu.Lock()
+		require.Len(received, i)
+		require.Equal(h.Fired(), received[i-1])
+		mu.Unlock()
+	}
+
+	thenCalled := make(chan time.Time, 1)
+	<-clock.ResetCh
+	h := clock.SendAsync()
+	h.Then(func(fired time.Time) { thenCalled <- fired })
+	require.Equal(h.Fired(), <-thenCalled)
+}
+
+func TestClockFake_SendAsync_not_delivered(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(
+		time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		mockable.WithSendPolicy(mockable.SendDrop),
+	)
+	clock.Reset(time.Second)
+
+	h := clock.SendAsync()
+	<-h.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	require.ErrorIs(h.AwaitConsumed(ctx), context.DeadlineExceeded)
+
+	// Later Reset calls are not attributed to it.
+	clock.Reset(time.Second)
+	select {
+	case <-h.Resumed():
+		t.Fatal("resumed without being consumed")
+	default:
+	}
+}
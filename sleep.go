@@ -0,0 +1,26 @@
+package mockable
+
+import (
+	"context"
+	"time"
+)
+
+// SleepContext pauses the current goroutine for at least the duration d measured by timer,
+// or until ctx is done. It returns nil if it slept for d, otherwise ctx.Err().
+// A zero or negative d returns ctx.Err() immediately without arming timer.
+//
+// timer must not be used by others while SleepContext is sleeping.
+// With ClockFake, the sleep ends when Send is called.
+func SleepContext(ctx context.Context, timer Timer, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer.Reset(d)
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	}
+}
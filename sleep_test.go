@@ -0,0 +1,53 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSleepContext(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+
+	sleep := func(ctx context.Context, d time.Duration) chan error {
+		ch := make(chan error)
+		go func() {
+			ch <- mockable.SleepContext(ctx, clock, d)
+		}()
+		return ch
+	}
+
+	errCh := sleep(context.Background(), time.Hour)
+	require.Equal(time.Hour, <-clock.ResetCh)
+	clock.Send()
+	require.NoError(<-errCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh = sleep(ctx, time.Hour)
+	<-clock.ResetCh
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+	<-clock.StopCh
+	require.False(clock.IsScheduled())
+
+	require.NoError(mockable.SleepContext(context.Background(), clock, 0))
+	require.ErrorIs(mockable.SleepContext(ctx, clock, -1), context.Canceled)
+	select {
+	case <-clock.ResetCh:
+		t.Fatal("non-positive duration must not arm the timer")
+	default:
+	}
+}
+
+func TestSleepContext_real(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Now()
+	require.NoError(mockable.SleepContext(context.Background(), mockable.NewClockReal(), time.Millisecond))
+	require.GreaterOrEqual(time.Since(start), time.Millisecond)
+}
@@ -0,0 +1,133 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// SlidingWindowConfig configures SlidingWindow. Zero fields are replaced with defaults.
+type SlidingWindowConfig struct {
+	// Window is the longest span counts are kept for. Defaults to 1m.
+	Window time.Duration
+	// Resolution is the span of a bucket, i.e. the precision of window boundaries. Defaults to 1s.
+	// Window is rounded up to a multiple of Resolution.
+	Resolution time.Duration
+}
+
+// SlidingWindow counts events within sliding windows of time, for rate metrics and admission control.
+//
+// Events are counted in buckets of Resolution aligned to the zero time, kept for Window.
+// All window math uses the injected Nower, so counts are fully controllable in tests by SetNow.
+type SlidingWindow struct {
+	mu         sync.Mutex
+	nower      Nower
+	resolution time.Duration
+	buckets    []int64
+	// head is the index of the bucket of the time slot last.
+	head int
+	last int64
+}
+
+// NewSlidingWindow returns an empty SlidingWindow configured by config.
+func NewSlidingWindow(nower Nower, config SlidingWindowConfig) *SlidingWindow {
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	if config.Resolution <= 0 {
+		config.Resolution = time.Second
+	}
+	n := int((config.Window + config.Resolution - 1) / config.Resolution)
+	return &SlidingWindow{
+		nower:      nower,
+		resolution: config.Resolution,
+		buckets:    make([]int64, n),
+		last:       windowSlot(nower.Now(), config.Resolution),
+	}
+}
+
+func windowSlot(t time.Time, resolution time.Duration) int64 {
+	// Divides each part separately to avoid overflowing UnixNano for times far from the epoch.
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	if resolution >= time.Second && resolution%time.Second == 0 {
+		return floorDiv(sec, int64(resolution/time.Second))
+	}
+	return floorDiv(sec*int64(time.Second)+nsec, int64(resolution))
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// Window returns the span counts are kept for.
+func (w *SlidingWindow) Window() time.Duration {
+	return time.Duration(len(w.buckets)) * w.resolution
+}
+
+// advance moves the head to the current slot, clearing buckets which have slid out. w.mu must be held.
+func (w *SlidingWindow) advance() {
+	now := windowSlot(w.nower.Now(), w.resolution)
+	if now <= w.last {
+		// The time went backwards; keeps counting into the head.
+		return
+	}
+	steps := now - w.last
+	if steps > int64(len(w.buckets)) {
+		steps = int64(len(w.buckets))
+	}
+	for i := int64(0); i < steps; i++ {
+		w.head = (w.head + 1) % len(w.buckets)
+		w.buckets[w.head] = 0
+	}
+	w.last = now
+}
+
+// Incr counts an event now.
+func (w *SlidingWindow) Incr() {
+	w.IncrBy(1)
+}
+
+// IncrBy counts n events now.
+func (w *SlidingWindow) IncrBy(n int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance()
+	w.buckets[w.head] += n
+}
+
+// count sums the buckets covering the last d. w.mu must be held.
+func (w *SlidingWindow) count(d time.Duration) int64 {
+	w.advance()
+	n := int((d + w.resolution - 1) / w.resolution)
+	if n > len(w.buckets) {
+		n = len(w.buckets)
+	}
+	var sum int64
+	for i := 0; i < n; i++ {
+		sum += w.buckets[(w.head-i+len(w.buckets))%len(w.buckets)]
+	}
+	return sum
+}
+
+// CountWithin returns the number of events within the last d, including the current partial bucket.
+// d is rounded up to a multiple of Resolution and capped at Window.
+func (w *SlidingWindow) CountWithin(d time.Duration) int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.count(d)
+}
+
+// Allow counts an event and returns true if less than limit events have been counted within the whole window.
+// Otherwise it returns false without counting.
+func (w *SlidingWindow) Allow(limit int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.count(w.Window()) >= limit {
+		return false
+	}
+	w.buckets[w.head]++
+	return true
+}
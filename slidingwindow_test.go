@@ -0,0 +1,67 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(start)
+	w := mockable.NewSlidingWindow(nower, mockable.SlidingWindowConfig{Window: 10 * time.Second})
+	require.Equal(10*time.Second, w.Window())
+
+	w.Incr()
+	nower.SetNow(start.Add(3 * time.Second))
+	w.IncrBy(2)
+	nower.SetNow(start.Add(5500 * time.Millisecond))
+	w.Incr()
+
+	require.Equal(int64(1), w.CountWithin(time.Second))
+	require.Equal(int64(3), w.CountWithin(3*time.Second))
+	require.Equal(int64(4), w.CountWithin(time.Hour))
+
+	// The first event slides out.
+	nower.SetNow(start.Add(10 * time.Second))
+	require.Equal(int64(3), w.CountWithin(10*time.Second))
+	nower.SetNow(start.Add(13 * time.Second))
+	require.Equal(int64(1), w.CountWithin(10*time.Second))
+	nower.SetNow(start.Add(time.Hour))
+	require.Equal(int64(0), w.CountWithin(10*time.Second))
+
+	// Admission control.
+	for i := 0; i < 3; i++ {
+		require.True(w.Allow(3))
+	}
+	require.False(w.Allow(3))
+	nower.SetNow(start.Add(time.Hour + 9*time.Second))
+	require.False(w.Allow(3))
+	nower.SetNow(start.Add(time.Hour + 10*time.Second))
+	require.True(w.Allow(3))
+}
+
+func TestSlidingWindow_resolution(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(start)
+	w := mockable.NewSlidingWindow(nower, mockable.SlidingWindowConfig{
+		Window:     time.Second,
+		Resolution: 300 * time.Millisecond,
+	})
+	require.Equal(1200*time.Millisecond, w.Window())
+
+	w.Incr()
+	nower.SetNow(start.Add(299 * time.Millisecond))
+	require.Equal(int64(1), w.CountWithin(time.Nanosecond))
+	nower.SetNow(start.Add(300 * time.Millisecond))
+	require.Equal(int64(0), w.CountWithin(time.Nanosecond))
+	require.Equal(int64(1), w.CountWithin(301*time.Millisecond))
+}
@@ -0,0 +1,141 @@
+package mockable
+
+import (
+	"testing"
+	"time"
+)
+
+// SoakInvariant checks the state of the code under test at the virtual time now, returning an error if it is broken.
+type SoakInvariant func(now time.Time) error
+
+// SoakConfig configures Soak. Zero fields are replaced with defaults.
+type SoakConfig struct {
+	// Horizon is how far the virtual time is advanced. Defaults to 4 years of 365 days,
+	// covering a leap day from any start.
+	Horizon time.Duration
+	// Step is the longest single advance. Defaults to 24h.
+	Step time.Duration
+	// CheckEvery is the virtual interval between invariant checks. Defaults to Step.
+	CheckEvery time.Duration
+	// Settle tells when the code under test has reacted to fires, e.g. re-armed its timers,
+	// so that the next step sees its next deadline. If nil, Soak does not wait.
+	Settle QuiescenceDetector
+	// SettleTimeout bounds the real time to wait for Settle after each step. Defaults to 1s.
+	SettleTimeout time.Duration
+}
+
+// SoakResult summarizes a run of Soak.
+type SoakResult struct {
+	// Steps is the number of advances, and Fires the number of fired timers and executed alarms.
+	Steps, Fires int
+	// Checks is the number of times the invariants were checked.
+	Checks int
+	// Start and End are the virtual times at which the run started and ended.
+	Start, End time.Time
+	// Elapsed is the real time the run took.
+	Elapsed time.Duration
+}
+
+// Soak advances v across a long horizon, years by default, in large steps,
+// and checks invariants periodically, to catch overflow, leap year and accumulation bugs
+// of scheduling code within seconds of test time.
+//
+// Each step advances v to the earliest of the next pending deadline, Step from now and the next check,
+// so every timer still fires at its own deadline, and then waits for Settle.
+// Invariants are called in order every CheckEvery of the virtual time and at the end.
+// The run stops at the first broken invariant, which is reported to t along with the virtual time.
+// It also stops if the virtual time does not advance, e.g. because v is paused at a barrier,
+// or the code under test does not settle within SettleTimeout.
+//
+// v must not be in the real-time mode or the auto-advance mode.
+func Soak(t testing.TB, v *VirtualClock, config SoakConfig, invariants ...SoakInvariant) SoakResult {
+	t.Helper()
+	if config.Horizon <= 0 {
+		config.Horizon = 4 * 365 * 24 * time.Hour
+	}
+	if config.Step <= 0 {
+		config.Step = 24 * time.Hour
+	}
+	if config.CheckEvery <= 0 {
+		config.CheckEvery = config.Step
+	}
+	if config.SettleTimeout <= 0 {
+		config.SettleTimeout = time.Second
+	}
+
+	started := time.Now()
+	result := SoakResult{Start: v.Now()}
+	end := result.Start.Add(config.Horizon)
+	nextCheck := result.Start.Add(config.CheckEvery)
+
+	check := func(now time.Time) bool {
+		result.Checks++
+		for i, inv := range invariants {
+			if err := inv(now); err != nil {
+				t.Errorf("mockable: soak: invariant #%d broken at %s (+%s): %v",
+					i, now.Format(time.RFC3339Nano), now.Sub(result.Start), err)
+				return false
+			}
+		}
+		return true
+	}
+
+	for now := result.Start; now.Before(end); {
+		target := now.Add(config.Step)
+		if next, ok := v.Next(); ok && next.Before(target) {
+			target = next
+		}
+		if nextCheck.Before(target) {
+			target = nextCheck
+		}
+		if end.Before(target) {
+			target = end
+		}
+		result.Fires += v.AdvanceTo(target)
+		result.Steps++
+
+		if config.Settle != nil && !soakSettle(v, config.Settle, config.SettleTimeout) {
+			t.Errorf("mockable: soak: code did not settle within %s at %s", config.SettleTimeout, target.Format(time.RFC3339Nano))
+			break
+		}
+
+		after := v.Now()
+		if after.Before(target) {
+			t.Errorf("mockable: soak: virtual time stuck at %s, short of %s; is it paused at a barrier?",
+				after.Format(time.RFC3339Nano), target.Format(time.RFC3339Nano))
+			break
+		}
+		now = after
+		if !now.Before(nextCheck) || !now.Before(end) {
+			if !check(now) {
+				break
+			}
+			for !now.Before(nextCheck) {
+				nextCheck = nextCheck.Add(config.CheckEvery)
+			}
+		}
+	}
+
+	result.End = v.Now()
+	result.Elapsed = time.Since(started)
+	return result
+}
+
+// soakSettle polls detector until it reports v is idle. It returns false if timeout elapses first.
+func soakSettle(v *VirtualClock, detector QuiescenceDetector, timeout time.Duration) bool {
+	if detector.Quiescent(v) {
+		return true
+	}
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(autoAdvancePoll)
+	defer ticker.Stop()
+	for range ticker.C {
+		if detector.Quiescent(v) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+	}
+	return false
+}
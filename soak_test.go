@@ -0,0 +1,130 @@
+package mockable_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoak(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	defer v.Close()
+
+	// A job on every leap day, at midnight.
+	s := mockable.NewScheduler(v.NewTimer(), time.UTC)
+	var (
+		mu    sync.Mutex
+		fired []time.Time
+	)
+	_, err := s.AddCron("0 0 29 2 *", func(scheduled time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		fired = append(fired, scheduled)
+	})
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	require.Eventually(func() bool { return v.Pending() == 1 }, time.Second, time.Millisecond)
+
+	result := mockable.Soak(t, v, mockable.SoakConfig{
+		Horizon:    8 * 365 * 24 * time.Hour,
+		CheckEvery: 30 * 24 * time.Hour,
+		Settle:     mockable.ArmedAtLeast(1),
+	}, func(now time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, f := range fired {
+			if f.Month() != time.February || f.Day() != 29 {
+				return fmt.Errorf("fired on %s", f)
+			}
+		}
+		if len(fired) > 0 && now.Sub(fired[len(fired)-1]) > 4*366*24*time.Hour {
+			return fmt.Errorf("no fire since %s", fired[len(fired)-1])
+		}
+		return nil
+	})
+
+	require.False(t.Failed())
+	require.Equal(start, result.Start)
+	require.Equal(start.Add(8*365*24*time.Hour), result.End)
+	require.Equal(2, result.Fires)
+	require.Greater(result.Checks, 90)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal([]time.Time{
+		time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+	}, fired)
+}
+
+func TestSoak_broken(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	defer v.Close()
+
+	// A "monthly" job approximating a month by 30 days drifts off the 1st.
+	timer := v.NewTimer()
+	var (
+		mu   sync.Mutex
+		last time.Time
+	)
+	stop := make(chan struct{})
+	defer close(stop)
+	timer.Reset(31 * 24 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case at := <-timer.C():
+				mu.Lock()
+				last = at
+				mu.Unlock()
+				timer.Reset(30 * 24 * time.Hour)
+			}
+		}
+	}()
+
+	tb := &fakeTB{TB: t}
+	result := mockable.Soak(tb, v, mockable.SoakConfig{Settle: mockable.ArmedAtLeast(1)}, func(now time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !last.IsZero() && last.Day() != 1 {
+			return fmt.Errorf("fired on %s", last.Format("2006-01-02"))
+		}
+		return nil
+	})
+
+	require.True(tb.failed)
+	require.Len(tb.logs, 1)
+	require.Contains(tb.logs[0], "invariant #0 broken at 2023-03-03")
+	require.Contains(tb.logs[0], "fired on 2023-03-03")
+	require.Equal(time.Date(2023, 3, 3, 0, 0, 0, 0, time.UTC), result.End)
+}
+
+func TestSoak_paused(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	defer v.Close()
+	v.AddBarrier(start.Add(36 * time.Hour))
+
+	tb := &fakeTB{TB: t}
+	result := mockable.Soak(tb, v, mockable.SoakConfig{})
+	require.True(tb.failed)
+	require.Contains(tb.logs[0], "paused at a barrier")
+	require.Equal(start.Add(36*time.Hour), result.End)
+}
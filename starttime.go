@@ -0,0 +1,48 @@
+package mockable
+
+import (
+	"hash/fnv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	startTimeBase = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	// startTimeSpan is in seconds.
+	startTimeSpan = uint64(time.Date(2011, 1, 1, 0, 0, 0, 0, time.UTC).Sub(startTimeBase) / time.Second)
+
+	startTimeMu    sync.Mutex
+	startTimeNames = map[time.Time]string{}
+)
+
+// StartTimeFor returns a base time derived from the name of t.
+//
+// The time is stable across runs and distinct among tests as long as their names differ,
+// so tests do not accidentally share magic constants.
+// It is a whole second in UTC between 2001 and 2010, far from the real current time,
+// so that mixing up the fake time and the real time is easily noticed.
+// If two tests in the process derive the same time, t is marked as failed to make the collision visible;
+// renaming either test resolves it.
+func StartTimeFor(t testing.TB) time.Time {
+	t.Helper()
+	name := t.Name()
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	start := startTimeBase.Add(time.Duration(h.Sum64()%startTimeSpan) * time.Second)
+
+	startTimeMu.Lock()
+	defer startTimeMu.Unlock()
+	if other, ok := startTimeNames[start]; ok && other != name {
+		t.Errorf("mockable: StartTimeFor: %q derives the same time %s as %q", name, start.Format(time.RFC3339), other)
+	} else {
+		startTimeNames[start] = name
+	}
+	return start
+}
+
+// NewClockFakeT returns a ClockFake starting at StartTimeFor(t).
+func NewClockFakeT(t testing.TB, options ...ClockFakeOption) *ClockFake {
+	t.Helper()
+	return NewClockFake(StartTimeFor(t), options...)
+}
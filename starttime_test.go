@@ -0,0 +1,52 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+type namedTB struct {
+	fakeTB
+	name string
+}
+
+func (t *namedTB) Name() string { return t.name }
+
+func TestStartTimeFor(t *testing.T) {
+	require := require.New(t)
+
+	start := mockable.StartTimeFor(t)
+	require.Equal(start, mockable.StartTimeFor(t))
+	require.Equal(time.UTC, start.Location())
+	require.Equal(start, start.Truncate(time.Second))
+	require.False(start.Before(time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.True(start.Before(time.Date(2011, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	seen := map[time.Time]string{}
+	for _, name := range []string{"a", "b", "TestFoo/sub_1", "TestFoo/sub_2"} {
+		tb := &namedTB{fakeTB: fakeTB{TB: t}, name: name}
+		s := mockable.StartTimeFor(tb)
+		require.False(tb.failed)
+		_, dup := seen[s]
+		require.False(dup)
+		seen[s] = name
+	}
+
+	c := mockable.NewClockFakeT(t)
+	require.Equal(start, c.Now())
+
+	t.Run("parallel", func(t *testing.T) {
+		for _, name := range []string{"x", "y"} {
+			name := name
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				if mockable.StartTimeFor(t).Equal(start) {
+					t.Errorf("subtest shares the start time with the parent")
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package mockable
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// The Stdio is a mockable interface of the standard streams.
+type Stdio interface {
+	Stdin() io.Reader
+	Stdout() io.Writer
+	Stderr() io.Writer
+}
+
+var _ Stdio = StdioReal{}
+
+// StdioReal is an implementation of the Stdio interface.
+// It returns os.Stdin, os.Stdout and os.Stderr.
+type StdioReal struct{}
+
+func (StdioReal) Stdin() io.Reader {
+	return os.Stdin
+}
+
+func (StdioReal) Stdout() io.Writer {
+	return os.Stdout
+}
+
+func (StdioReal) Stderr() io.Writer {
+	return os.Stderr
+}
+
+var _ Stdio = (*StdioFake)(nil)
+
+// StdioFake is an implementation of the Stdio interface backed by buffers.
+// Stdin reads from the given input. Outputs written to Stdout and Stderr
+// are retrievable by OutString and ErrString.
+// All streams are safe for concurrent use.
+type StdioFake struct {
+	in  lockedBuffer
+	out lockedBuffer
+	err lockedBuffer
+}
+
+// NewStdioFake returns a StdioFake whose Stdin reads input.
+func NewStdioFake(input string) *StdioFake {
+	s := &StdioFake{}
+	s.in.buf.WriteString(input)
+	return s
+}
+
+func (s *StdioFake) Stdin() io.Reader {
+	return &s.in
+}
+
+func (s *StdioFake) Stdout() io.Writer {
+	return &s.out
+}
+
+func (s *StdioFake) Stderr() io.Writer {
+	return &s.err
+}
+
+// WriteIn appends p to the unread portion of Stdin.
+func (s *StdioFake) WriteIn(p string) {
+	_, _ = io.Copy(&s.in, strings.NewReader(p))
+}
+
+// OutString returns everything written to Stdout.
+func (s *StdioFake) OutString() string {
+	return s.out.String()
+}
+
+// ErrString returns everything written to Stderr.
+func (s *StdioFake) ErrString() string {
+	return s.err.String()
+}
+
+// Reset discards everything written to Stdout and Stderr.
+func (s *StdioFake) Reset() {
+	s.out.Reset()
+	s.err.Reset()
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Read(p)
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
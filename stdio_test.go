@@ -0,0 +1,54 @@
+package mockable_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdioReal(t *testing.T) {
+	require := require.New(t)
+
+	s := mockable.StdioReal{}
+	require.Equal(os.Stdin, s.Stdin())
+	require.Equal(os.Stdout, s.Stdout())
+	require.Equal(os.Stderr, s.Stderr())
+}
+
+func TestStdioFake(t *testing.T) {
+	require := require.New(t)
+
+	prompt := func(s mockable.Stdio) (string, error) {
+		fmt.Fprint(s.Stdout(), "name? ")
+		sc := bufio.NewScanner(s.Stdin())
+		if !sc.Scan() {
+			fmt.Fprintln(s.Stderr(), "no input")
+			return "", io.ErrUnexpectedEOF
+		}
+		fmt.Fprintf(s.Stdout(), "hello %s\n", sc.Text())
+		return sc.Text(), nil
+	}
+
+	s := mockable.NewStdioFake("foo\n")
+	name, err := prompt(s)
+	require.NoError(err)
+	require.Equal("foo", name)
+	require.Equal("name? hello foo\n", s.OutString())
+	require.Equal("", s.ErrString())
+
+	s.Reset()
+	_, err = prompt(s)
+	require.ErrorIs(err, io.ErrUnexpectedEOF)
+	require.Equal("name? ", s.OutString())
+	require.Equal("no input\n", s.ErrString())
+
+	s.WriteIn("bar\n")
+	name, err = prompt(s)
+	require.NoError(err)
+	require.Equal("bar", name)
+}
@@ -0,0 +1,118 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// Stopwatch measures elapsed time and laps using the injected Nower.
+// The zero value is not usable; use NewStopwatch.
+type Stopwatch struct {
+	mu      sync.Mutex
+	nower   Nower
+	running bool
+	started bool
+	start   time.Time
+	lapFrom time.Time
+	stopped time.Time
+	laps    []time.Duration
+}
+
+// NewStopwatch returns a stopped Stopwatch.
+func NewStopwatch(nower Nower) *Stopwatch {
+	return &Stopwatch{nower: nower}
+}
+
+// Start starts s from zero, discarding previous laps.
+func (s *Stopwatch) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.nower.Now()
+	s.running, s.started = true, true
+	s.start, s.lapFrom = now, now
+	s.laps = nil
+}
+
+// Stop stops s. Elapsed keeps returning the time elapsed until Stop.
+func (s *Stopwatch) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running {
+		return
+	}
+	s.running = false
+	s.stopped = s.nower.Now()
+}
+
+// Running reports whether s is started and not stopped.
+func (s *Stopwatch) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Lap records and returns the time elapsed since the previous Lap, or since Start for the first one.
+// It returns 0 and records nothing if s is not running.
+func (s *Stopwatch) Lap() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running {
+		return 0
+	}
+	now := s.nower.Now()
+	lap := now.Sub(s.lapFrom)
+	s.lapFrom = now
+	s.laps = append(s.laps, lap)
+	return lap
+}
+
+// Laps returns the laps recorded since Start.
+func (s *Stopwatch) Laps() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Duration(nil), s.laps...)
+}
+
+// Elapsed returns the time elapsed since Start, until now or Stop.
+// It returns 0 if s has never been started.
+func (s *Stopwatch) Elapsed() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.running:
+		return s.nower.Now().Sub(s.start)
+	case !s.started:
+		return 0
+	default:
+		return s.stopped.Sub(s.start)
+	}
+}
+
+// Elapser wraps function calls to capture their durations, measured by the injected Nower.
+type Elapser struct {
+	nower  Nower
+	record func(d time.Duration, err error)
+}
+
+// NewElapser returns an Elapser passing every captured duration to record.
+// record may be nil.
+func NewElapser(nower Nower, record func(d time.Duration, err error)) *Elapser {
+	return &Elapser{nower: nower, record: record}
+}
+
+// Do calls fn, records its duration and error, and returns the error.
+func (e *Elapser) Do(fn func() error) error {
+	_, err := e.Measure(fn)
+	return err
+}
+
+// Measure calls fn, records and returns its duration and error.
+func (e *Elapser) Measure(fn func() error) (time.Duration, error) {
+	start := e.nower.Now()
+	err := fn()
+	d := e.nower.Now().Sub(start)
+	if e.record != nil {
+		e.record(d, err)
+	}
+	return d, err
+}
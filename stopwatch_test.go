@@ -0,0 +1,83 @@
+package mockable_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopwatch(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(now)
+
+	s := mockable.NewStopwatch(nower)
+	require.False(s.Running())
+	require.Equal(time.Duration(0), s.Elapsed())
+	require.Equal(time.Duration(0), s.Lap())
+
+	s.Start()
+	nower.SetNow(now.Add(time.Second))
+	require.Equal(time.Second, s.Lap())
+	nower.SetNow(now.Add(3 * time.Second))
+	require.Equal(2*time.Second, s.Lap())
+	nower.SetNow(now.Add(3500 * time.Millisecond))
+	require.Equal(3500*time.Millisecond, s.Elapsed())
+
+	s.Stop()
+	nower.SetNow(now.Add(time.Hour))
+	require.False(s.Running())
+	require.Equal(3500*time.Millisecond, s.Elapsed())
+	require.Equal([]time.Duration{time.Second, 2 * time.Second}, s.Laps())
+
+	s.Start()
+	require.Empty(s.Laps())
+	require.Equal(time.Duration(0), s.Elapsed())
+}
+
+func TestStopwatch_zero_time(t *testing.T) {
+	require := require.New(t)
+
+	// A clock starting at the zero time.
+	clock := mockable.NewClockFake(time.Time{})
+	s := mockable.NewStopwatch(clock)
+	require.Equal(time.Duration(0), s.Elapsed())
+
+	s.Start()
+	clock.SetNow(time.Time{}.Add(time.Second))
+	s.Stop()
+	require.Equal(time.Second, s.Elapsed())
+}
+
+func TestElapser(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	nower := &mockable.NowerFake{}
+	nower.SetNow(now)
+
+	var recorded []time.Duration
+	e := mockable.NewElapser(nower, func(d time.Duration, err error) {
+		recorded = append(recorded, d)
+	})
+
+	sampleErr := errors.New("sample")
+	err := e.Do(func() error {
+		nower.SetNow(now.Add(150 * time.Millisecond))
+		return sampleErr
+	})
+	require.ErrorIs(err, sampleErr)
+
+	d, err := e.Measure(func() error {
+		nower.SetNow(now.Add(time.Second))
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(850*time.Millisecond, d)
+	require.Equal([]time.Duration{150 * time.Millisecond, 850 * time.Millisecond}, recorded)
+}
@@ -0,0 +1,236 @@
+package mockable
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// StressOp is an operation made by StressClock.
+type StressOp int
+
+const (
+	// StressReset calls Reset with a random duration.
+	StressReset StressOp = iota
+	// StressStop calls Stop.
+	StressStop
+	// StressSend calls Send of the ClockFake.
+	StressSend
+	// StressNow calls Now.
+	StressNow
+)
+
+func (o StressOp) String() string {
+	switch o {
+	case StressReset:
+		return "reset"
+	case StressStop:
+		return "stop"
+	case StressSend:
+		return "send"
+	case StressNow:
+		return "now"
+	}
+	return "unknown"
+}
+
+// StressConfig configures StressClock. Zero fields are replaced with defaults.
+type StressConfig struct {
+	// Goroutines is the number of goroutines making operations concurrently. Defaults to 8.
+	Goroutines int
+	// Ops is the number of operations each goroutine makes. Defaults to 1000.
+	Ops int
+	// Weights is the relative frequency of each StressOp. Defaults to equal weights.
+	Weights map[StressOp]int
+	// MaxReset bounds durations passed to Reset. Defaults to 1s.
+	MaxReset time.Duration
+	// Seed seeds the schedule. The same seed makes each goroutine make the same sequence of operations,
+	// though how they interleave is up to the Go scheduler.
+	Seed int64
+	// Yield makes goroutines call runtime.Gosched between operations for finer interleaving.
+	Yield bool
+}
+
+// StressResult is the number of operations made by StressClock.
+type StressResult struct {
+	Resets, Stops, Sends, Nows int
+	// Delivered is the number of sends reported delivered by TrySend.
+	Delivered int
+	// Received is the number of values received from C.
+	Received int
+}
+
+// StressClock hammers fake with Reset, Stop, Send and Now from many goroutines,
+// while another goroutine keeps receiving from C, and reports violated invariants to t.
+//
+// Reset, Stop, Now and C are called through clock, which may be a wrapper or a decorator of fake
+// forwarding every Reset and Stop to it. If clock is nil, fake itself is used.
+// Send is always called on fake.
+//
+// The invariants checked are:
+//   - every received value and fake.Now() satisfy the FireInvariant of fake,
+//   - fake.Now() never goes backwards, unless values may be given up by SendDrop or SendError,
+//   - fake records every Reset and Stop made through clock, exactly once,
+//   - no more values are received than delivered, and nothing is left sending.
+//
+// Data races are reported only when the test binary is built with -race; see RaceEnabled.
+// fake must not be in the strict mode, as random operations break the Timer contract on purpose.
+func StressClock(t testing.TB, fake *ClockFake, clock Clock, config StressConfig) StressResult {
+	t.Helper()
+	if config.Goroutines <= 0 {
+		config.Goroutines = 8
+	}
+	if config.Ops <= 0 {
+		config.Ops = 1000
+	}
+	if config.MaxReset <= 0 {
+		config.MaxReset = time.Second
+	}
+	if clock == nil {
+		clock = fake
+	}
+
+	fake.Lock()
+	strict, latch, policy, invariant := fake.strict, fake.latch, fake.policy, fake.invariant
+	fake.Unlock()
+	if strict {
+		t.Fatalf("mockable: StressClock: fake must not be in the strict mode")
+	}
+	if !RaceEnabled {
+		t.Logf("mockable: StressClock: the race detector is disabled; build with -race to detect data races")
+	}
+	monotonic := latch || policy == SendBlock || policy == SendQueue
+
+	var (
+		weights []int
+		total   int
+	)
+	for op := StressReset; op <= StressNow; op++ {
+		w := 1
+		if config.Weights != nil {
+			w = config.Weights[op]
+		}
+		if w < 0 {
+			w = 0
+		}
+		weights = append(weights, w)
+		total += w
+	}
+	if total == 0 {
+		t.Fatalf("mockable: StressClock: all weights are zero")
+	}
+
+	stop := make(chan struct{})
+	recvDone := make(chan int)
+	go func() {
+		var received int
+		check := func(v time.Time) {
+			received++
+			if now := fake.Now(); !invariant.holds(v, now) {
+				t.Errorf("mockable: StressClock: received %s and Now() = %s break FireInvariant %s", v, now, invariant)
+			}
+		}
+		for {
+			select {
+			case v := <-clock.C():
+				check(v)
+			case <-stop:
+				// Let queued values be delivered so that the pump goroutine exits.
+				for fake.Queued() > 0 || fake.IsSending() {
+					select {
+					case v := <-clock.C():
+						check(v)
+					case <-time.After(time.Millisecond):
+					}
+				}
+				recvDone <- received
+				return
+			}
+		}
+	}()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results StressResult
+	)
+	for i := 0; i < config.Goroutines; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			var local StressResult
+			r := rand.New(rand.NewSource(seed))
+			var last time.Time
+			for j := 0; j < config.Ops; j++ {
+				n := r.Intn(total)
+				op := StressReset
+				for n >= weights[op] {
+					n -= weights[op]
+					op++
+				}
+				switch op {
+				case StressReset:
+					clock.Reset(time.Duration(r.Int63n(int64(config.MaxReset) + 1)))
+					local.Resets++
+				case StressStop:
+					clock.Stop()
+					local.Stops++
+				case StressSend:
+					if _, delivered, _ := fake.TrySend(); delivered {
+						local.Delivered++
+					}
+					local.Sends++
+				case StressNow:
+					clock.Now()
+					local.Nows++
+				}
+				now := fake.Now()
+				if monotonic && now.Before(last) {
+					t.Errorf("mockable: StressClock: Now() went backwards from %s to %s after %s", last, now, op)
+				}
+				last = now
+				if config.Yield {
+					runtime.Gosched()
+				}
+			}
+			mu.Lock()
+			results.Resets += local.Resets
+			results.Stops += local.Stops
+			results.Sends += local.Sends
+			results.Nows += local.Nows
+			results.Delivered += local.Delivered
+			mu.Unlock()
+		}(config.Seed + int64(i))
+	}
+	wg.Wait()
+	close(stop)
+	results.Received = <-recvDone
+
+	var resets, stops int
+	for _, e := range fake.History() {
+		switch e.Kind {
+		case ClockEventReset:
+			resets++
+		case ClockEventStop:
+			stops++
+		}
+	}
+	if resets != results.Resets || stops != results.Stops {
+		t.Errorf(
+			"mockable: StressClock: fake recorded %d Reset and %d Stop, but %d and %d are made",
+			resets, stops, results.Resets, results.Stops,
+		)
+	}
+	if n := len(fake.CloneResetArg()); n != resets+stops {
+		t.Errorf("mockable: StressClock: %d Reset arguments are recorded for %d Reset and Stop", n, resets+stops)
+	}
+	if results.Received > results.Delivered {
+		t.Errorf("mockable: StressClock: received %d values but only %d are delivered", results.Received, results.Delivered)
+	}
+	if s := fake.State(); s == ClockSending {
+		t.Errorf("mockable: StressClock: fake is left %s", s)
+	}
+	return results
+}
@@ -0,0 +1,64 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStressClock(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	for name, options := range map[string][]mockable.ClockFakeOption{
+		"block": nil,
+		"drop":  {mockable.WithSendPolicy(mockable.SendDrop)},
+		"queue": {mockable.WithSendPolicy(mockable.SendQueue)},
+		"error": {
+			mockable.WithSendPolicy(mockable.SendError),
+			mockable.WithSendGracePeriod(time.Millisecond),
+		},
+		"latch": {mockable.WithLatch()},
+		"equal": {mockable.WithFireInvariant(mockable.FireEqualAllowed)},
+	} {
+		options := options
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			fake := mockable.NewClockFake(now, options...)
+			result := mockable.StressClock(t, fake, nil, mockable.StressConfig{Ops: 200, Seed: 1, Yield: true})
+			require.Equal(8*200, result.Resets+result.Stops+result.Sends+result.Nows)
+			require.LessOrEqual(result.Received, result.Delivered)
+		})
+	}
+}
+
+func TestStressClock_weights(t *testing.T) {
+	require := require.New(t)
+
+	fake := mockable.NewClockFake(time.Time{})
+	result := mockable.StressClock(t, fake, nil, mockable.StressConfig{
+		Goroutines: 2,
+		Ops:        100,
+		Weights:    map[mockable.StressOp]int{mockable.StressReset: 1, mockable.StressNow: 1},
+	})
+	require.Zero(result.Stops)
+	require.Zero(result.Sends)
+	require.Equal(200, result.Resets+result.Nows)
+}
+
+// stopSwallower is a broken decorator which forwards no Stop.
+type stopSwallower struct {
+	mockable.Clock
+}
+
+func (stopSwallower) Stop() bool { return false }
+
+func TestStressClock_brokenDecorator(t *testing.T) {
+	require := require.New(t)
+
+	fake := mockable.NewClockFake(time.Time{})
+	tb := &fakeTB{TB: t}
+	mockable.StressClock(tb, fake, stopSwallower{fake}, mockable.StressConfig{Goroutines: 2, Ops: 100})
+	require.True(tb.failed)
+	require.Contains(tb.logs[len(tb.logs)-1], "recorded")
+}
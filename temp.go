@@ -0,0 +1,145 @@
+package mockable
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The TempProvider is a mockable interface for creating temporary directories and files.
+type TempProvider interface {
+	// MkdirTemp creates a new temporary directory in the directory dir and returns its path.
+	// If dir is the empty string, a default directory is used.
+	// The name is generated by taking pattern and replacing the last "*" with a random string.
+	MkdirTemp(dir, pattern string) (string, error)
+	// CreateTemp creates a new temporary file in the directory dir
+	// in the same manner as MkdirTemp, and opens it for writing.
+	// The path of the file can be retrieved by calling Name on the returned File.
+	CreateTemp(dir, pattern string) (File, error)
+}
+
+var _ TempProvider = TempReal{}
+
+// TempReal is an implementation of the TempProvider interface.
+// It only wraps os.MkdirTemp and os.CreateTemp.
+type TempReal struct{}
+
+func (TempReal) MkdirTemp(dir, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
+func (TempReal) CreateTemp(dir, pattern string) (File, error) {
+	return os.CreateTemp(dir, pattern)
+}
+
+var _ TempProvider = (*TempFake)(nil)
+
+// TempFake is an implementation of the TempProvider interface
+// which creates temporary directories and files in FS.
+//
+// Generated names are deterministic: the last "*" in a pattern is replaced with
+// a sequence number, starting from 1 and incremented by every call.
+// Every created path is recorded so that tests can assert on them
+// and verify that the code under test cleaned them up.
+type TempFake struct {
+	mu      sync.Mutex
+	fsys    FS
+	dir     string
+	seq     int
+	created []string
+}
+
+// NewTempFake returns a TempFake creating temporary files in fsys.
+// defaultDir is used when dir is the empty string. It is created if it does not exist.
+func NewTempFake(fsys FS, defaultDir string) *TempFake {
+	return &TempFake{
+		fsys: fsys,
+		dir:  defaultDir,
+	}
+}
+
+func (t *TempFake) next(op, dir, pattern string) (string, error) {
+	if strings.Contains(pattern, "/") {
+		return "", &fs.PathError{Op: op, Path: pattern, Err: errors.New("pattern contains path separator")}
+	}
+	if dir == "" {
+		dir = t.dir
+		if err := t.fsys.MkdirAll(dir, fs.ModePerm); err != nil {
+			return "", err
+		}
+	}
+
+	t.seq++
+	seq := strconv.Itoa(t.seq)
+	var name string
+	if i := strings.LastIndex(pattern, "*"); i >= 0 {
+		name = pattern[:i] + seq + pattern[i+1:]
+	} else {
+		name = pattern + seq
+	}
+	return path.Join(dir, name), nil
+}
+
+func (t *TempFake) MkdirTemp(dir, pattern string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	name, err := t.next("mkdirtemp", dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fs.Stat(t.fsys, name); err == nil {
+		return "", &fs.PathError{Op: "mkdirtemp", Path: name, Err: fs.ErrExist}
+	}
+	if err := t.fsys.MkdirAll(name, 0o700); err != nil {
+		return "", err
+	}
+	t.created = append(t.created, name)
+	return name, nil
+}
+
+func (t *TempFake) CreateTemp(dir, pattern string) (File, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	name, err := t.next("createtemp", dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fs.Stat(t.fsys, name); err == nil {
+		return nil, &fs.PathError{Op: "createtemp", Path: name, Err: fs.ErrExist}
+	}
+	f, err := t.fsys.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	t.created = append(t.created, name)
+	return f, nil
+}
+
+// Created returns every path created by t in creation order.
+func (t *TempFake) Created() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]string, len(t.created))
+	copy(out, t.created)
+	return out
+}
+
+// Remaining returns paths created by t which still exist in the underlying FS.
+// Tests can call this after the code under test finished to verify its cleanup.
+func (t *TempFake) Remaining() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []string
+	for _, name := range t.created {
+		if _, err := fs.Stat(t.fsys, name); err == nil {
+			out = append(out, name)
+		}
+	}
+	return out
+}
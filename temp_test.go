@@ -0,0 +1,66 @@
+package mockable_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTempReal(t *testing.T) {
+	require := require.New(t)
+
+	base := t.TempDir()
+	var tp mockable.TempProvider = mockable.TempReal{}
+
+	dir, err := tp.MkdirTemp(base, "foo-*")
+	require.NoError(err)
+	require.True(strings.HasPrefix(filepath.Base(dir), "foo-"))
+	stat, err := os.Stat(dir)
+	require.NoError(err)
+	require.True(stat.IsDir())
+
+	f, err := tp.CreateTemp(dir, "bar-*.txt")
+	require.NoError(err)
+	_, err = f.Write([]byte("bar"))
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	bin, err := os.ReadFile(f.Name())
+	require.NoError(err)
+	require.Equal("bar", string(bin))
+}
+
+func TestTempFake(t *testing.T) {
+	require := require.New(t)
+
+	fsys := mockable.NewFSFake(mockable.NowerReal{})
+	tp := mockable.NewTempFake(fsys, "tmp")
+
+	dir, err := tp.MkdirTemp("", "foo-*")
+	require.NoError(err)
+	require.Equal("tmp/foo-1", dir)
+
+	f, err := tp.CreateTemp(dir, "bar-*.txt")
+	require.NoError(err)
+	require.Equal("tmp/foo-1/bar-2.txt", f.Name())
+	require.NoError(f.Close())
+
+	f, err = tp.CreateTemp("", "baz")
+	require.NoError(err)
+	require.Equal("tmp/baz3", f.Name())
+	require.NoError(f.Close())
+
+	_, err = tp.CreateTemp("", "a/b")
+	require.Error(err)
+
+	require.Equal([]string{"tmp/foo-1", "tmp/foo-1/bar-2.txt", "tmp/baz3"}, tp.Created())
+	require.Equal(tp.Created(), tp.Remaining())
+
+	require.NoError(fsys.Remove("tmp/foo-1/bar-2.txt"))
+	require.NoError(fsys.Remove("tmp/foo-1"))
+	require.Equal([]string{"tmp/baz3"}, tp.Remaining())
+}
@@ -0,0 +1,87 @@
+package mockable
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Throttler permits at most one invocation per interval measured by the injected Clock.
+//
+// Allow and Do drop invocations exceeding the rate, while Wait blocks until the next slot.
+type Throttler struct {
+	mu       sync.Mutex
+	waitMu   sync.Mutex
+	clock    Clock
+	interval time.Duration
+	last     time.Time
+	used     bool
+}
+
+// NewThrottler returns a Throttler permitting one invocation per interval.
+// clock's timer is exclusively used by Wait.
+func NewThrottler(clock Clock, interval time.Duration) *Throttler {
+	return &Throttler{
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// next returns the earliest time at which the next invocation is permitted. t.mu must be held.
+func (t *Throttler) next(now time.Time) time.Time {
+	if !t.used {
+		return now
+	}
+	if next := t.last.Add(t.interval); next.After(now) {
+		return next
+	}
+	return now
+}
+
+// Allow reports whether an invocation is permitted now.
+// If it returns true, the invocation is accounted.
+func (t *Throttler) Allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.clock.Now()
+	if t.next(now).After(now) {
+		return false
+	}
+	t.last, t.used = now, true
+	return true
+}
+
+// Do calls fn if an invocation is permitted now, and reports whether fn was called.
+func (t *Throttler) Do(fn func()) bool {
+	if !t.Allow() {
+		return false
+	}
+	fn()
+	return true
+}
+
+// Wait blocks until an invocation is permitted, and accounts it.
+// Concurrent callers are served one at a time.
+// If ctx is done before that, Wait returns ctx.Err() without accounting the invocation.
+func (t *Throttler) Wait(ctx context.Context) error {
+	t.waitMu.Lock()
+	defer t.waitMu.Unlock()
+
+	t.mu.Lock()
+	now := t.clock.Now()
+	at := t.next(now)
+	prevLast, prevUsed := t.last, t.used
+	// Reserve the slot so that Allow does not take it meanwhile.
+	t.last, t.used = at, true
+	t.mu.Unlock()
+
+	if err := SleepContext(ctx, t.clock, at.Sub(now)); err != nil {
+		t.mu.Lock()
+		if t.last.Equal(at) {
+			t.last, t.used = prevLast, prevUsed
+		}
+		t.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottler_drop(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	th := mockable.NewThrottler(clock, time.Second)
+
+	count := 0
+	inc := func() { count++ }
+
+	require.True(th.Do(inc))
+	require.False(th.Do(inc))
+	clock.SetNow(now.Add(999 * time.Millisecond))
+	require.False(th.Allow())
+	clock.SetNow(now.Add(time.Second))
+	require.True(th.Do(inc))
+	require.False(th.Allow())
+	require.Equal(2, count)
+}
+
+func TestThrottler_wait(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+	th := mockable.NewThrottler(clock, time.Second)
+
+	require.NoError(th.Wait(context.Background()))
+
+	errCh := make(chan error)
+	go func() { errCh <- th.Wait(context.Background()) }()
+	require.Equal(time.Second, <-clock.ResetCh)
+	// The slot is reserved by the waiter.
+	require.False(th.Allow())
+	clock.Send()
+	require.NoError(<-errCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { errCh <- th.Wait(ctx) }()
+	require.Equal(time.Second-1, <-clock.ResetCh)
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+
+	// The cancelled reservation is released.
+	clock.SetNow(now.Add(2500 * time.Millisecond))
+	require.True(th.Allow())
+}
@@ -0,0 +1,262 @@
+package mockable
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The Ticker is a mockable interface equivalent to the time.Ticker.
+//
+// Like the Timer, a New function for the Ticker must create it at stopped state.
+// Reset starts the ticker if it is stopped.
+type Ticker interface {
+	// C is equivalent of ticker.C
+	C() <-chan time.Time
+	// Stop turns off the ticker. After Stop, no more ticks will be sent.
+	Stop()
+	// Reset stops the ticker and resets its period to the specified duration.
+	// The next tick will arrive after the new period elapses.
+	// d must be greater than zero.
+	Reset(d time.Duration)
+}
+
+var _ Ticker = (*TickerReal)(nil)
+
+// TickerReal implements Ticker using a runtime ticker.
+type TickerReal struct {
+	T *time.Ticker
+}
+
+// NewTickerReal returns newly created TickerReal.
+// This creates stopped ticker unlike time.NewTicker.
+func NewTickerReal() *TickerReal {
+	ticker := time.NewTicker(30 * 365 * 24 * time.Hour)
+	ticker.Stop()
+	return &TickerReal{
+		T: ticker,
+	}
+}
+
+func (t *TickerReal) C() <-chan time.Time {
+	return t.T.C
+}
+
+func (t *TickerReal) Stop() {
+	t.T.Stop()
+}
+
+func (t *TickerReal) Reset(d time.Duration) {
+	t.T.Reset(d)
+}
+
+var (
+	_ Ticker             = (*TickerFake)(nil)
+	_ MissedTickReporter = (*TickerFake)(nil)
+)
+
+// TickerFake is a fake Ticker driven by Send.
+//
+// Ticks are scheduled at exact multiples of the period from the last Reset;
+// Send emits the next scheduled tick and steps the current time slightly after it,
+// so that (<-t.C()).Before(t.Now()) holds as is for ClockFake.
+// Advance jumps the time, coalescing ticks due meanwhile as the runtime ticker does.
+type TickerFake struct {
+	mu      sync.Mutex
+	name    string
+	current time.Time
+	next    time.Time
+	period  time.Duration
+	running bool
+	missed  []MissedTicks
+	count   int
+	format  emitFormat
+
+	TimeCh chan time.Time
+	// ResetCh can be used to synchronize to or wait for Reset calls.
+	// If an instance is initialized with NewTickerFake, ResetCh is buffered with size of 1.
+	ResetCh chan time.Duration
+	// StopCh can be used to synchronize to or wait for Stop calls.
+	// If an instance is initialized with NewTickerFake, StopCh is buffered with size of 1.
+	StopCh chan struct{}
+}
+
+// TickerFakeOption configures TickerFake.
+type TickerFakeOption func(t *TickerFake)
+
+func NewTickerFake(current time.Time, options ...TickerFakeOption) *TickerFake {
+	t := &TickerFake{
+		current: current,
+		TimeCh:  make(chan time.Time),
+		ResetCh: make(chan time.Duration, 1),
+		StopCh:  make(chan struct{}, 1),
+	}
+	for _, opt := range options {
+		opt(t)
+	}
+	return t
+}
+
+// Now implements Nower.
+func (t *TickerFake) Now() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.format.apply(t.current)
+}
+
+func (t *TickerFake) SetNow(now time.Time) (prev time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current, prev = now, t.current
+	return prev
+}
+
+func (t *TickerFake) C() <-chan time.Time {
+	return t.TimeCh
+}
+
+// Reset panics if d is not positive, as time.Ticker does.
+func (t *TickerFake) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for TickerFake.Reset")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.period = d
+	t.next = t.current.Add(d)
+	t.running = true
+	select {
+	case t.ResetCh <- d:
+	default:
+	}
+}
+
+func (t *TickerFake) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running = false
+	select {
+	case t.StopCh <- struct{}{}:
+	default:
+	}
+}
+
+// Send sends the next scheduled tick and blocks until it is received.
+// If t has never been Reset, it sends the current time.
+func (t *TickerFake) Send() (prev time.Time) {
+	t.mu.Lock()
+	tick := t.current
+	if t.period > 0 {
+		tick = t.next
+		t.next = t.next.Add(t.period)
+	}
+	prev, t.current = t.current, tick.Add(t.format.step())
+	emitted := t.format.apply(tick)
+	t.mu.Unlock()
+
+	t.TimeCh <- emitted
+	return prev
+}
+
+// Advance moves the current time forward by d.
+//
+// If t is running and ticks fall due meanwhile, only the earliest one is sent, blocking until received,
+// and the rest are dropped and recorded as missed, as the runtime ticker coalesces ticks
+// for a receiver which is not keeping up. The next tick is scheduled at the first multiple of the period
+// after the new current time. It returns the number of missed ticks.
+func (t *TickerFake) Advance(d time.Duration) (missed int) {
+	t.mu.Lock()
+	target := t.current.Add(d)
+	if !t.running || t.next.After(target) {
+		if target.After(t.current) {
+			t.current = target
+		}
+		t.mu.Unlock()
+		return 0
+	}
+	tick := t.next
+	missed = int(target.Sub(tick) / t.period)
+	t.missed = appendMissed(t.missed, tick.Add(t.period), t.period, missed)
+	t.count += missed
+	t.next = tick.Add(time.Duration(missed+1) * t.period)
+	t.current = target
+	if step := t.format.step(); target.Before(tick.Add(step)) {
+		t.current = tick.Add(step)
+	}
+	emitted := t.format.apply(tick)
+	t.mu.Unlock()
+
+	t.TimeCh <- emitted
+	return missed
+}
+
+// Missed implements MissedTickReporter.
+func (t *TickerFake) Missed() []MissedTicks {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]MissedTicks(nil), t.missed...)
+}
+
+// MissedCount implements MissedTickReporter.
+func (t *TickerFake) MissedCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count
+}
+
+// Period returns the period set by the last Reset.
+func (t *TickerFake) Period() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.period
+}
+
+// IsRunning reports whether t has been Reset and not Stopped since then.
+func (t *TickerFake) IsRunning() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.running
+}
+
+// ExhaustCh exhausts ResetCh and StopCh.
+func (t *TickerFake) ExhaustCh() {
+	for {
+		select {
+		case <-t.ResetCh:
+		case <-t.StopCh:
+		default:
+			return
+		}
+	}
+}
+
+// Tick starts ticker with period d and returns a channel delivering its ticks,
+// until ctx is done. Then ticker is stopped and the returned channel is closed.
+//
+// Like time.Tick, ticks are dropped to make up for slow receivers.
+// Unlike time.Tick, the underlying ticker never leaks as long as ctx is eventually cancelled.
+// If d <= 0, Tick returns nil.
+func Tick(ctx context.Context, ticker Ticker, d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+
+	out := make(chan time.Time, 1)
+	ticker.Reset(d)
+	go func() {
+		defer close(out)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t := <-ticker.C():
+				select {
+				case out <- t:
+				default:
+				}
+			}
+		}
+	}()
+	return out
+}
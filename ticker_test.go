@@ -0,0 +1,124 @@
+package mockable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTickerReal(t *testing.T) {
+	require := require.New(t)
+
+	ticker := mockable.NewTickerReal()
+
+	select {
+	case <-ticker.C():
+		t.Fatal("C is received without Reset")
+	case <-time.After(time.Millisecond):
+	}
+
+	ticker.Reset(time.Millisecond)
+	first := <-ticker.C()
+	second := <-ticker.C()
+	require.True(second.After(first))
+
+	ticker.Stop()
+}
+
+func TestTickerFake(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	ticker := mockable.NewTickerFake(now)
+	require.False(ticker.IsRunning())
+
+	ticker.Reset(time.Second)
+	require.Equal(time.Second, <-ticker.ResetCh)
+	require.True(ticker.IsRunning())
+	require.Equal(time.Second, ticker.Period())
+
+	for i := 1; i <= 3; i++ {
+		go ticker.Send()
+		tick := <-ticker.C()
+		require.Equal(now.Add(time.Duration(i)*time.Second), tick)
+		require.True(tick.Before(ticker.Now()))
+	}
+
+	ticker.Stop()
+	<-ticker.StopCh
+	require.False(ticker.IsRunning())
+
+	require.Panics(func() { ticker.Reset(0) })
+}
+
+func TestTick(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	ticker := mockable.NewTickerFake(now)
+
+	require.Nil(mockable.Tick(context.Background(), ticker, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := mockable.Tick(ctx, ticker, time.Minute)
+	require.Equal(time.Minute, <-ticker.ResetCh)
+
+	ticker.Send()
+	require.Equal(now.Add(time.Minute), <-ch)
+
+	// The slow receiver misses ticks.
+	ticker.Send()
+	ticker.Send()
+	require.Eventually(func() bool { return len(ch) == 1 }, time.Second, time.Millisecond)
+	require.Equal(now.Add(2*time.Minute), <-ch)
+
+	cancel()
+	<-ticker.StopCh
+	_, ok := <-ch
+	require.False(ok)
+}
+
+func TestTick_real(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := mockable.Tick(ctx, mockable.NewTickerReal(), time.Millisecond)
+	<-ch
+	<-ch
+	cancel()
+	for range ch {
+	}
+}
+
+func TestTickerFake_Advance(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	ticker := mockable.NewTickerFake(now)
+
+	// Not running.
+	require.Zero(ticker.Advance(time.Hour))
+	require.Equal(now.Add(time.Hour), ticker.Now())
+	now = now.Add(time.Hour)
+
+	ticker.Reset(time.Second)
+	require.Zero(ticker.Advance(500 * time.Millisecond))
+
+	// Ticks at +1s through +10s are due; +1s is delivered.
+	missed := make(chan int)
+	go func() { missed <- ticker.Advance(10*time.Second - 500*time.Millisecond) }()
+	require.Equal(now.Add(time.Second), <-ticker.C())
+	require.Equal(9, <-missed)
+	require.Equal(now.Add(10*time.Second), ticker.Now())
+
+	require.Equal(9, ticker.MissedCount())
+	runs := ticker.Missed()
+	require.Len(runs, 1)
+	require.Equal(now.Add(2*time.Second), runs[0].First)
+	require.Equal(now.Add(10*time.Second), runs[0].Last())
+
+	// The schedule is kept.
+	go ticker.Send()
+	require.Equal(now.Add(11*time.Second), <-ticker.C())
+}
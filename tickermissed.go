@@ -0,0 +1,165 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// MissedTicks is a run of consecutive ticks dropped at once,
+// as the runtime ticker drops ticks for slow receivers or after a jump of the time.
+type MissedTicks struct {
+	// First is the scheduled time of the first dropped tick.
+	First time.Time
+	// Period is the period of the ticker.
+	Period time.Duration
+	// Count is the number of dropped ticks.
+	Count int
+}
+
+// Last returns the scheduled time of the last dropped tick.
+func (m MissedTicks) Last() time.Time {
+	return m.First.Add(time.Duration(m.Count-1) * m.Period)
+}
+
+// Times returns the scheduled times of all dropped ticks.
+func (m MissedTicks) Times() []time.Time {
+	out := make([]time.Time, m.Count)
+	for i := range out {
+		out[i] = m.First.Add(time.Duration(i) * m.Period)
+	}
+	return out
+}
+
+// MissedTickReporter is implemented by Tickers accounting dropped ticks,
+// for testing catch-up logic of their consumers.
+type MissedTickReporter interface {
+	// Missed returns runs of dropped ticks, oldest first.
+	Missed() []MissedTicks
+	// MissedCount returns the total number of dropped ticks.
+	MissedCount() int
+}
+
+// appendMissed appends a run of count ticks from first to runs, merging it into the last run if contiguous.
+func appendMissed(runs []MissedTicks, first time.Time, period time.Duration, count int) []MissedTicks {
+	if count <= 0 {
+		return runs
+	}
+	if n := len(runs); n > 0 && runs[n-1].Period == period && runs[n-1].Last().Add(period).Equal(first) {
+		runs[n-1].Count += count
+		return runs
+	}
+	return append(runs, MissedTicks{First: first, Period: period, Count: count})
+}
+
+var (
+	_ Ticker             = (*TickerTracked)(nil)
+	_ MissedTickReporter = (*TickerTracked)(nil)
+)
+
+// TickerTracked is a Ticker wrapping another Ticker, typically TickerReal, to account its dropped ticks.
+//
+// A tick is counted as missed either if it is dropped because the previous one is still not received,
+// or if the inner ticker skipped it, which is detected by a gap of more than a period between ticks.
+// Ticks are relayed by a goroutine running from Reset until Stop.
+type TickerTracked struct {
+	inner Ticker
+	out   chan time.Time
+
+	mu     sync.Mutex
+	period time.Duration
+	// last is the last tick relayed since Reset.
+	last   time.Time
+	missed []MissedTicks
+	count  int
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewTickerTracked returns a stopped TickerTracked wrapping inner.
+// inner is exclusively used by the TickerTracked.
+func NewTickerTracked(inner Ticker) *TickerTracked {
+	return &TickerTracked{
+		inner: inner,
+		out:   make(chan time.Time, 1),
+	}
+}
+
+func (t *TickerTracked) C() <-chan time.Time {
+	return t.out
+}
+
+func (t *TickerTracked) Stop() {
+	t.mu.Lock()
+	stop, done := t.stop, t.done
+	t.stop, t.done = nil, nil
+	t.mu.Unlock()
+	t.inner.Stop()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (t *TickerTracked) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for TickerTracked.Reset")
+	}
+	t.Stop()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.period, t.last = d, time.Time{}
+	select {
+	case <-t.out:
+	default:
+	}
+	t.stop, t.done = make(chan struct{}), make(chan struct{})
+	go t.run(t.stop, t.done)
+	t.inner.Reset(d)
+}
+
+func (t *TickerTracked) run(stop, done chan struct{}) {
+	defer close(done)
+	for {
+		select {
+		case <-stop:
+			return
+		case tick := <-t.inner.C():
+			t.mu.Lock()
+			if !t.last.IsZero() {
+				// Rounds the gap to periods, tolerating the jitter of the runtime.
+				skipped := int((tick.Sub(t.last)+t.period/2)/t.period) - 1
+				t.record(t.last.Add(t.period), skipped)
+			}
+			t.last = tick
+			select {
+			case t.out <- tick:
+			default:
+				t.record(tick, 1)
+			}
+			t.mu.Unlock()
+		}
+	}
+}
+
+// record records count missed ticks from first. t.mu must be held.
+func (t *TickerTracked) record(first time.Time, count int) {
+	if count <= 0 {
+		return
+	}
+	t.missed = appendMissed(t.missed, first, t.period, count)
+	t.count += count
+}
+
+// Missed implements MissedTickReporter.
+func (t *TickerTracked) Missed() []MissedTicks {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]MissedTicks(nil), t.missed...)
+}
+
+// MissedCount implements MissedTickReporter.
+func (t *TickerTracked) MissedCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count
+}
@@ -0,0 +1,80 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissedTicks(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	m := mockable.MissedTicks{First: now, Period: time.Minute, Count: 3}
+	require.Equal(now.Add(2*time.Minute), m.Last())
+	require.Equal([]time.Time{now, now.Add(time.Minute), now.Add(2 * time.Minute)}, m.Times())
+}
+
+func TestTickerTracked(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	fake := mockable.NewTickerFake(now)
+	ticker := mockable.NewTickerTracked(fake)
+	var _ mockable.MissedTickReporter = ticker
+
+	ticker.Reset(time.Second)
+	<-fake.ResetCh
+
+	fake.Send()
+	require.Equal(now.Add(time.Second), <-ticker.C())
+
+	// Coalesced by the inner ticker: +2s is delivered and +3s through +5s are skipped,
+	// which is told by the gap to the next tick.
+	require.Equal(3, fake.Advance(4*time.Second))
+	require.Equal(now.Add(2*time.Second), <-ticker.C())
+	fake.Send()
+	require.Equal(now.Add(6*time.Second), <-ticker.C())
+	require.Equal(3, ticker.MissedCount())
+
+	// Dropped for the slow receiver: +8s.
+	fake.Send()
+	fake.Send()
+	require.Eventually(func() bool { return ticker.MissedCount() == 4 }, time.Second, time.Millisecond)
+	require.Equal(now.Add(7*time.Second), <-ticker.C())
+
+	runs := ticker.Missed()
+	require.Len(runs, 2)
+	require.Equal(mockable.MissedTicks{First: now.Add(3 * time.Second), Period: time.Second, Count: 3}, runs[0])
+	require.Equal(mockable.MissedTicks{First: now.Add(8 * time.Second), Period: time.Second, Count: 1}, runs[1])
+
+	ticker.Stop()
+	<-fake.StopCh
+	require.False(fake.IsRunning())
+}
+
+func TestTickerTracked_Reset_non_positive(t *testing.T) {
+	require := require.New(t)
+
+	inner := mockable.NewTickerFake(time.Now())
+	tracked := mockable.NewTickerTracked(inner)
+	require.PanicsWithValue("non-positive interval for TickerTracked.Reset", func() { tracked.Reset(0) })
+	require.False(inner.IsRunning())
+	// Nothing is left running by the panic.
+	tracked.Stop()
+}
+
+func TestTickerTracked_real(t *testing.T) {
+	require := require.New(t)
+
+	ticker := mockable.NewTickerTracked(mockable.NewTickerReal())
+	ticker.Reset(time.Millisecond)
+	<-ticker.C()
+	// Ticks are dropped while not receiving.
+	time.Sleep(20 * time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+	require.Greater(ticker.MissedCount(), 0)
+}
@@ -0,0 +1,174 @@
+package mockable
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ClockEventKind is a kind of ClockEvent.
+type ClockEventKind int
+
+const (
+	// ClockEventReset is recorded by Reset. Duration is the argument.
+	ClockEventReset ClockEventKind = iota
+	// ClockEventStop is recorded by Stop.
+	ClockEventStop
+	// ClockEventSend is recorded by Send. Value is the time sent.
+	ClockEventSend
+	// ClockEventSetNow is recorded by SetNow. Value is the new current time.
+	ClockEventSetNow
+)
+
+func (k ClockEventKind) String() string {
+	switch k {
+	case ClockEventReset:
+		return "reset"
+	case ClockEventStop:
+		return "stop"
+	case ClockEventSend:
+		return "send"
+	case ClockEventSetNow:
+		return "set_now"
+	}
+	return "unknown"
+}
+
+// ClockEvent is an event recorded by ClockFake.
+type ClockEvent struct {
+	Kind ClockEventKind
+	// At is the fake current time when the event happened.
+	At       time.Time
+	Duration time.Duration
+	Value    time.Time
+
+	// SentAt is the real time Send started, recorded for ClockEventSend.
+	SentAt time.Time
+	// ReceivedAt is the real time the value sent was received, and ReceivedNow is the fake current time then.
+	// They are zero if the value was not received, or not known to be as in the latch mode.
+	ReceivedAt  time.Time
+	ReceivedNow time.Time
+}
+
+// Latency returns how long the value sent took to be received in the real time,
+// or 0 if it was not received.
+func (e ClockEvent) Latency() time.Duration {
+	if e.ReceivedAt.IsZero() {
+		return 0
+	}
+	return e.ReceivedAt.Sub(e.SentAt)
+}
+
+type clockEventJSON struct {
+	Kind     string     `json:"kind"`
+	At       time.Time  `json:"at"`
+	Duration string     `json:"duration,omitempty"`
+	Value    *time.Time `json:"value,omitempty"`
+	// ReceivedNow and Latency are set for received sends.
+	ReceivedNow *time.Time `json:"received_now,omitempty"`
+	Latency     string     `json:"latency,omitempty"`
+}
+
+// WriteTimelineJSON writes events to w as an indented JSON array.
+func WriteTimelineJSON(w io.Writer, events []ClockEvent) error {
+	out := make([]clockEventJSON, len(events))
+	for i, e := range events {
+		out[i] = clockEventJSON{Kind: e.Kind.String(), At: e.At}
+		switch e.Kind {
+		case ClockEventReset:
+			out[i].Duration = e.Duration.String()
+		case ClockEventSend, ClockEventSetNow:
+			v := e.Value
+			out[i].Value = &v
+			if e.Kind == ClockEventSend && !e.ReceivedAt.IsZero() {
+				r := e.ReceivedNow
+				out[i].ReceivedNow = &r
+				out[i].Latency = e.Latency().String()
+			}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteTimelineMermaid writes events to w as a Mermaid gantt chart.
+//
+// Every Reset is rendered as a bar spanning until the timer fired, was stopped or was reset again,
+// or until its deadline if it is still pending. Time jumps made by SetNow are rendered as milestones.
+func WriteTimelineMermaid(w io.Writer, events []ClockEvent) error {
+	var (
+		timer, jumps []string
+		open         *ClockEvent
+	)
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	bar := func(status, outcome string, end time.Time) {
+		if end.Before(open.At) {
+			end = open.At
+		}
+		timer = append(timer, fmt.Sprintf(
+			"    Reset %s (%s) :%s, %d, %d", open.Duration, outcome, status, ms(open.At), ms(end),
+		))
+		open = nil
+	}
+	for i := range events {
+		e := events[i]
+		switch e.Kind {
+		case ClockEventReset:
+			if open != nil {
+				bar("done", "reset", e.At)
+			}
+			open = &e
+		case ClockEventStop:
+			if open != nil {
+				bar("crit", "stopped", e.At)
+			}
+		case ClockEventSend:
+			if open != nil {
+				bar("done", "fired", e.Value)
+			} else {
+				timer = append(timer, fmt.Sprintf("    Send :milestone, %d, 0ms", ms(e.Value)))
+			}
+		case ClockEventSetNow:
+			jumps = append(jumps, fmt.Sprintf("    SetNow :milestone, %d, 0ms", ms(e.Value)))
+		}
+	}
+	if open != nil {
+		bar("active", "pending", open.At.Add(open.Duration))
+	}
+
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	b.WriteString("    title ClockFake timeline\n")
+	b.WriteString("    dateFormat x\n")
+	b.WriteString("    axisFormat %H:%M:%S\n")
+	if len(timer) > 0 {
+		b.WriteString("    section timer\n")
+		b.WriteString(strings.Join(timer, "\n") + "\n")
+	}
+	if len(jumps) > 0 {
+		b.WriteString("    section time jumps\n")
+		b.WriteString(strings.Join(jumps, "\n") + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// LogTimelineOnFailure logs the history of c as a Mermaid gantt chart when t fails.
+func LogTimelineOnFailure(t testing.TB, c *ClockFake) {
+	t.Helper()
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+		var b strings.Builder
+		if err := WriteTimelineMermaid(&b, c.History()); err != nil {
+			t.Logf("mockable: failed to render timeline: %v", err)
+			return
+		}
+		t.Logf("mockable: ClockFake timeline:\n%s", b.String())
+	})
+}
@@ -0,0 +1,176 @@
+package mockable_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func timelineFixture() (*mockable.ClockFake, time.Time) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := mockable.NewClockFake(now)
+	c.Reset(time.Second)
+	go c.Send()
+	<-c.C()
+	c.Reset(10 * time.Second)
+	c.SetNow(now.Add(5 * time.Second))
+	c.Stop()
+	c.Reset(time.Minute)
+	return c, now
+}
+
+func TestClockFake_History(t *testing.T) {
+	require := require.New(t)
+
+	c, now := timelineFixture()
+	history := c.History()
+	// Real times of the Send vary between runs.
+	send := &history[1]
+	require.False(send.SentAt.IsZero())
+	require.False(send.ReceivedAt.Before(send.SentAt))
+	require.Equal(send.ReceivedAt.Sub(send.SentAt), send.Latency())
+	require.Equal(now.Add(time.Second+1), send.ReceivedNow)
+	send.SentAt, send.ReceivedAt, send.ReceivedNow = time.Time{}, time.Time{}, time.Time{}
+	require.Equal(
+		[]mockable.ClockEvent{
+			{Kind: mockable.ClockEventReset, At: now, Duration: time.Second},
+			{Kind: mockable.ClockEventSend, At: now, Value: now.Add(time.Second)},
+			{Kind: mockable.ClockEventReset, At: now.Add(time.Second + 1), Duration: 10 * time.Second},
+			{Kind: mockable.ClockEventSetNow, At: now.Add(time.Second + 1), Value: now.Add(5 * time.Second)},
+			{Kind: mockable.ClockEventStop, At: now.Add(5 * time.Second)},
+			{Kind: mockable.ClockEventReset, At: now.Add(5 * time.Second), Duration: time.Minute},
+		},
+		history,
+	)
+}
+
+func TestWriteTimelineJSON(t *testing.T) {
+	require := require.New(t)
+
+	c, _ := timelineFixture()
+
+	var buf bytes.Buffer
+	require.NoError(mockable.WriteTimelineJSON(&buf, c.History()))
+
+	var decoded []map[string]any
+	require.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(decoded, 6)
+	require.Equal(
+		map[string]any{"kind": "reset", "at": "2023-05-01T12:00:00Z", "duration": "1s"},
+		decoded[0],
+	)
+	latency, err := time.ParseDuration(decoded[1]["latency"].(string))
+	require.NoError(err)
+	require.GreaterOrEqual(latency, time.Duration(0))
+	delete(decoded[1], "latency")
+	require.Equal(
+		map[string]any{
+			"kind": "send", "at": "2023-05-01T12:00:00Z", "value": "2023-05-01T12:00:01Z",
+			"received_now": "2023-05-01T12:00:01.000000001Z",
+		},
+		decoded[1],
+	)
+	require.Equal(
+		map[string]any{"kind": "stop", "at": "2023-05-01T12:00:05Z"},
+		decoded[4],
+	)
+}
+
+func TestWriteTimelineMermaid(t *testing.T) {
+	require := require.New(t)
+
+	c, _ := timelineFixture()
+
+	var buf bytes.Buffer
+	require.NoError(mockable.WriteTimelineMermaid(&buf, c.History()))
+	require.Equal(
+		strings.Join([]string{
+			"gantt",
+			"    title ClockFake timeline",
+			"    dateFormat x",
+			"    axisFormat %H:%M:%S",
+			"    section timer",
+			"    Reset 1s (fired) :done, 1682942400000, 1682942401000",
+			"    Reset 10s (stopped) :crit, 1682942401000, 1682942405000",
+			"    Reset 1m0s (pending) :active, 1682942405000, 1682942465000",
+			"    section time jumps",
+			"    SetNow :milestone, 1682942405000, 0ms",
+			"",
+		}, "\n"),
+		buf.String(),
+	)
+}
+
+func TestLogTimelineOnFailure(t *testing.T) {
+	require := require.New(t)
+
+	c, _ := timelineFixture()
+
+	tb := &fakeTB{TB: t}
+	mockable.LogTimelineOnFailure(tb, c)
+	tb.cleanups[0]()
+	require.Empty(tb.logs)
+
+	tb.failed = true
+	tb.cleanups[0]()
+	require.Len(tb.logs, 1)
+	require.Contains(tb.logs[0], "Reset 1s (fired)")
+}
+
+func TestClockFake_History_latency(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	lastSend := func(c *mockable.ClockFake) mockable.ClockEvent {
+		history := c.History()
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Kind == mockable.ClockEventSend {
+				return history[i]
+			}
+		}
+		t.Fatal("no send is recorded")
+		return mockable.ClockEvent{}
+	}
+
+	for _, policy := range []mockable.SendPolicy{mockable.SendBlock, mockable.SendQueue} {
+		c := mockable.NewClockFake(now, mockable.WithSendPolicy(policy))
+		c.Reset(time.Second)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.Send()
+		}()
+		// A slow receiver.
+		time.Sleep(20 * time.Millisecond)
+		c.SetNow(now.Add(time.Minute))
+		<-c.C()
+		<-done
+		require.Eventually(func() bool { return !lastSend(c).ReceivedAt.IsZero() }, time.Second, time.Millisecond)
+		e := lastSend(c)
+		require.GreaterOrEqual(e.Latency(), 20*time.Millisecond, "policy %d", policy)
+		require.Equal(now.Add(time.Minute), e.ReceivedNow, "policy %d", policy)
+	}
+
+	// A dropped value is never received.
+	c := mockable.NewClockFake(now, mockable.WithSendPolicy(mockable.SendDrop))
+	c.Reset(time.Second)
+	c.Send()
+	for _, e := range c.History() {
+		require.NotEqual(mockable.ClockEventSend, e.Kind)
+	}
+
+	// The latch mode does not know when the value is received.
+	c = mockable.NewClockFake(now, mockable.WithLatch())
+	c.Reset(time.Second)
+	c.Send()
+	<-c.C()
+	e := lastSend(c)
+	require.False(e.SentAt.IsZero())
+	require.Equal(time.Duration(0), e.Latency())
+}
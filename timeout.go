@@ -0,0 +1,45 @@
+package mockable
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimeout is returned from DoWithTimeout when fn does not return within the timeout.
+var ErrTimeout = errors.New("mockable: timeout")
+
+// DoWithTimeout calls fn with a context which is done after d measured by clock,
+// and waits for fn to return or the context to be done.
+//
+// The returned error distinguishes the three outcomes:
+//   - the error returned from fn, if fn returns before the timeout (nil on success);
+//   - ErrTimeout, if d elapses first;
+//   - ctx.Err(), if ctx is done first.
+//
+// DoWithTimeout does not wait for fn to return after the timeout or cancellation;
+// fn should honor its context to avoid leaking the goroutine.
+// With ClockFake, tests trigger the timeout by calling Send.
+func DoWithTimeout(ctx context.Context, clock Clock, d time.Duration, fn func(ctx context.Context) error) error {
+	tctx, cancel := WithTimeout(ctx, clock, d)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(tctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil && tctx.Err() != nil {
+			// fn observed the timeout and returned the context error.
+			return ErrTimeout
+		}
+		return err
+	case <-tctx.Done():
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,68 @@
+package mockable_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoWithTimeout(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	// fn returns first.
+	sentinel := errors.New("fn")
+	err := mockable.DoWithTimeout(context.Background(), clock, time.Second, func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		require.True(ok)
+		require.Equal(now.Add(time.Second), deadline)
+		return sentinel
+	})
+	require.ErrorIs(err, sentinel)
+	require.Equal(time.Second, <-clock.ResetCh)
+
+	// timeout.
+	errCh := make(chan error)
+	block := make(chan struct{})
+	defer close(block)
+	go func() {
+		errCh <- mockable.DoWithTimeout(context.Background(), clock, time.Second, func(ctx context.Context) error {
+			<-block
+			return nil
+		})
+	}()
+	<-clock.ResetCh
+	clock.Send()
+	require.ErrorIs(<-errCh, mockable.ErrTimeout)
+
+	// fn honoring the context also results in ErrTimeout.
+	go func() {
+		errCh <- mockable.DoWithTimeout(context.Background(), clock, time.Second, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	<-clock.ResetCh
+	clock.Send()
+	require.ErrorIs(<-errCh, mockable.ErrTimeout)
+
+	// cancellation.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		errCh <- mockable.DoWithTimeout(ctx, clock, time.Second, func(ctx context.Context) error {
+			<-block
+			return nil
+		})
+	}()
+	<-clock.ResetCh
+	cancel()
+	err = <-errCh
+	require.ErrorIs(err, context.Canceled)
+	require.NotErrorIs(err, mockable.ErrTimeout)
+}
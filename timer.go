@@ -1,6 +1,7 @@
 package mockable
 
 import (
+	"errors"
 	"sync"
 	"time"
 )
@@ -19,13 +20,15 @@ type Nower interface {
 var _ Nower = (*NowerReal)(nil)
 
 // NowerReal is an implementation of the Nower interface.
-// It only wraps time.Now.
-type NowerReal struct{}
+// It only wraps time.Now, unless Source is TimeSourceCoarse.
+type NowerReal struct {
+	Source TimeSource
+}
 
 // Now implements Clock interface.
 // Now returns the current local time using runtime timer.
-func (_ NowerReal) Now() time.Time {
-	return time.Now()
+func (n NowerReal) Now() time.Time {
+	return n.Source.now()
 }
 
 var _ Nower = (*NowerFake)(nil)
@@ -63,6 +66,7 @@ type Timer interface {
 	// Stop prevents timer from firing. It returns true if it successfully stopped the timer, false if it has already expired or been stopped.
 	Stop() bool
 	// Reset changes the timer to expire after duration d. Call Reset only for explicitly stopped and drained timer.
+	// If d is zero or negative, the timer expires immediately, at the time of Reset, as time.Timer does.
 	Reset(d time.Duration)
 }
 
@@ -71,6 +75,10 @@ var _ Clock = (*ClockReal)(nil)
 // ClockReal implements Clock using a runtime timer.
 type ClockReal struct {
 	T *time.Timer
+	// Source selects the time source of Now.
+	// TimeSourceCoarse makes Now lag the runtime timer by up to CoarseResolution,
+	// so a value just received from C may be after Now, breaking (<-C()).Before(Now()).
+	Source TimeSource
 }
 
 // NewClockReal returns newly created ClockReal.
@@ -84,7 +92,7 @@ func NewClockReal() *ClockReal {
 }
 
 func (c *ClockReal) Now() time.Time {
-	return time.Now()
+	return c.Source.now()
 }
 
 func (c *ClockReal) C() <-chan time.Time {
@@ -117,7 +125,9 @@ type ClockFake struct {
 	// current is used to mock timer's behavior
 	// where Timer.C() emits the current time when the timer is expired.
 	current time.Time
-	TimeCh  chan time.Time
+	// TimeCh is the channel of Chan.
+	TimeCh chan time.Time
+	ch     *ManualChan[time.Time]
 	// The resetArg holds records of Reset calls.
 	// Every time Reset is called, resetArg is appended.
 	// Stop also appends it with nil.
@@ -132,37 +142,302 @@ type ClockFake struct {
 	// whether Clock is sending a time value via TimeCh or not.
 	sending   bool
 	scheduled bool
+	// fired is true if the last fire was delivered and c is not Reset or Stopped since then.
+	fired bool
+	subs  []*stateSub
+	// history records every Reset, Stop, Send and SetNow call.
+	history []ClockEvent
+
+	policy  SendPolicy
+	grace   time.Duration
+	queue   []queuedSend
+	pumping bool
+
+	name      string
+	strict    bool
+	invariant FireInvariant
+	format    emitFormat
+
+	preserve bool
+	zeroMode ZeroDurationMode
+	// armGen counts Reset and Stop calls, to tell whether c is re-armed while a value is in flight.
+	armGen uint64
+	// disarmed is closed and replaced by every Reset and Stop, to cancel fires bound to the previous arming.
+	disarmed chan struct{}
+	// drained holds values in flight taken from TimeCh by Reset, to be reported as discarded by their senders.
+	drained []time.Time
+
+	latch      bool
+	latchCh    chan time.Time
+	latched    time.Time
+	hasLatched bool
+	// awaiting holds handles of SendAsync waiting for the code under test to resume.
+	awaiting []*SendHandle
+}
+
+type queuedSend struct {
+	value  time.Time
+	handle *SendHandle
+	// event is the index of the Send in the history.
+	event int
+}
+
+// SendPolicy decides what ClockFake.Send does when nothing is receiving from TimeCh.
+type SendPolicy int
+
+const (
+	// SendBlock blocks until the value is received. This is the default.
+	SendBlock SendPolicy = iota
+	// SendDrop drops the value unless a receiver is already waiting.
+	SendDrop
+	// SendQueue queues the value without blocking.
+	// Queued values are delivered in order, one per receive, by a goroutine owned by the ClockFake.
+	// Note that Reset drains TimeCh, which consumes a queued value.
+	SendQueue
+	// SendError gives up after the grace period, and TrySend reports ErrNoReceiver.
+	SendError
+)
+
+// ClockFakeOption configures ClockFake.
+type ClockFakeOption func(c *ClockFake)
+
+// WithSendPolicy sets the policy of Send when nothing is receiving from TimeCh.
+func WithSendPolicy(policy SendPolicy) ClockFakeOption {
+	return func(c *ClockFake) {
+		c.policy = policy
+	}
+}
+
+// WithSendGracePeriod sets how long Send waits for a receiver under SendError. Defaults to 1s.
+// The grace period is measured in the real time.
+func WithSendGracePeriod(d time.Duration) ClockFakeOption {
+	return func(c *ClockFake) {
+		c.grace = d
+	}
+}
+
+// WithStrict enables the strict mode, where misuses of the Timer contract by the code under test
+// are reported rather than tolerated:
+// Reset on an armed clock panics with *ContractViolationError locating the call,
+// and Send on a clock not armed panics likewise, or TrySend returns ErrNotScheduled.
+func WithStrict() ClockFakeOption {
+	return func(c *ClockFake) {
+		c.strict = true
+	}
+}
+
+// FireInvariant is an invariant between the fire time emitted by ClockFake.Send and ClockFake.Now.
+type FireInvariant int
+
+const (
+	// FireBefore steps the current time slightly after the fire time,
+	// so that (<-c.C()).Before(c.Now()) holds. This is the default.
+	FireBefore FireInvariant = iota
+	// FireEqualAllowed sets the current time to the fire time,
+	// so that (<-c.C()).Equal(c.Now()) holds unless the time is moved meanwhile.
+	// It is violated only if the current time is set before the fire time.
+	FireEqualAllowed
+	// FireNone sets the current time to the fire time and never validates it.
+	FireNone
+)
+
+func (i FireInvariant) String() string {
+	switch i {
+	case FireBefore:
+		return "before"
+	case FireEqualAllowed:
+		return "equal-allowed"
+	case FireNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// step returns how far the current time is stepped after the fire time.
+func (i FireInvariant) step() time.Duration {
+	if i == FireBefore {
+		return 1
+	}
+	return 0
+}
+
+// step returns how far Send steps the current time after the fire time. c.Mutex must be held.
+func (c *ClockFake) step() time.Duration {
+	if c.invariant.step() == 0 {
+		return 0
+	}
+	return c.format.step()
+}
+
+// holds reports whether fired and now satisfy i.
+func (i FireInvariant) holds(fired, now time.Time) bool {
+	switch i {
+	case FireBefore:
+		return fired.Before(now)
+	case FireEqualAllowed:
+		return !fired.After(now)
+	}
+	return true
+}
+
+// WithFireInvariant sets the invariant between fire times and the current time. Defaults to FireBefore.
+//
+// The invariant is validated when a blocking or dropping Send delivers the value:
+// if the current time has been moved, e.g. by SetNow, so that it breaks the invariant,
+// TrySend returns *FireInvariantError and Send panics with it.
+func WithFireInvariant(invariant FireInvariant) ClockFakeOption {
+	return func(c *ClockFake) {
+		c.invariant = invariant
+	}
+}
+
+// ZeroDurationMode decides when ClockFake fires after Reset with a zero or negative duration.
+// Either way, the fire time is the current time at Reset; negative durations never move the time backwards.
+type ZeroDurationMode int
+
+const (
+	// ZeroFiresOnSend fires on the next Send or Advance, like any other duration. This is the default.
+	ZeroFiresOnSend ZeroDurationMode = iota
+	// ZeroFiresImmediately fires without Send, as time.Timer does:
+	// Reset starts sending the fire by itself, which follows SendPolicy
+	// and is cancelled by Stop or Reset made before it is received, even while it is blocked in the send.
+	ZeroFiresImmediately
+)
+
+// WithZeroDuration sets when c fires after Reset with a zero or negative duration. Defaults to ZeroFiresOnSend.
+func WithZeroDuration(mode ZeroDurationMode) ClockFakeOption {
+	return func(c *ClockFake) {
+		c.zeroMode = mode
+	}
+}
+
+// nonNegative returns d, or 0 if d is negative.
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// WithPreserveFires makes Reset keep a fire in flight deliverable, rather than discarding it.
+// The fire is then received from C after Reset returns, like a stale value left in the channel
+// of a runtime timer before Go 1.23.
+func WithPreserveFires() ClockFakeOption {
+	return func(c *ClockFake) {
+		c.preserve = true
+	}
+}
+
+// WithLatch enables the latch mode.
+//
+// In the latch mode, Send never blocks: the fire lands in an internal slot of size 1,
+// and C returns a freshly made channel per call, to which the fire is delivered.
+// A fire left unreceived in a channel previously returned by C is moved to the new one,
+// as it stays in time.Timer.C until received.
+// This models code that re-obtains timer.C in every iteration of a loop.
+// SendPolicy is ignored and TimeCh is unused in the latch mode,
+// and SendAsync reports the fire as consumed as soon as it is latched.
+func WithLatch() ClockFakeOption {
+	return func(c *ClockFake) {
+		c.latch = true
+	}
 }
 
-func NewClockFake(current time.Time) *ClockFake {
-	return &ClockFake{
+func NewClockFake(current time.Time, options ...ClockFakeOption) *ClockFake {
+	c := &ClockFake{
 		current:  current,
 		TimeCh:   make(chan time.Time),
 		resetArg: make([]*time.Duration, 0),
 		ResetCh:  make(chan time.Duration, 1),
 		StopCh:   make(chan struct{}, 1),
+		grace:    time.Second,
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Chan returns the ManualChan through which c sends fires,
+// e.g. to subscribe to every fire received by the code under test.
+// It must not be closed, and it is unused in the latch mode.
+// If TimeCh is replaced, a new ManualChan wrapping it is used from then on.
+func (c *ClockFake) Chan() *ManualChan[time.Time] {
+	c.Lock()
+	defer c.Unlock()
+	return c.chanLocked()
+}
+
+// chanLocked returns the ManualChan wrapping TimeCh, making it on first use or after TimeCh is replaced,
+// so that ClockFake made by a struct literal works. c.Mutex must be held.
+func (c *ClockFake) chanLocked() *ManualChan[time.Time] {
+	if c.ch == nil || c.ch.Ch != c.TimeCh {
+		c.ch = newManualChan(c.TimeCh)
 	}
+	return c.ch
 }
 
 // Now implements Nower.
 func (c *ClockFake) Now() time.Time {
 	c.Lock()
 	defer c.Unlock()
-	return c.current
+	return c.format.apply(c.current)
 }
 
+// C implements Timer.
+// It returns TimeCh, or a fresh channel per call in the latch mode (see WithLatch).
 func (c *ClockFake) C() <-chan time.Time {
-	return c.TimeCh
+	c.Lock()
+	defer c.Unlock()
+	if !c.latch {
+		return c.TimeCh
+	}
+	// Take the fire back from the previous channel if it is left unreceived.
+	v, ok := c.latched, c.hasLatched
+	if c.latchCh != nil {
+		select {
+		case v = <-c.latchCh:
+			ok = true
+		default:
+		}
+	}
+	c.hasLatched = false
+	c.latchCh = make(chan time.Time, 1)
+	if ok {
+		c.latchCh <- v
+	}
+	return c.latchCh
 }
 
+// Reset arms c to fire after d.
+//
+// Reset is ordered with a Send in flight, i.e. whose value is not received yet, as follows.
+// By default the value is discarded: it is never received from C after Reset returns,
+// and TrySend reports ErrDiscarded. With WithPreserveFires, it stays deliverable instead.
+// Either way, the state armed by Reset is not overridden by the Send completing afterwards,
+// and ResetCh is notified only after all of the effects of Reset are made,
+// so a test receiving from ResetCh observes them.
 func (c *ClockFake) Reset(d time.Duration) {
 	c.Lock()
-	defer c.Unlock()
+	defer c.unlockState(c.stateLocked())
+	if c.strict && c.scheduled {
+		panic(newContractViolation("ClockFake.Reset", errors.New("Reset on an armed clock; Stop it first")))
+	}
+	c.fired = false
 	c.resetArg = append(c.resetArg, &d)
+	c.history = append(c.history, ClockEvent{Kind: ClockEventReset, At: c.current, Duration: d})
+	c.resumeLocked()
 	c.scheduled = true
-	select {
-	case <-c.TimeCh:
-	default:
+	c.rearmLocked()
+	if d <= 0 && c.zeroMode == ZeroFiresImmediately {
+		// Fires as soon as Reset releases the lock, unless re-armed or stopped meanwhile.
+		go c.trySend(nil, c.current, c.armGen, false)
+	}
+	if !c.preserve {
+		if v, ok := c.chanLocked().Drain(); ok {
+			c.drained = append(c.drained, v)
+		}
+		c.drainLatchLocked()
 	}
 	select {
 	case c.ResetCh <- d:
@@ -170,11 +445,36 @@ func (c *ClockFake) Reset(d time.Duration) {
 	}
 }
 
+// rearmLocked starts a new arming generation, canceling sends bound to the previous one.
+// c.Mutex must be held.
+func (c *ClockFake) rearmLocked() {
+	c.armGen++
+	if c.disarmed != nil {
+		close(c.disarmed)
+	}
+	c.disarmed = make(chan struct{})
+}
+
+// takeDrained reports whether v has been discarded by Reset, forgetting it. c.Mutex must be held.
+func (c *ClockFake) takeDrained(v time.Time) bool {
+	for i, d := range c.drained {
+		if d.Equal(v) {
+			c.drained = append(c.drained[:i], c.drained[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // true if it successfully stopped the timer, false if it has already expired or been stopped.
 func (c *ClockFake) Stop() bool {
 	c.Lock()
-	defer c.Unlock()
+	defer c.unlockState(c.stateLocked())
+	c.fired = false
 	c.resetArg = append(c.resetArg, nil)
+	c.history = append(c.history, ClockEvent{Kind: ClockEventStop, At: c.current})
+	c.rearmLocked()
+	c.resumeLocked()
 	select {
 	case c.StopCh <- struct{}{}:
 	default:
@@ -187,6 +487,7 @@ func (c *ClockFake) Stop() bool {
 func (c *ClockFake) SetNow(t time.Time) (prev time.Time) {
 	c.Lock()
 	defer c.Unlock()
+	c.history = append(c.history, ClockEvent{Kind: ClockEventSetNow, At: c.current, Value: t})
 	c.current, prev = t, c.current
 	return prev
 }
@@ -197,29 +498,252 @@ func (c *ClockFake) SetNow(t time.Time) (prev time.Time) {
 // Send keeps invariants where (<-c.C()).Before(c.Now()) is always true
 // by stepping the current time slightly forward.
 // Taking the time from the runtime must take a few nano seconds.
+// WithFireInvariant relaxes it for code expecting the fire time to equal Now.
+//
+// By default Send blocks until the value is received. See SendPolicy for other behaviors.
+// If the value is dropped or given up, the current time is left unchanged.
+// In the strict mode, Send panics with *ContractViolationError if c is not armed.
 func (c *ClockFake) Send() (prev time.Time) {
+	prev, _, err := c.TrySend()
+	var invErr *FireInvariantError
+	switch {
+	case errors.Is(err, ErrNotScheduled):
+		panic(newContractViolation("ClockFake.Send", err))
+	case errors.As(err, &invErr):
+		panic(err)
+	}
+	return prev
+}
+
+// TrySend is Send reporting the outcome.
+// delivered is true if the value is received, or queued under SendQueue.
+// err is ErrNoReceiver if the value is given up under SendError,
+// or ErrNotScheduled if c is not armed in the strict mode, in which case nothing is sent.
+// err is *FireInvariantError if the delivered value breaks the invariant set by WithFireInvariant,
+// or ErrDiscarded if the value is discarded by Reset before received.
+func (c *ClockFake) TrySend() (prev time.Time, delivered bool, err error) {
+	return c.trySend(nil, time.Time{}, 0, false)
+}
+
+// Advance moves the current time forward by d, like SetNow.
+// If c is scheduled and its deadline comes meanwhile, c fires at the deadline first, as Send does.
+// It returns the number of delivered fires, which is either 0 or 1.
+//
+// Unlike Send, Advance never blocks indefinitely: under SendBlock, the fire waits for a receiver
+// only for the grace period (see WithSendGracePeriod) as under SendError.
+// A fire given up this way expires c, as a runtime timer expires whether or not its channel is received from.
+func (c *ClockFake) Advance(d time.Duration) int {
+	// The target is taken from the current time before truncation by WithPrecision,
+	// so that steps shorter than the precision add up.
 	c.Lock()
-	var lastReset time.Duration
-	for i := len(c.resetArg); i > 0; i-- {
-		arg := c.resetArg[i-1]
-		if arg != nil {
-			lastReset = *arg
-			break
+	target := c.current.Add(d)
+	c.Unlock()
+	var fired int
+	if deadlines := c.ArmedDeadlines(); len(deadlines) > 0 && !deadlines[0].After(target) {
+		if _, delivered, _ := c.trySend(nil, deadlines[0], 0, true); delivered {
+			fired = 1
 		}
 	}
-	next := c.current.Add(lastReset)
+	c.Lock()
+	defer c.Unlock()
+	if target.After(c.current) {
+		c.history = append(c.history, ClockEvent{Kind: ClockEventSetNow, At: c.current, Value: target})
+		c.current = target
+	}
+	return fired
+}
 
-	prev, c.current = c.current, next.Add(1)
-	c.sending = true
-	c.Unlock()
+// trySend sends at, or the current time advanced by the last Reset duration if at is zero.
+// If gen is non-zero, trySend sends nothing unless c is still armed by the Reset or Stop call numbered gen,
+// and gives up the send in flight with ErrDiscarded once c is reset or stopped.
+// If bounded is true, SendBlock is taken as SendError, and a fire given up expires c.
+func (c *ClockFake) trySend(h *SendHandle, at time.Time, gen uint64, bounded bool) (prev time.Time, delivered bool, err error) {
+	c.Lock()
+	before := c.stateLocked()
+	if gen != 0 && (gen != c.armGen || !c.scheduled) {
+		prev = c.current
+		c.unlockState(before)
+		return prev, false, ErrDiscarded
+	}
+	if c.strict && !c.scheduled {
+		if h != nil {
+			c.settleLocked(h, time.Time{}, false)
+		}
+		prev = c.current
+		c.unlockState(before)
+		return prev, false, ErrNotScheduled
+	}
+	if h != nil {
+		c.awaiting = append(c.awaiting, h)
+	}
+	next := at
+	if next.IsZero() {
+		var lastReset time.Duration
+		for i := len(c.resetArg); i > 0; i-- {
+			arg := c.resetArg[i-1]
+			if arg != nil {
+				lastReset = nonNegative(*arg)
+				break
+			}
+		}
+		next = c.current.Add(lastReset)
+	}
+	next = c.format.apply(next)
+	policy := c.policy
+	if bounded && policy == SendBlock {
+		policy = SendError
+	}
 
-	c.TimeCh <- next
+	prev, c.current = c.current, next.Add(c.step())
+	sent := ClockEvent{Kind: ClockEventSend, At: prev, Value: next, SentAt: time.Now()}
+	if c.latch {
+		c.history = append(c.history, sent)
+		c.scheduled, c.fired = false, true
+		c.drainLatchLocked()
+		if c.latchCh != nil {
+			c.latchCh <- next
+		} else {
+			c.latched, c.hasLatched = next, true
+		}
+		if h != nil {
+			c.settleLocked(h, next, true)
+		}
+		c.unlockState(before)
+		return prev, true, nil
+	}
+	event := -1
+	if policy == SendBlock || policy == SendQueue {
+		event = len(c.history)
+		c.history = append(c.history, sent)
+	}
+	if policy == SendQueue {
+		c.scheduled, c.fired = false, true
+		c.queue = append(c.queue, queuedSend{next, h, event})
+		if !c.pumping {
+			c.pumping = true
+			go c.pump()
+		}
+		c.unlockState(before)
+		return prev, true, nil
+	}
+	c.sending = true
+	grace, armed, ch := c.grace, c.armGen, c.chanLocked()
+	var cancel <-chan struct{}
+	if gen != 0 {
+		cancel = c.disarmed
+	}
+	c.unlockState(before)
+
+	switch policy {
+	case SendDrop:
+		delivered = ch.TrySend(next)
+	case SendError:
+		timer := time.NewTimer(grace)
+		err = ch.sendCancel(next, timer.C, cancel)
+		timer.Stop()
+	default:
+		// ch is never closed, so the send fails only if canceled.
+		err = ch.sendCancel(next, nil, cancel)
+	}
+	canceled := errors.Is(err, errSendCanceled)
+	if canceled {
+		err = ErrDiscarded
+	}
+	if policy != SendDrop {
+		delivered = err == nil
+	}
 
+	receivedAt := time.Now()
 	c.Lock()
-	c.scheduled = false
+	before = c.stateLocked()
 	c.sending = false
-	c.Unlock()
-	return prev
+	discarded := delivered && c.takeDrained(next)
+	if discarded {
+		delivered, err = false, ErrDiscarded
+	}
+	if h != nil {
+		c.settleLocked(h, next, delivered)
+	}
+	// A Reset or Stop made while the value is in flight decides the state.
+	if delivered && c.armGen == armed {
+		c.scheduled, c.fired = false, true
+	}
+	if bounded && errors.Is(err, ErrNoReceiver) && c.armGen == armed {
+		c.scheduled = false
+	}
+	switch {
+	case discarded:
+	case !delivered:
+		// A value dropped, given up or canceled leaves the current time unchanged.
+		if c.current.Equal(next.Add(c.step())) {
+			c.current = prev
+		}
+	case policy == SendBlock:
+		c.received(event, receivedAt)
+	default:
+		c.history = append(c.history, sent)
+		c.received(len(c.history)-1, receivedAt)
+	}
+	if delivered && !c.invariant.holds(next, c.current) {
+		err = &FireInvariantError{Invariant: c.invariant, Fired: next, Now: c.current}
+	}
+	c.unlockState(before)
+	return prev, delivered, err
+}
+
+// received records on the i-th event of the history that its value was received at the real time at.
+// c.Mutex must be held.
+func (c *ClockFake) received(i int, at time.Time) {
+	c.history[i].ReceivedAt, c.history[i].ReceivedNow = at, c.current
+}
+
+// drainLatchLocked discards the fire left in the latch. c.Mutex must be held.
+func (c *ClockFake) drainLatchLocked() {
+	c.hasLatched = false
+	if c.latchCh != nil {
+		select {
+		case <-c.latchCh:
+		default:
+		}
+	}
+}
+
+// pump delivers queued values under SendQueue.
+func (c *ClockFake) pump() {
+	for {
+		c.Lock()
+		before := c.stateLocked()
+		if len(c.queue) == 0 {
+			c.pumping, c.sending = false, false
+			c.unlockState(before)
+			return
+		}
+		q := c.queue[0]
+		c.sending = true
+		ch := c.chanLocked()
+		c.unlockState(before)
+
+		_ = ch.Send(q.value)
+		receivedAt := time.Now()
+
+		c.Lock()
+		c.queue = c.queue[1:]
+		discarded := c.takeDrained(q.value)
+		if !discarded {
+			c.received(q.event, receivedAt)
+		}
+		if q.handle != nil {
+			c.settleLocked(q.handle, q.value, !discarded)
+		}
+		c.Unlock()
+	}
+}
+
+// Queued returns the number of values queued under SendQueue and not delivered yet.
+func (c *ClockFake) Queued() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.queue)
 }
 
 // ExhaustCh exhausts ResetCh and StopCh.
@@ -259,14 +783,42 @@ func (c *ClockFake) LastReset() (dur time.Duration, ok bool) {
 	return 0, false
 }
 
+// ArmedDeadlines implements ArmedDeadliner.
+// If c is scheduled, it returns the time of the last Reset call plus its argument.
+func (c *ClockFake) ArmedDeadlines() []time.Time {
+	c.Lock()
+	defer c.Unlock()
+	if !c.scheduled {
+		return nil
+	}
+	for i := len(c.history); i > 0; i-- {
+		if e := c.history[i-1]; e.Kind == ClockEventReset {
+			return []time.Time{e.At.Add(nonNegative(e.Duration))}
+		}
+	}
+	return nil
+}
+
+// History returns a copy of events recorded so far.
+func (c *ClockFake) History() []ClockEvent {
+	c.Lock()
+	defer c.Unlock()
+	return append([]ClockEvent(nil), c.history...)
+}
+
 // IsSending determines t is sending a time value to TimeCh.
 // Be cautious that there is always a race condition between channel send and status update.
+//
+// Deprecated: use State, or Subscribe to observe transitions.
 func (c *ClockFake) IsSending() bool {
 	c.Lock()
 	defer c.Unlock()
 	return c.sending
 }
 
+// IsScheduled determines t is Reset and neither fired nor stopped since then.
+//
+// Deprecated: use State, or Subscribe to observe transitions.
 func (c *ClockFake) IsScheduled() bool {
 	c.Lock()
 	defer c.Unlock()
@@ -59,10 +59,19 @@ func TestClockReal(t *testing.T) {
 	c.Reset(time.Minute)
 	require.True(c.Stop())
 
-	c.Reset(0)
-	time.Sleep(time.Millisecond)
-	require.False(c.Stop())
-	require.False(c.Stop())
+	// Zero and negative durations fire immediately, at the time of Reset.
+	for _, d := range []time.Duration{0, -time.Minute} {
+		before := time.Now()
+		c.Reset(d)
+		select {
+		case fired := <-c.C():
+			require.False(fired.Before(before))
+		case <-time.After(time.Second):
+			t.Fatalf("Reset(%s) did not fire immediately", d)
+		}
+		require.False(c.Stop())
+		require.False(c.Stop())
+	}
 }
 
 func TestClockFake(t *testing.T) {
@@ -185,3 +194,282 @@ func channelReceived[T any](ch chan T) func() bool {
 		}
 	}
 }
+
+func TestClockFake_send_policy(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("drop", func(t *testing.T) {
+		require := require.New(t)
+
+		c := mockable.NewClockFake(now, mockable.WithSendPolicy(mockable.SendDrop))
+		c.Reset(time.Second)
+
+		prev, delivered, err := c.TrySend()
+		require.Equal(now, prev)
+		require.False(delivered)
+		require.NoError(err)
+		require.Equal(now, c.Now())
+		require.True(c.IsScheduled())
+
+		received := make(chan time.Time)
+		go func() { received <- <-c.C() }()
+		for {
+			if _, delivered, _ := c.TrySend(); delivered {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		require.Equal(now.Add(time.Second), <-received)
+		require.Equal(now.Add(time.Second+1), c.Now())
+		require.False(c.IsScheduled())
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		require := require.New(t)
+
+		c := mockable.NewClockFake(now, mockable.WithSendPolicy(mockable.SendQueue))
+		c.Reset(time.Second)
+
+		for i := 0; i < 3; i++ {
+			_, delivered, err := c.TrySend()
+			require.True(delivered)
+			require.NoError(err)
+		}
+		require.Equal(3, c.Queued())
+		require.Equal(now.Add(time.Second), <-c.C())
+		require.Equal(now.Add(2*time.Second+1), <-c.C())
+		require.Equal(now.Add(3*time.Second+2), <-c.C())
+		require.Eventually(func() bool { return c.Queued() == 0 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		require := require.New(t)
+
+		c := mockable.NewClockFake(
+			now,
+			mockable.WithSendPolicy(mockable.SendError),
+			mockable.WithSendGracePeriod(time.Millisecond),
+		)
+		c.Reset(time.Second)
+
+		_, delivered, err := c.TrySend()
+		require.False(delivered)
+		require.ErrorIs(err, mockable.ErrNoReceiver)
+		require.Equal(now, c.Now())
+		require.Empty(c.History()[1:])
+
+		c.Send()
+		require.Equal(now, c.Now())
+	})
+}
+
+func TestClockFake_latch(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := mockable.NewClockFake(now, mockable.WithLatch())
+
+	c.Reset(time.Second)
+	// Send never blocks.
+	c.Send()
+
+	ch1 := c.C()
+	require.NotEqual(c.C(), ch1)
+	// The unreceived fire moves to the latest channel.
+	ch2 := c.C()
+	require.Equal(now.Add(time.Second), <-ch2)
+
+	// A fire is delivered to the channel obtained beforehand.
+	c.Reset(time.Second)
+	ch3 := c.C()
+	c.Send()
+	require.Equal(now.Add(2*time.Second+1), <-ch3)
+
+	// Reset discards the unreceived fire.
+	c.Reset(time.Minute)
+	c.Send()
+	c.Reset(time.Minute)
+	select {
+	case <-c.C():
+		t.Fatal("received a fire discarded by Reset")
+	default:
+	}
+	require.Len(c.History(), 7)
+}
+
+func TestClockFake_fire_invariant(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	clock := mockable.NewClockFake(now, mockable.WithFireInvariant(mockable.FireEqualAllowed))
+	clock.Reset(time.Second)
+	go clock.Send()
+	fired := <-clock.C()
+	require.Equal(now.Add(time.Second), fired)
+	require.Equal(fired, clock.Now())
+
+	// The time is moved back while the value is in flight.
+	clock.Reset(time.Second)
+	result := make(chan error)
+	go func() {
+		_, _, err := clock.TrySend()
+		result <- err
+	}()
+	require.Eventually(clock.IsSending, time.Second, time.Millisecond)
+	clock.SetNow(now)
+	<-clock.C()
+	err := <-result
+	var invErr *mockable.FireInvariantError
+	require.ErrorAs(err, &invErr)
+	require.Equal(mockable.FireEqualAllowed, invErr.Invariant)
+	require.Equal(now.Add(2*time.Second), invErr.Fired)
+	require.Equal(now, invErr.Now)
+	require.Contains(err.Error(), "equal-allowed")
+
+	// The default invariant fails the same way, and Send panics.
+	clock = mockable.NewClockFake(now)
+	clock.Reset(time.Second)
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		clock.Send()
+	}()
+	require.Eventually(clock.IsSending, time.Second, time.Millisecond)
+	clock.SetNow(now)
+	<-clock.C()
+	p := <-panicked
+	require.IsType(&mockable.FireInvariantError{}, p)
+
+	// FireNone never validates.
+	clock = mockable.NewClockFake(now, mockable.WithFireInvariant(mockable.FireNone))
+	clock.Reset(time.Second)
+	go func() {
+		_, _, err := clock.TrySend()
+		result <- err
+	}()
+	require.Eventually(clock.IsSending, time.Second, time.Millisecond)
+	clock.SetNow(now)
+	<-clock.C()
+	require.NoError(<-result)
+}
+
+func TestClockFake_reset_in_flight(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	type sendResult struct {
+		delivered bool
+		err       error
+	}
+	trySend := func(clock *mockable.ClockFake) <-chan sendResult {
+		out := make(chan sendResult, 1)
+		go func() {
+			_, delivered, err := clock.TrySend()
+			out <- sendResult{delivered, err}
+		}()
+		require.Eventually(clock.IsSending, time.Second, time.Millisecond)
+		return out
+	}
+
+	// By default the value in flight is discarded.
+	clock := mockable.NewClockFake(now)
+	clock.Reset(time.Second)
+	<-clock.ResetCh
+	result := trySend(clock)
+	clock.Reset(time.Minute)
+	require.Equal(time.Minute, <-clock.ResetCh)
+	r := <-result
+	require.False(r.delivered)
+	require.ErrorIs(r.err, mockable.ErrDiscarded)
+	require.True(clock.IsScheduled())
+	select {
+	case v := <-clock.C():
+		t.Fatalf("received discarded %s", v)
+	default:
+	}
+
+	// With WithPreserveFires, it is received after Reset.
+	clock = mockable.NewClockFake(now, mockable.WithPreserveFires())
+	clock.Reset(time.Second)
+	<-clock.ResetCh
+	result = trySend(clock)
+	clock.Reset(time.Minute)
+	<-clock.ResetCh
+	require.Equal(now.Add(time.Second), <-clock.C())
+	r = <-result
+	require.True(r.delivered)
+	require.NoError(r.err)
+	// The Send completing afterwards does not override the state armed by Reset.
+	require.True(clock.IsScheduled())
+	require.Equal(mockable.ClockScheduled, clock.State())
+}
+
+func TestClockFake_zero_duration(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// By default, zero and negative durations fire on Send, at the time of Reset.
+	clock := mockable.NewClockFake(now)
+	clock.Reset(-time.Minute)
+	require.Equal([]time.Time{now}, clock.ArmedDeadlines())
+	select {
+	case <-clock.C():
+		t.Fatal("fired without Send")
+	case <-time.After(time.Millisecond):
+	}
+	go clock.Send()
+	require.Equal(now, <-clock.C())
+	require.False(clock.Now().Before(now))
+	d, _ := clock.LastReset()
+	require.Equal(-time.Minute, d)
+
+	// ZeroFiresImmediately fires without Send.
+	clock = mockable.NewClockFake(now, mockable.WithZeroDuration(mockable.ZeroFiresImmediately))
+	clock.Reset(0)
+	require.Equal(now, <-clock.C())
+	require.Equal(mockable.ClockFired, clock.State())
+	clock.Reset(-time.Second)
+	require.Equal(now.Add(1), <-clock.C())
+
+	// Stop cancels the fire blocked in the send, as nothing is receiving yet.
+	clock = mockable.NewClockFake(now, mockable.WithZeroDuration(mockable.ZeroFiresImmediately))
+	clock.Reset(0)
+	require.Eventually(clock.IsSending, time.Second, time.Millisecond)
+	clock.Stop()
+	require.Eventually(func() bool { return !clock.IsSending() }, time.Second, time.Millisecond)
+	select {
+	case <-clock.C():
+		t.Fatal("fired after Stop")
+	case <-time.After(5 * time.Millisecond):
+	}
+	require.Equal(mockable.ClockIdle, clock.State())
+
+	// So does Reset, even with WithPreserveFires, which keeps only fires of Send.
+	clock = mockable.NewClockFake(now, mockable.WithZeroDuration(mockable.ZeroFiresImmediately), mockable.WithPreserveFires())
+	clock.Reset(-time.Second)
+	require.Eventually(clock.IsSending, time.Second, time.Millisecond)
+	clock.Reset(time.Hour)
+	require.Eventually(func() bool { return !clock.IsSending() }, time.Second, time.Millisecond)
+	select {
+	case <-clock.C():
+		t.Fatal("fired after Reset")
+	case <-time.After(5 * time.Millisecond):
+	}
+	// The canceled fire leaves the time unchanged, and the deadline is set by the last Reset.
+	require.Equal(now, clock.Now())
+	deadlines := clock.ArmedDeadlines()
+	require.Len(deadlines, 1)
+	require.False(deadlines[0].Before(now.Add(time.Hour)))
+
+	// Positive durations still wait for Send.
+	clock = mockable.NewClockFake(now, mockable.WithZeroDuration(mockable.ZeroFiresImmediately))
+	clock.Reset(time.Second)
+	select {
+	case <-clock.C():
+		t.Fatal("fired without Send")
+	case <-time.After(time.Millisecond):
+	}
+}
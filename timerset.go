@@ -0,0 +1,205 @@
+package mockable
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// TimerSet multiplexes many keyed deadlines onto a single Clock.
+//
+// Deadlines are kept in a heap, so arming, re-arming and cancelling a key is O(log n),
+// and only one underlying timer is armed at a time, for the earliest deadline.
+// Fires are delivered through per-key channels returned by Set and through the callback given to NewTimerSet.
+// Any Clock can back it: ClockReal in production, ClockFake or VirtualTimer in tests.
+type TimerSet[K comparable] struct {
+	mu      sync.Mutex
+	clock   Clock
+	onFire  func(key K, fired time.Time)
+	entries map[K]*timerSetEntry[K]
+	heap    timerSetHeap[K]
+	seq     uint64
+	wake    chan struct{}
+}
+
+type timerSetEntry[K comparable] struct {
+	key      K
+	deadline time.Time
+	seq      uint64
+	index    int
+	ch       chan time.Time
+}
+
+type timerSetHeap[K comparable] []*timerSetEntry[K]
+
+func (h timerSetHeap[K]) Len() int { return len(h) }
+func (h timerSetHeap[K]) Less(i, j int) bool {
+	if !h[i].deadline.Equal(h[j].deadline) {
+		return h[i].deadline.Before(h[j].deadline)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h timerSetHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *timerSetHeap[K]) Push(x any) {
+	e := x.(*timerSetEntry[K])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+func (h *timerSetHeap[K]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	e.index = -1
+	return e
+}
+
+// NewTimerSet returns an empty TimerSet driven by clock.
+// onFire, if non-nil, is called for every fire, sequentially in order of deadlines, by Run.
+// clock is exclusively used by the TimerSet while Run is running.
+func NewTimerSet[K comparable](clock Clock, onFire func(key K, fired time.Time)) *TimerSet[K] {
+	return &TimerSet[K]{
+		clock:   clock,
+		onFire:  onFire,
+		entries: make(map[K]*timerSetEntry[K]),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Set arms key to fire at deadline, replacing its previous deadline if any.
+// The returned channel receives the fire time; it is buffered and used only for this arming.
+func (s *TimerSet[K]) Set(key K, deadline time.Time) <-chan time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	head := s.headLocked()
+	if e, ok := s.entries[key]; ok {
+		heap.Remove(&s.heap, e.index)
+	}
+	s.seq++
+	e := &timerSetEntry[K]{key: key, deadline: deadline, seq: s.seq, ch: make(chan time.Time, 1)}
+	s.entries[key] = e
+	heap.Push(&s.heap, e)
+	s.notifyIfHeadChanged(head)
+	return e.ch
+}
+
+// SetAfter arms key to fire after d from now in terms of the Clock.
+func (s *TimerSet[K]) SetAfter(key K, d time.Duration) <-chan time.Time {
+	return s.Set(key, s.clock.Now().Add(d))
+}
+
+// Cancel disarms key. It reports whether key was armed.
+func (s *TimerSet[K]) Cancel(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return false
+	}
+	head := s.headLocked()
+	heap.Remove(&s.heap, e.index)
+	delete(s.entries, key)
+	s.notifyIfHeadChanged(head)
+	return true
+}
+
+// Deadline returns the deadline of key. ok is false if key is not armed.
+func (s *TimerSet[K]) Deadline(key K) (deadline time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.deadline, true
+}
+
+// Len returns the number of armed keys.
+func (s *TimerSet[K]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+func (s *TimerSet[K]) headLocked() *timerSetEntry[K] {
+	if len(s.heap) == 0 {
+		return nil
+	}
+	return s.heap[0]
+}
+
+// notifyIfHeadChanged wakes Run up to re-arm the clock if the earliest entry changed. s.mu must be held.
+func (s *TimerSet[K]) notifyIfHeadChanged(prev *timerSetEntry[K]) {
+	if s.headLocked() == prev {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// due pops entries whose deadlines are not after now. s.mu must be held.
+func (s *TimerSet[K]) due(now time.Time) []*timerSetEntry[K] {
+	var out []*timerSetEntry[K]
+	for len(s.heap) > 0 && !s.heap[0].deadline.After(now) {
+		e := heap.Pop(&s.heap).(*timerSetEntry[K])
+		delete(s.entries, e.key)
+		out = append(out, e)
+	}
+	return out
+}
+
+// Run fires keys until ctx is done, and returns ctx.Err().
+func (s *TimerSet[K]) Run(ctx context.Context) error {
+	// Keys set so far are taken into account below.
+	select {
+	case <-s.wake:
+	default:
+	}
+	for {
+		s.mu.Lock()
+		now := s.clock.Now()
+		fired := s.due(now)
+		head := s.headLocked()
+		var next time.Time
+		if head != nil {
+			next = head.deadline
+		}
+		s.mu.Unlock()
+
+		for _, e := range fired {
+			e.ch <- now
+			if s.onFire != nil {
+				s.onFire(e.key, now)
+			}
+		}
+
+		armed := head != nil
+		if armed {
+			if d := next.Sub(s.clock.Now()); d > 0 {
+				s.clock.Reset(d)
+			} else {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if armed {
+				s.clock.Stop()
+			}
+			return ctx.Err()
+		case <-s.wake:
+			if armed {
+				s.clock.Stop()
+			}
+		case <-s.clock.C():
+		}
+	}
+}
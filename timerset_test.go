@@ -0,0 +1,110 @@
+package mockable_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimerSet(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	var (
+		mu    sync.Mutex
+		fired []string
+	)
+	s := mockable.NewTimerSet(clock, func(key string, _ time.Time) {
+		mu.Lock()
+		fired = append(fired, key)
+		mu.Unlock()
+	})
+
+	chA := s.Set("a", now.Add(3*time.Second))
+	chB := s.SetAfter("b", time.Second)
+	s.Set("c", now.Add(2*time.Second))
+	require.True(s.Cancel("c"))
+	require.False(s.Cancel("c"))
+	require.Equal(2, s.Len())
+	deadline, ok := s.Deadline("a")
+	require.True(ok)
+	require.Equal(now.Add(3*time.Second), deadline)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error)
+	go func() { errCh <- s.Run(ctx) }()
+
+	require.Equal(time.Second, <-clock.ResetCh)
+	clock.Send()
+	require.Equal(now.Add(time.Second+1), <-chB)
+	require.Equal(2*time.Second-1, <-clock.ResetCh)
+
+	// An earlier deadline re-arms the clock.
+	chD := s.Set("d", now.Add(1500*time.Millisecond))
+	<-clock.StopCh
+	require.Equal(500*time.Millisecond-1, <-clock.ResetCh)
+	clock.Send()
+	<-chD
+	require.Equal(1500*time.Millisecond-1, <-clock.ResetCh)
+
+	// Re-arming a key replaces its deadline.
+	chA = s.Set("a", now.Add(time.Hour))
+	<-clock.StopCh
+	require.Equal(time.Hour-1500*time.Millisecond-1, <-clock.ResetCh)
+	clock.Send()
+	<-chA
+	require.Equal(0, s.Len())
+
+	cancel()
+	require.ErrorIs(<-errCh, context.Canceled)
+
+	mu.Lock()
+	require.Equal([]string{"b", "d", "a"}, fired)
+	mu.Unlock()
+}
+
+func TestTimerSet_many(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+	timer := v.NewTimer()
+
+	const n = 10000
+	var count int
+	done := make(chan struct{})
+	s := mockable.NewTimerSet(timer, func(key int, _ time.Time) {
+		count++
+		if count == n {
+			close(done)
+		}
+	})
+	for i := 0; i < n; i++ {
+		s.SetAfter(i, time.Duration(n-i)*time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	for {
+		select {
+		case <-done:
+			require.Equal(0, s.Len())
+			return
+		default:
+		}
+		if next, ok := v.Next(); ok {
+			v.AdvanceTo(next)
+		} else {
+			time.Sleep(time.Microsecond)
+		}
+	}
+}
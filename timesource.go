@@ -0,0 +1,77 @@
+package mockable
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TimeSource selects where the real implementations take the current time from.
+type TimeSource int
+
+const (
+	// TimeSourcePrecise takes the time from time.Now. This is the default.
+	TimeSourcePrecise TimeSource = iota
+	// TimeSourceCoarse takes the time from a process-wide cache refreshed every CoarseResolution
+	// by a background goroutine. The goroutine is started on use and exits
+	// once the cache has not been read for coarseIdle, so an unused source costs nothing.
+	// It is cheaper than time.Now in hot loops, at the cost of resolution:
+	// the time may lag time.Now by up to CoarseResolution.
+	// It carries no monotonic clock reading.
+	TimeSourceCoarse
+)
+
+// CoarseResolution is the refresh interval of TimeSourceCoarse.
+const CoarseResolution = time.Millisecond
+
+// coarseIdle is how long the refreshing goroutine of TimeSourceCoarse survives without reads.
+const coarseIdle = 100 * CoarseResolution
+
+var (
+	coarseNow     atomic.Int64
+	coarseRunning atomic.Bool
+	coarseUsed    atomic.Bool
+)
+
+func coarseTime() time.Time {
+	coarseUsed.Store(true)
+	if !coarseRunning.Load() {
+		// The cache may be arbitrarily old; refresh it before anyone sees the goroutine running.
+		now := time.Now().Round(0)
+		coarseNow.Store(now.UnixNano())
+		if coarseRunning.CompareAndSwap(false, true) {
+			go coarseRefresh()
+		}
+		return now
+	}
+	return time.Unix(0, coarseNow.Load())
+}
+
+func coarseRefresh() {
+	t := time.NewTicker(CoarseResolution)
+	defer t.Stop()
+	idle := 0
+	for now := range t.C {
+		coarseNow.Store(now.UnixNano())
+		if coarseUsed.Swap(false) {
+			idle = 0
+			continue
+		}
+		idle++
+		if time.Duration(idle)*CoarseResolution < coarseIdle {
+			continue
+		}
+		coarseRunning.Store(false)
+		// A read racing with the store above saw the goroutine running; keep it running for that reader.
+		if !coarseUsed.Load() || !coarseRunning.CompareAndSwap(false, true) {
+			return
+		}
+		idle = 0
+	}
+}
+
+func (s TimeSource) now() time.Time {
+	if s == TimeSourceCoarse {
+		return coarseTime()
+	}
+	return time.Now()
+}
@@ -0,0 +1,45 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeSourceCoarse(t *testing.T) {
+	require := require.New(t)
+
+	n := mockable.NowerReal{Source: mockable.TimeSourceCoarse}
+	now := n.Now()
+	require.InDelta(time.Now().UnixNano(), now.UnixNano(), float64(100*time.Millisecond))
+	require.Equal(now, now.Round(0))
+
+	require.Eventually(func() bool { return n.Now().After(now) }, time.Second, time.Millisecond)
+
+	// The refreshing goroutine exits when unused; the next read still sees the current time.
+	time.Sleep(200 * time.Millisecond)
+	require.WithinDuration(time.Now(), n.Now(), 10*time.Millisecond)
+
+	c := mockable.NewClockReal()
+	c.Source = mockable.TimeSourceCoarse
+	require.Equal(c.Now(), c.Now().Round(0))
+}
+
+func BenchmarkNowerReal(b *testing.B) {
+	for _, bc := range []struct {
+		name   string
+		source mockable.TimeSource
+	}{
+		{"precise", mockable.TimeSourcePrecise},
+		{"coarse", mockable.TimeSourceCoarse},
+	} {
+		n := mockable.NowerReal{Source: bc.source}
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = n.Now()
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package mockable
+
+import (
+	"testing"
+	"time"
+)
+
+// tripwireSlack is how close a time must be to the wall clock, and how far from the fake time,
+// to be taken as read from the wall clock.
+const tripwireSlack = time.Second
+
+// Tripwire catches code escaping the injected fake clock, e.g. by calling time.Now or time.After directly.
+//
+// It relies on the fake time being far from the wall clock, as is for a fixed start time in the past,
+// and on the fake being frozen while it watches: times near the wall clock, values advancing in the real time
+// and fires arriving without the fake moving can then only come from the real clock.
+// Violations are reported to the test by Errorf.
+type Tripwire struct {
+	tb    testing.TB
+	fake  Nower
+	start time.Time
+}
+
+// NewTripwire returns a Tripwire for the code under test driven by fake.
+func NewTripwire(tb testing.TB, fake Nower) *Tripwire {
+	return &Tripwire{tb: tb, fake: fake, start: time.Now()}
+}
+
+// wallClock reports whether at looks read from the wall clock rather than from the fake.
+func (w *Tripwire) wallClock(at time.Time) bool {
+	if abs(at.Sub(w.fake.Now())) <= tripwireSlack {
+		return false
+	}
+	return !at.Before(w.start.Add(-tripwireSlack)) && !at.After(time.Now().Add(tripwireSlack))
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// Check reports a violation if at, a time produced by the code under test and described by what,
+// is near the wall clock while the fake time is not. It returns false on a violation.
+func (w *Tripwire) Check(what string, at time.Time) bool {
+	w.tb.Helper()
+	if w.wallClock(at) {
+		w.tb.Errorf("mockable: tripwire: %s is %s, the wall clock time, while the fake time is %s",
+			what, at.Format(time.RFC3339Nano), w.fake.Now().Format(time.RFC3339Nano))
+		return false
+	}
+	return true
+}
+
+// Nower decorates n, which the code under test uses, so that every time it returns is checked by Check.
+func (w *Tripwire) Nower(what string, n Nower) Nower {
+	return NowerFunc(func() time.Time {
+		now := n.Now()
+		w.Check(what, now)
+		return now
+	})
+}
+
+// Frozen observes a time by observe twice, wait apart in the real time,
+// and reports a violation if it advances while the fake time stays still.
+// It returns false on a violation.
+func (w *Tripwire) Frozen(what string, wait time.Duration, observe func() time.Time) bool {
+	w.tb.Helper()
+	fakeBefore, before := w.fake.Now(), observe()
+	time.Sleep(wait)
+	fakeAfter, after := w.fake.Now(), observe()
+	if fakeAfter.Equal(fakeBefore) && after.After(before) {
+		w.tb.Errorf("mockable: tripwire: %s advanced by %s in %s of the real time while the fake time is frozen at %s",
+			what, after.Sub(before), wait, fakeBefore.Format(time.RFC3339Nano))
+		return false
+	}
+	return true
+}
+
+// ExpectNoFire waits for wait in the real time and reports a violation if ch, fed by the code under test,
+// receives meanwhile without the fake time moving. Received values are consumed.
+// It returns false on a violation.
+func (w *Tripwire) ExpectNoFire(what string, wait time.Duration, ch <-chan time.Time) bool {
+	w.tb.Helper()
+	fakeBefore := w.fake.Now()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case fired := <-ch:
+		if !w.fake.Now().Equal(fakeBefore) {
+			return true
+		}
+		w.tb.Errorf("mockable: tripwire: %s fired at %s while the fake time is frozen at %s",
+			what, fired.Format(time.RFC3339Nano), fakeBefore.Format(time.RFC3339Nano))
+		return false
+	}
+}
@@ -0,0 +1,66 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTripwire_Check(t *testing.T) {
+	require := require.New(t)
+
+	fake := mockable.NewClockFake(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	tb := &fakeTB{TB: t}
+	w := mockable.NewTripwire(tb, fake)
+
+	require.True(w.Check("record time", fake.Now()))
+	require.True(w.Check("record time", fake.Now().Add(time.Hour)))
+	require.False(tb.failed)
+
+	require.False(w.Check("record time", time.Now()))
+	require.True(tb.failed)
+	require.Len(tb.logs, 1)
+	require.Contains(tb.logs[0], "record time")
+
+	// The decorated Nower checks every time it returns.
+	tb = &fakeTB{TB: t}
+	w = mockable.NewTripwire(tb, fake)
+	w.Nower("injected", fake).Now()
+	require.False(tb.failed)
+	w.Nower("escaped", mockable.NowerReal{}).Now()
+	require.True(tb.failed)
+	require.Contains(tb.logs[0], "escaped")
+}
+
+func TestTripwire_Frozen(t *testing.T) {
+	require := require.New(t)
+
+	fake := mockable.NewClockFake(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	tb := &fakeTB{TB: t}
+	w := mockable.NewTripwire(tb, fake)
+
+	require.True(w.Frozen("fake", 5*time.Millisecond, fake.Now))
+	require.False(tb.failed)
+
+	require.False(w.Frozen("wall", 5*time.Millisecond, time.Now))
+	require.True(tb.failed)
+	require.Contains(tb.logs[0], "frozen")
+}
+
+func TestTripwire_ExpectNoFire(t *testing.T) {
+	require := require.New(t)
+
+	fake := mockable.NewClockFake(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	tb := &fakeTB{TB: t}
+	w := mockable.NewTripwire(tb, fake)
+
+	fake.Reset(time.Second)
+	require.True(w.ExpectNoFire("fake timer", 5*time.Millisecond, fake.C()))
+	require.False(tb.failed)
+
+	require.False(w.ExpectNoFire("time.After", time.Second, time.After(time.Millisecond)))
+	require.True(tb.failed)
+	require.Contains(tb.logs[0], "time.After")
+}
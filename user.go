@@ -0,0 +1,116 @@
+package mockable
+
+import (
+	"os/user"
+	"strconv"
+	"sync"
+)
+
+// The UserLookup is a mockable interface of the os/user package.
+type UserLookup interface {
+	Current() (*user.User, error)
+	Lookup(username string) (*user.User, error)
+	LookupId(uid string) (*user.User, error)
+}
+
+var _ UserLookup = UserLookupReal{}
+
+// UserLookupReal is an implementation of the UserLookup interface.
+// It only wraps functions of the os/user package.
+type UserLookupReal struct{}
+
+func (UserLookupReal) Current() (*user.User, error) {
+	return user.Current()
+}
+
+func (UserLookupReal) Lookup(username string) (*user.User, error) {
+	return user.Lookup(username)
+}
+
+func (UserLookupReal) LookupId(uid string) (*user.User, error) {
+	return user.LookupId(uid)
+}
+
+var _ UserLookup = (*UserLookupFake)(nil)
+
+// UserLookupFake is an implementation of the UserLookup interface serving registered users.
+//
+// Unknown names and ids fail with user.UnknownUserError and user.UnknownUserIdError respectively,
+// as the os/user package does on unix. Non-numeric unknown ids fail with the strconv error.
+// Returned users are copies; mutating them does not affect UserLookupFake.
+type UserLookupFake struct {
+	mu      sync.Mutex
+	users   []user.User
+	current string
+	// CurrentErr, if non-nil, is returned from Current.
+	CurrentErr error
+}
+
+// NewUserLookupFake returns a UserLookupFake serving users.
+// The first user, if any, is the current user.
+func NewUserLookupFake(users ...user.User) *UserLookupFake {
+	u := &UserLookupFake{}
+	for _, usr := range users {
+		u.Add(usr)
+	}
+	if len(users) > 0 {
+		u.current = users[0].Uid
+	}
+	return u
+}
+
+// Add adds usr. A user with the same Uid is replaced.
+func (u *UserLookupFake) Add(usr user.User) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i := range u.users {
+		if u.users[i].Uid == usr.Uid {
+			u.users[i] = usr
+			return
+		}
+	}
+	u.users = append(u.users, usr)
+}
+
+// SetCurrent sets the current user to the user whose Uid is uid.
+func (u *UserLookupFake) SetCurrent(uid string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.current = uid
+}
+
+func (u *UserLookupFake) Current() (*user.User, error) {
+	if u.CurrentErr != nil {
+		return nil, u.CurrentErr
+	}
+	u.mu.Lock()
+	uid := u.current
+	u.mu.Unlock()
+	return u.LookupId(uid)
+}
+
+func (u *UserLookupFake) Lookup(username string) (*user.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, usr := range u.users {
+		if usr.Username == username {
+			return &usr, nil
+		}
+	}
+	return nil, user.UnknownUserError(username)
+}
+
+func (u *UserLookupFake) LookupId(uid string) (*user.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, usr := range u.users {
+		if usr.Uid == uid {
+			return &usr, nil
+		}
+	}
+	id, err := strconv.Atoi(uid)
+	if err != nil {
+		return nil, err
+	}
+	return nil, user.UnknownUserIdError(id)
+}
@@ -0,0 +1,63 @@
+package mockable_test
+
+import (
+	"errors"
+	"os/user"
+	"testing"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserLookupReal(t *testing.T) {
+	require := require.New(t)
+
+	var u mockable.UserLookup = mockable.UserLookupReal{}
+	current, err := u.Current()
+	if err != nil {
+		t.Skipf("current user is not available: %v", err)
+	}
+	byId, err := u.LookupId(current.Uid)
+	require.NoError(err)
+	require.Equal(current.Username, byId.Username)
+}
+
+func TestUserLookupFake(t *testing.T) {
+	require := require.New(t)
+
+	alice := user.User{Uid: "1000", Gid: "1000", Username: "alice", HomeDir: "/home/alice"}
+	bob := user.User{Uid: "1001", Gid: "1001", Username: "bob", HomeDir: "/home/bob"}
+	u := mockable.NewUserLookupFake(alice, bob)
+
+	current, err := u.Current()
+	require.NoError(err)
+	require.Equal(alice, *current)
+
+	u.SetCurrent("1001")
+	current, err = u.Current()
+	require.NoError(err)
+	require.Equal(bob, *current)
+
+	found, err := u.Lookup("alice")
+	require.NoError(err)
+	require.Equal(alice, *found)
+	found.HomeDir = "/tmp"
+	found, err = u.LookupId("1000")
+	require.NoError(err)
+	require.Equal("/home/alice", found.HomeDir)
+
+	_, err = u.Lookup("carol")
+	require.Equal(user.UnknownUserError("carol"), err)
+	_, err = u.LookupId("1002")
+	require.Equal(user.UnknownUserIdError(1002), err)
+
+	u.Add(user.User{Uid: "1000", Username: "alice", HomeDir: "/var/alice"})
+	found, err = u.Lookup("alice")
+	require.NoError(err)
+	require.Equal("/var/alice", found.HomeDir)
+
+	sentinel := errors.New("user: Current requires cgo")
+	u.CurrentErr = sentinel
+	_, err = u.Current()
+	require.ErrorIs(err, sentinel)
+}
@@ -0,0 +1,406 @@
+package mockable
+
+import (
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// VirtualClock is a virtual time source shared by any number of timers.
+//
+// Unlike ClockFake, which is a single timer driven by Send,
+// a VirtualClock fires every timer created by NewTimer when the virtual time is advanced past its deadline,
+// in order of deadlines. Like runtime timers, fired timers send the fire time to their buffered channels
+// without blocking, so Advance never waits for receivers.
+//
+// Timers sharing a deadline fire in the order they were created by NewTimer, regardless of the order they were armed.
+// Goroutines waiting on them are therefore made runnable in that order,
+// though which of runnable goroutines runs first is up to the Go scheduler.
+// Use WithFireHook to observe the fire order.
+//
+// VirtualClock is perfectly deterministic unless chaos is enabled by WithChaos,
+// which perturbs the order of fires sharing a deadline and delays fires by bounded latency.
+type VirtualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers map[*VirtualTimer]struct{}
+	lastID uint64
+	onFire func(t *VirtualTimer, at time.Time)
+	fired  []virtualFire
+	// lastActivity is the real time of the last operation on timers or fire.
+	lastActivity time.Time
+
+	// alarms are pending alarms sorted by time and then by id.
+	alarms       []*Alarm
+	alarmHistory []AlarmRecord
+
+	barriers []time.Time
+	pauseAt  *time.Time
+	paused   chan struct{}
+	// lastBarrier is the barrier v paused at most recently.
+	lastBarrier time.Time
+
+	autoAdvance QuiescenceDetector
+	closeOnce   sync.Once
+	closeCh     chan struct{}
+	autoDone    chan struct{}
+
+	chaos      *rand.Rand
+	chaosSeed  int64
+	maxLatency time.Duration
+
+	// real is true in the real-time mode, where realFired counts fires and executed alarms.
+	real      bool
+	realFired uint64
+
+	decisions []Decision
+	replay    map[DecisionKind][]Decision
+	replayErr error
+}
+
+// VirtualClockOption configures VirtualClock.
+type VirtualClockOption func(v *VirtualClock)
+
+// WithChaos enables the chaos mode.
+//
+// In the chaos mode, timers sharing a deadline fire in a random order,
+// and each fire is delayed by a random latency in [0, maxLatency].
+// Randomness is drawn from a generator seeded by seed, so a run can be reproduced by the same seed
+// as long as the code under test arms timers in the same order.
+func WithChaos(seed int64, maxLatency time.Duration) VirtualClockOption {
+	return func(v *VirtualClock) {
+		if maxLatency < 0 {
+			maxLatency = 0
+		}
+		v.chaos = rand.New(rand.NewSource(seed))
+		v.chaosSeed = seed
+		v.maxLatency = maxLatency
+	}
+}
+
+// WithFireHook sets a callback called for every fire, in the fire order.
+// It is called after Advance, AdvanceTo or Reset has released the internal lock,
+// so it may call methods of the VirtualClock and its timers.
+func WithFireHook(fn func(t *VirtualTimer, at time.Time)) VirtualClockOption {
+	return func(v *VirtualClock) {
+		v.onFire = fn
+	}
+}
+
+// ChaosSeedEnv is the environment variable from which WithChaosT reads the seed.
+const ChaosSeedEnv = "MOCKABLE_CHAOS_SEED"
+
+// WithChaosT is like WithChaos but chooses the seed for the test t.
+//
+// The seed is read from the environment variable named by ChaosSeedEnv if set, or chosen randomly otherwise.
+// If t fails, the seed is logged so that the failing run can be reproduced by setting the variable.
+func WithChaosT(t testing.TB, maxLatency time.Duration) VirtualClockOption {
+	t.Helper()
+	seed := time.Now().UnixNano()
+	if s, ok := os.LookupEnv(ChaosSeedEnv); ok {
+		parsed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			t.Fatalf("mockable: invalid %s %q: %v", ChaosSeedEnv, s, err)
+		}
+		seed = parsed
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("mockable: chaos seed = %d; reproduce with %s=%d", seed, ChaosSeedEnv, seed)
+		}
+	})
+	return WithChaos(seed, maxLatency)
+}
+
+// NewVirtualClock returns a VirtualClock whose virtual time starts at start.
+func NewVirtualClock(start time.Time, options ...VirtualClockOption) *VirtualClock {
+	v := &VirtualClock{
+		now:          start,
+		timers:       make(map[*VirtualTimer]struct{}),
+		lastActivity: time.Now(),
+		paused:       make(chan struct{}),
+		closeCh:      make(chan struct{}),
+	}
+	for _, opt := range options {
+		opt(v)
+	}
+	if v.autoAdvance != nil && !v.real {
+		v.autoDone = make(chan struct{})
+		go v.runAutoAdvance()
+	}
+	return v
+}
+
+// ChaosSeed returns the seed of the chaos mode. ok is false if the chaos mode is disabled.
+func (v *VirtualClock) ChaosSeed() (seed int64, ok bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.chaosSeed, v.chaos != nil
+}
+
+// Now implements Nower.
+func (v *VirtualClock) Now() time.Time {
+	if v.real {
+		return time.Now()
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+// NewTimer returns a stopped VirtualTimer driven by v.
+func (v *VirtualClock) NewTimer() *VirtualTimer {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lastActivity = time.Now()
+	v.lastID++
+	return &VirtualTimer{
+		v:  v,
+		id: v.lastID,
+		ch: make(chan time.Time, 1),
+	}
+}
+
+// Pending returns the number of armed timers and pending alarms.
+func (v *VirtualClock) Pending() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.timers) + len(v.alarms)
+}
+
+// Next returns the earliest deadline among armed timers and pending alarms.
+// ok is false if there is none.
+func (v *VirtualClock) Next() (next time.Time, ok bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.next()
+}
+
+// next is Next without locking. v.mu must be held.
+func (v *VirtualClock) next() (next time.Time, ok bool) {
+	if len(v.alarms) > 0 {
+		next, ok = v.alarms[0].at, true
+	}
+	for t := range v.timers {
+		if !ok || t.when.Before(next) {
+			next, ok = t.when, true
+		}
+	}
+	return next, ok
+}
+
+// ArmedDeadlines implements ArmedDeadliner.
+// It returns the requested deadlines of armed timers, excluding latency added by the chaos mode.
+func (v *VirtualClock) ArmedDeadlines() []time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	out := make([]time.Time, 0, len(v.timers))
+	for t := range v.timers {
+		out = append(out, t.deadline)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
+
+// Advance advances the virtual time by d, firing every timer whose deadline comes meanwhile.
+// It returns the number of fired timers and executed alarms.
+func (v *VirtualClock) Advance(d time.Duration) int {
+	if v.real {
+		return v.realWait(time.Now().Add(d))
+	}
+	v.mu.Lock()
+	defer v.unlock()
+	return v.advanceTo(v.now.Add(d))
+}
+
+// AdvanceTo advances the virtual time to t, firing every timer whose deadline is not after t.
+// It returns the number of fired timers and executed alarms. The virtual time never goes backwards.
+func (v *VirtualClock) AdvanceTo(t time.Time) int {
+	if v.real {
+		return v.realWait(t)
+	}
+	v.mu.Lock()
+	defer v.unlock()
+	return v.advanceTo(t)
+}
+
+// unlock releases v.mu and then calls the fire hook for fires made while it was held.
+func (v *VirtualClock) unlock() {
+	fired := v.fired
+	v.fired = nil
+	v.mu.Unlock()
+	if v.onFire != nil {
+		for _, f := range fired {
+			v.onFire(f.timer, f.at)
+		}
+	}
+}
+
+// advanceTo advances the virtual time to t. v.mu must be held; it is released while alarm callbacks run.
+func (v *VirtualClock) advanceTo(t time.Time) int {
+	if v.pauseAt != nil {
+		return 0
+	}
+	barrier, stop := v.nextBarrier(t)
+	if stop {
+		t = barrier
+	}
+
+	var n int
+	for {
+		due := v.due(t)
+		if len(v.alarms) > 0 && !v.alarms[0].at.After(t) &&
+			(len(due) == 0 || !due[0].when.Before(v.alarms[0].at)) {
+			if v.alarms[0].at.After(v.now) {
+				v.now = v.alarms[0].at
+			}
+			v.lastActivity = time.Now()
+			a := v.settleAlarm(0, AlarmExecuted)
+			v.mu.Unlock()
+			a.fn(a.at)
+			v.mu.Lock()
+			n++
+			continue
+		}
+		if len(due) == 0 {
+			break
+		}
+		timer := due[v.pick(len(due))]
+		if timer.when.After(v.now) {
+			v.now = timer.when
+		}
+		v.fire(timer)
+		n++
+	}
+	if t.After(v.now) {
+		v.now = t
+	}
+	if stop {
+		v.pause(barrier)
+	}
+	return n
+}
+
+// due returns timers sharing the earliest deadline, if it is not after t, in creation order.
+// v.mu must be held.
+func (v *VirtualClock) due(t time.Time) []*VirtualTimer {
+	var out []*VirtualTimer
+	for timer := range v.timers {
+		if timer.when.After(t) {
+			continue
+		}
+		if len(out) > 0 && timer.when.Before(out[0].when) {
+			out = out[:0]
+		}
+		if len(out) == 0 || timer.when.Equal(out[0].when) {
+			out = append(out, timer)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+	return out
+}
+
+// fire sends the fire time of t without blocking and disarms it. v.mu must be held.
+func (v *VirtualClock) fire(t *VirtualTimer) {
+	delete(v.timers, t)
+	v.lastActivity = time.Now()
+	select {
+	case t.ch <- t.when:
+	default:
+	}
+	if v.onFire != nil {
+		v.fired = append(v.fired, virtualFire{t, t.when})
+	}
+}
+
+type virtualFire struct {
+	timer *VirtualTimer
+	at    time.Time
+}
+
+var _ Clock = (*VirtualTimer)(nil)
+
+// VirtualTimer is a Timer driven by a VirtualClock. It also implements Clock,
+// reporting the virtual time of its VirtualClock.
+type VirtualTimer struct {
+	v  *VirtualClock
+	id uint64
+	ch chan time.Time
+	// deadline is the requested deadline and when is the time t actually fires,
+	// which may be later than deadline in the chaos mode.
+	deadline time.Time
+	when     time.Time
+	// rt is the runtime timer backing t in the real-time mode, and gen tells its stale fires.
+	rt  *time.Timer
+	gen uint64
+}
+
+// ID returns the sequence number of t in creation order, starting from 1.
+func (t *VirtualTimer) ID() uint64 {
+	return t.id
+}
+
+// Now implements Nower.
+func (t *VirtualTimer) Now() time.Time {
+	return t.v.Now()
+}
+
+// C implements Timer.
+func (t *VirtualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+// Stop implements Timer. It returns true if t was armed.
+func (t *VirtualTimer) Stop() bool {
+	t.v.mu.Lock()
+	defer t.v.mu.Unlock()
+	t.v.lastActivity = time.Now()
+	if t.v.real {
+		t.v.realDisarm(t)
+	}
+	_, armed := t.v.timers[t]
+	delete(t.v.timers, t)
+	return armed
+}
+
+// Reset implements Timer. It stops and drains t, then arms it to fire after d.
+// If d is not positive and no latency is added by the chaos mode, t fires immediately.
+func (t *VirtualTimer) Reset(d time.Duration) {
+	v := t.v
+	v.mu.Lock()
+	defer v.unlock()
+
+	v.lastActivity = time.Now()
+	delete(v.timers, t)
+	select {
+	case <-t.ch:
+	default:
+	}
+
+	if d < 0 {
+		d = 0
+	}
+	if v.real {
+		v.realDisarm(t)
+		if d == 0 {
+			t.deadline, t.when = time.Now(), time.Now()
+			v.fire(t)
+			v.realFired++
+			return
+		}
+		v.realArm(t, d)
+		return
+	}
+	t.deadline = v.now.Add(d)
+	d += v.latency()
+	t.when = v.now.Add(d)
+	if d == 0 {
+		v.fire(t)
+		return
+	}
+	v.timers[t] = struct{}{}
+}
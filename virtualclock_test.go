@@ -0,0 +1,197 @@
+package mockable_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualClock(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start)
+
+	t1, t2, t3 := v.NewTimer(), v.NewTimer(), v.NewTimer()
+	require.False(t1.Stop())
+
+	t1.Reset(3 * time.Second)
+	t2.Reset(time.Second)
+	t3.Reset(2 * time.Second)
+	require.Equal(3, v.Pending())
+	next, ok := v.Next()
+	require.True(ok)
+	require.Equal(start.Add(time.Second), next)
+
+	require.True(t3.Stop())
+	require.Equal(1, v.Advance(2*time.Second))
+	require.Equal(start.Add(2*time.Second), v.Now())
+	require.Equal(start.Add(time.Second), <-t2.C())
+	require.False(received(t3.C()))
+
+	// Resetting drains the unreceived fire.
+	t2.Reset(time.Hour)
+	require.Equal(1, v.Advance(time.Second))
+	require.Equal(start.Add(3*time.Second), <-t1.C())
+	require.False(received(t2.C()))
+
+	require.Equal(1, v.AdvanceTo(start.Add(2*time.Hour)))
+	require.Equal(start.Add(time.Hour+2*time.Second), <-t2.C())
+	require.Equal(start.Add(2*time.Hour), t1.Now())
+
+	// The virtual time never goes backwards.
+	require.Equal(0, v.AdvanceTo(start))
+	require.Equal(start.Add(2*time.Hour), v.Now())
+
+	t1.Reset(0)
+	require.Equal(start.Add(2*time.Hour), <-t1.C())
+	require.Equal(0, v.Pending())
+}
+
+func received(ch <-chan time.Time) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func fireOrder(seed int64) (order []int, at []time.Time) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	v := mockable.NewVirtualClock(start, mockable.WithChaos(seed, 100*time.Millisecond))
+
+	timers := make([]*mockable.VirtualTimer, 8)
+	for i := range timers {
+		timers[i] = v.NewTimer()
+		timers[i].Reset(time.Second)
+	}
+	for v.Pending() > 0 {
+		next, _ := v.Next()
+		v.AdvanceTo(next)
+		for i, t := range timers {
+			select {
+			case fired := <-t.C():
+				order = append(order, i)
+				at = append(at, fired)
+			default:
+			}
+		}
+	}
+	return order, at
+}
+
+func TestVirtualClock_chaos(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	order, at := fireOrder(42)
+	require.Len(order, 8)
+	for _, fired := range at {
+		require.False(fired.Before(start.Add(time.Second)))
+		require.False(fired.After(start.Add(1100 * time.Millisecond)))
+	}
+
+	// The same seed reproduces the same run.
+	order2, at2 := fireOrder(42)
+	require.Equal(order, order2)
+	require.Equal(at, at2)
+
+	v := mockable.NewVirtualClock(start, mockable.WithChaos(42, 0))
+	seed, ok := v.ChaosSeed()
+	require.True(ok)
+	require.Equal(int64(42), seed)
+
+	// Timers armed for the same deadline fire in different orders with different seeds.
+	distinct := map[string]bool{}
+	for s := int64(0); s < 10; s++ {
+		order, _ := fireOrder(s)
+		distinct[fmt.Sprint(order)] = true
+	}
+	require.Greater(len(distinct), 1)
+}
+
+type fakeTB struct {
+	testing.TB
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (t *fakeTB) Helper()           {}
+func (t *fakeTB) Failed() bool      { return t.failed }
+func (t *fakeTB) Cleanup(fn func()) { t.cleanups = append(t.cleanups, fn) }
+func (t *fakeTB) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.failed = true
+	t.Logf(format, args...)
+}
+
+func TestWithChaosT(t *testing.T) {
+	require := require.New(t)
+
+	t.Setenv(mockable.ChaosSeedEnv, "1234")
+
+	tb := &fakeTB{TB: t}
+	v := mockable.NewVirtualClock(time.Time{}, mockable.WithChaosT(tb, time.Millisecond))
+	seed, ok := v.ChaosSeed()
+	require.True(ok)
+	require.Equal(int64(1234), seed)
+
+	tb.cleanups[0]()
+	require.Empty(tb.logs)
+
+	tb.failed = true
+	tb.cleanups[0]()
+	require.Len(tb.logs, 1)
+	require.Contains(tb.logs[0], "MOCKABLE_CHAOS_SEED=1234")
+}
+
+func TestVirtualClock_fifo(t *testing.T) {
+	require := require.New(t)
+
+	var fired []uint64
+	v := mockable.NewVirtualClock(
+		time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		mockable.WithFireHook(func(t *mockable.VirtualTimer, at time.Time) {
+			fired = append(fired, t.ID())
+		}),
+	)
+
+	timers := make([]*mockable.VirtualTimer, 16)
+	for i := range timers {
+		timers[i] = v.NewTimer()
+		require.Equal(uint64(i+1), timers[i].ID())
+	}
+	// Arm in the reverse order, with the same deadline except the last one.
+	for i := len(timers) - 1; i >= 0; i-- {
+		d := time.Second
+		if i == 0 {
+			d = 2 * time.Second
+		}
+		timers[i].Reset(d)
+	}
+
+	for round := 0; round < 10; round++ {
+		fired = fired[:0]
+		require.Equal(16, v.Advance(2*time.Second))
+		require.Equal(
+			[]uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 1},
+			fired,
+		)
+		for i, t := range timers {
+			<-t.C()
+			d := time.Second
+			if i == 0 {
+				d = 2 * time.Second
+			}
+			t.Reset(d)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mockable
+
+import "time"
+
+// WithRealTime makes VirtualClock delegate to real time, for integration and end-to-end runs.
+//
+// The same code can then be wired with a VirtualClock in production and in tests,
+// switching between virtual and real time by this option alone.
+// In the real-time mode:
+//   - Now returns the real current time and the start time given to NewVirtualClock is ignored.
+//   - Timers and alarms are backed by runtime timers. A fired timer sends the real time it fired at.
+//     Alarms sharing a time are not guaranteed to be called in scheduling order.
+//   - Advance and AdvanceTo wait for the real time to pass, returning the number of fires and alarms meanwhile.
+//   - The chaos, auto-advance and replay modes and barriers have no effect.
+func WithRealTime() VirtualClockOption {
+	return func(v *VirtualClock) {
+		v.real = true
+	}
+}
+
+// RealTime reports whether v is in the real-time mode.
+func (v *VirtualClock) RealTime() bool {
+	return v.real
+}
+
+// realWait waits until the real time reaches t and returns the number of fires and alarms meanwhile.
+func (v *VirtualClock) realWait(t time.Time) int {
+	v.mu.Lock()
+	before := v.realFired
+	v.mu.Unlock()
+	if d := time.Until(t); d > 0 {
+		time.Sleep(d)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return int(v.realFired - before)
+}
+
+// realArm arms t with a runtime timer. v.mu must be held.
+func (v *VirtualClock) realArm(t *VirtualTimer, d time.Duration) {
+	t.gen++
+	gen := t.gen
+	t.deadline = time.Now().Add(d)
+	t.when = t.deadline
+	v.timers[t] = struct{}{}
+	t.rt = time.AfterFunc(d, func() {
+		v.mu.Lock()
+		defer v.unlock()
+		if _, armed := v.timers[t]; !armed || t.gen != gen {
+			return
+		}
+		t.when = time.Now()
+		v.fire(t)
+		v.realFired++
+	})
+}
+
+// realDisarm stops the runtime timer of t. v.mu must be held.
+func (v *VirtualClock) realDisarm(t *VirtualTimer) {
+	t.gen++
+	if t.rt != nil {
+		t.rt.Stop()
+		t.rt = nil
+	}
+}
+
+// realSchedule runs a with a runtime timer. v.mu must be held.
+func (v *VirtualClock) realSchedule(a *Alarm) {
+	time.AfterFunc(time.Until(a.at), func() {
+		v.mu.Lock()
+		for i, p := range v.alarms {
+			if p == a {
+				v.lastActivity = time.Now()
+				v.settleAlarm(i, AlarmExecuted)
+				v.mu.Unlock()
+				a.fn(a.at)
+				// Counted after the callback returns so that Advance observes its effects.
+				v.mu.Lock()
+				v.realFired++
+				v.mu.Unlock()
+				return
+			}
+		}
+		v.mu.Unlock()
+	})
+}
@@ -0,0 +1,63 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVirtualClock_modes runs the same scenario against the virtual and the real-time modes.
+func TestVirtualClock_modes(t *testing.T) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		unit time.Duration
+		v    *mockable.VirtualClock
+	}{
+		{"virtual", time.Second, mockable.NewVirtualClock(start)},
+		{"real", 20 * time.Millisecond, mockable.NewVirtualClock(start, mockable.WithRealTime())},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+			v, unit := tc.v, tc.unit
+
+			begin := v.Now()
+			timer := v.NewTimer()
+			require.False(timer.Stop())
+
+			timer.Reset(2 * unit)
+			require.Equal(1, v.Pending())
+			next, ok := v.Next()
+			require.True(ok)
+			require.False(next.Before(begin.Add(2 * unit)))
+			require.True(timer.Stop())
+			require.Equal(0, v.Pending())
+
+			var alarmed time.Time
+			alarm := v.ScheduleAfter(unit, func(now time.Time) { alarmed = now })
+			cancelled := v.ScheduleAfter(unit, func(now time.Time) { panic("cancelled") })
+			require.True(cancelled.Cancel())
+
+			timer.Reset(2 * unit)
+			require.Equal(2, v.Advance(4*unit))
+			fired := <-timer.C()
+			require.False(fired.Before(begin.Add(2 * unit)))
+			require.False(v.Now().Before(begin.Add(4 * unit)))
+			require.Equal(alarm.At(), alarmed)
+			require.Equal(mockable.AlarmExecuted, alarm.State())
+			require.Equal(0, v.Pending())
+
+			history := v.AlarmHistory()
+			require.Len(history, 2)
+			require.Equal(mockable.AlarmCancelled, history[0].State)
+			require.Equal(mockable.AlarmExecuted, history[1].State)
+
+			// Reset with 0 fires immediately.
+			timer.Reset(0)
+			require.True(received(timer.C()))
+			require.Equal(tc.name == "real", v.RealTime())
+		})
+	}
+}
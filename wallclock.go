@@ -0,0 +1,107 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+var _ Clock = (*ClockRealWall)(nil)
+
+// ClockRealWall implements Clock using runtime timers, but measures timeouts by the wall clock.
+//
+// Runtime timers are driven by the monotonic clock, which may stop while the machine is suspended
+// or the VM is paused, so a long timeout can fire far later than intended in terms of the wall clock.
+// ClockRealWall instead arms runtime timers for at most the step at a time
+// and re-checks the wall clock on every wake, so the timer fires within about the step
+// after the intended wall time even across suspend and resume.
+type ClockRealWall struct {
+	// Wall is the wall clock. It defaults to NowerReal. Set it before the first Reset.
+	Wall Nower
+
+	mu     sync.Mutex
+	step   time.Duration
+	ch     chan time.Time
+	cancel chan struct{}
+}
+
+// NewClockRealWall returns a stopped ClockRealWall which re-checks the wall clock at least every step.
+// If step is not positive, it defaults to 1s.
+func NewClockRealWall(step time.Duration) *ClockRealWall {
+	if step <= 0 {
+		step = time.Second
+	}
+	return &ClockRealWall{
+		Wall: NowerReal{},
+		step: step,
+		ch:   make(chan time.Time, 1),
+	}
+}
+
+// Now implements Nower. It returns the time from Wall.
+func (c *ClockRealWall) Now() time.Time {
+	return c.Wall.Now()
+}
+
+// C implements Timer.
+func (c *ClockRealWall) C() <-chan time.Time {
+	return c.ch
+}
+
+// Stop implements Timer.
+func (c *ClockRealWall) Stop() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopLocked()
+}
+
+func (c *ClockRealWall) stopLocked() bool {
+	if c.cancel == nil {
+		return false
+	}
+	close(c.cancel)
+	c.cancel = nil
+	return true
+}
+
+// Reset implements Timer. The deadline is fixed in terms of the wall clock at the time of the call.
+func (c *ClockRealWall) Reset(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopLocked()
+	select {
+	case <-c.ch:
+	default:
+	}
+	cancel := make(chan struct{})
+	c.cancel = cancel
+	// Round(0) strips the monotonic clock reading, so that Sub compares wall clocks.
+	go c.run(c.Wall.Now().Round(0).Add(d), cancel)
+}
+
+func (c *ClockRealWall) run(deadline time.Time, cancel chan struct{}) {
+	for {
+		remaining := deadline.Sub(c.Wall.Now().Round(0))
+		if remaining <= 0 {
+			c.mu.Lock()
+			if c.cancel == cancel {
+				c.cancel = nil
+				select {
+				case c.ch <- c.Wall.Now():
+				default:
+				}
+			}
+			c.mu.Unlock()
+			return
+		}
+		if remaining > c.step {
+			remaining = c.step
+		}
+		t := time.NewTimer(remaining)
+		select {
+		case <-t.C:
+		case <-cancel:
+			t.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockRealWall(t *testing.T) {
+	require := require.New(t)
+
+	c := mockable.NewClockRealWall(time.Millisecond)
+	require.False(c.Stop())
+	require.Less(time.Since(c.Now()), time.Second)
+
+	c.Reset(5 * time.Millisecond)
+	start := time.Now()
+	fired := <-c.C()
+	require.GreaterOrEqual(time.Since(start), 5*time.Millisecond)
+	require.False(fired.IsZero())
+	require.False(c.Stop())
+
+	c.Reset(time.Hour)
+	require.True(c.Stop())
+	require.False(c.Stop())
+}
+
+func TestClockRealWall_wall_jump(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	wall := &mockable.NowerFake{}
+	wall.SetNow(now)
+
+	c := mockable.NewClockRealWall(time.Millisecond)
+	c.Wall = wall
+	c.Reset(time.Hour)
+
+	select {
+	case <-c.C():
+		t.Fatal("fired before the wall clock reaches the deadline")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// The machine wakes up an hour later.
+	wall.SetNow(now.Add(time.Hour))
+	select {
+	case fired := <-c.C():
+		require.Equal(now.Add(time.Hour), fired)
+	case <-time.After(time.Second):
+		t.Fatal("not fired after the wall clock jumped past the deadline")
+	}
+}
@@ -0,0 +1,114 @@
+package mockable
+
+import (
+	"sync"
+	"time"
+)
+
+// Watchdog calls a callback when it is not kicked within a timeout measured by the injected Timer.
+//
+// The Watchdog is armed when created and re-armed by every Kick.
+// After it expires, it stays disarmed until the next Kick.
+//
+// With ClockFake, a missing kick is simulated by calling Send.
+type Watchdog struct {
+	mu       sync.Mutex
+	timer    Timer
+	timeout  time.Duration
+	onExpire func()
+	armed    bool
+	stopped  bool
+	expired  int
+	// callback is the ID of the goroutine running onExpire, or 0.
+	callback uint64
+	stopCh   chan struct{}
+	done     chan struct{}
+}
+
+// NewWatchdog returns an armed Watchdog which calls onExpire if it is not kicked within timeout.
+// onExpire is called in a goroutine owned by the Watchdog.
+// timer is exclusively used by the returned Watchdog until Stop is called.
+func NewWatchdog(timer Timer, timeout time.Duration, onExpire func()) *Watchdog {
+	w := &Watchdog{
+		timer:    timer,
+		timeout:  timeout,
+		onExpire: onExpire,
+		armed:    true,
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	timer.Reset(timeout)
+	go w.run()
+	return w
+}
+
+func (w *Watchdog) run() {
+	defer close(w.done)
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-w.timer.C():
+			w.mu.Lock()
+			fire := w.armed
+			if fire {
+				w.armed = false
+				w.expired++
+				w.callback = goroutineID()
+			}
+			w.mu.Unlock()
+			if fire && w.onExpire != nil {
+				w.onExpire()
+			}
+			w.mu.Lock()
+			w.callback = 0
+			w.mu.Unlock()
+		}
+	}
+}
+
+// Kick re-arms the Watchdog, postponing its expiry by the timeout.
+// Kick after Stop is ignored.
+func (w *Watchdog) Kick() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return
+	}
+	w.armed = true
+	w.timer.Reset(w.timeout)
+}
+
+// Armed reports whether the Watchdog is waiting for a kick.
+func (w *Watchdog) Armed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.armed
+}
+
+// ExpiredCount returns how many times the Watchdog has expired.
+func (w *Watchdog) ExpiredCount() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.expired
+}
+
+// Stop disarms the Watchdog and releases the timer.
+// It waits for onExpire in progress to return,
+// unless called from onExpire itself, e.g. to shut down on expiry, in which case it returns immediately.
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+	w.stopped = true
+	w.armed = false
+	w.timer.Stop()
+	close(w.stopCh)
+	fromCallback := w.callback != 0 && w.callback == goroutineID()
+	w.mu.Unlock()
+	if !fromCallback {
+		<-w.done
+	}
+}
@@ -0,0 +1,74 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdog(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := mockable.NewClockFake(now)
+
+	alarm := make(chan time.Time, 1)
+	w := mockable.NewWatchdog(clock, 10*time.Second, func() { alarm <- clock.Now() })
+	defer w.Stop()
+	require.Equal(10*time.Second, <-clock.ResetCh)
+	require.True(w.Armed())
+
+	// Kicks keep postponing the expiry.
+	clock.SetNow(now.Add(5 * time.Second))
+	w.Kick()
+	require.Equal(10*time.Second, <-clock.ResetCh)
+
+	// A missing kick triggers the alarm at 10s after the last kick.
+	clock.Send()
+	require.Equal(now.Add(15*time.Second+1), <-alarm)
+	require.False(w.Armed())
+	require.Equal(1, w.ExpiredCount())
+
+	// Disarmed until the next kick.
+	clock.Send()
+	select {
+	case <-alarm:
+		t.Fatal("alarm while disarmed")
+	case <-time.After(time.Millisecond):
+	}
+
+	w.Kick()
+	clock.Send()
+	<-alarm
+	require.Equal(2, w.ExpiredCount())
+
+	w.Stop()
+	w.Kick()
+	require.False(w.Armed())
+}
+
+func TestWatchdog_Stop_from_onExpire(t *testing.T) {
+	require := require.New(t)
+
+	clock := mockable.NewClockFake(time.Now())
+	stopped := make(chan struct{})
+	var w *mockable.Watchdog
+	w = mockable.NewWatchdog(clock, 10*time.Second, func() {
+		// Shutting down on expiry must not wait for this very callback.
+		w.Stop()
+		close(stopped)
+	})
+	<-clock.ResetCh
+
+	clock.Send()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop from onExpire deadlocked")
+	}
+	w.Kick()
+	require.False(w.Armed())
+	w.Stop()
+}
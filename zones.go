@@ -0,0 +1,199 @@
+package mockable
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The Zones is a mockable interface of the time zone database.
+type Zones interface {
+	// LoadLocation returns the Location with the given name, like time.LoadLocation.
+	LoadLocation(name string) (*time.Location, error)
+}
+
+var _ Zones = ZonesReal{}
+
+// ZonesReal is an implementation of the Zones interface.
+// It only wraps time.LoadLocation.
+type ZonesReal struct{}
+
+func (ZonesReal) LoadLocation(name string) (*time.Location, error) {
+	return time.LoadLocation(name)
+}
+
+var _ Zones = (*ZonesFake)(nil)
+
+// ZonesFake is an implementation of the Zones interface serving registered zones,
+// independently of the tzdata available on the host.
+//
+// As time.LoadLocation does, "" and "UTC" load time.UTC and "Local" loads time.Local
+// unless "Local" is registered. Unknown names fail with the same error as time.LoadLocation.
+type ZonesFake struct {
+	mu    sync.Mutex
+	zones map[string]*time.Location
+}
+
+// NewZonesFake returns a ZonesFake serving locs, each registered under its String.
+func NewZonesFake(locs ...*time.Location) *ZonesFake {
+	z := &ZonesFake{zones: make(map[string]*time.Location)}
+	for _, loc := range locs {
+		z.Add(loc.String(), loc)
+	}
+	return z
+}
+
+// Add registers loc under name. A zone with the same name is replaced.
+func (z *ZonesFake) Add(name string, loc *time.Location) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.zones[name] = loc
+}
+
+// AddFixed registers a zone under name which always uses offset, as time.FixedZone.
+func (z *ZonesFake) AddFixed(name string, offset time.Duration) {
+	z.Add(name, time.FixedZone(name, int(offset/time.Second)))
+}
+
+// AddTZData registers a zone under name from TZif data, e.g. embedded from a tzdata file.
+func (z *ZonesFake) AddTZData(name string, data []byte) error {
+	loc, err := time.LoadLocationFromTZData(name, data)
+	if err != nil {
+		return err
+	}
+	z.Add(name, loc)
+	return nil
+}
+
+// Remove unregisters the zone with name.
+func (z *ZonesFake) Remove(name string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	delete(z.zones, name)
+}
+
+func (z *ZonesFake) LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "UTC" {
+		return time.UTC, nil
+	}
+	z.mu.Lock()
+	loc, ok := z.zones[name]
+	z.mu.Unlock()
+	if ok {
+		return loc, nil
+	}
+	if name == "Local" {
+		return time.Local, nil
+	}
+	if strings.Contains(name, "..") || name[0] == '/' || name[0] == '\\' {
+		return nil, errors.New("time: invalid location name")
+	}
+	return nil, errors.New("unknown time zone " + name)
+}
+
+// ZoneTransition is a change of the offset of a synthetic zone built by SyntheticLocation.
+type ZoneTransition struct {
+	// At is the instant the change takes effect. It is ignored for the initial state.
+	At time.Time
+	// Name is the abbreviation in effect, e.g. "PDT".
+	Name string
+	// Offset is the offset from UTC in effect. It is truncated to seconds.
+	Offset time.Duration
+	// DST reports whether daylight saving time is in effect.
+	DST bool
+}
+
+// SyntheticLocation builds a Location named name with arbitrary rules:
+// initial is in effect before the first transition, and each transition is in effect from its At
+// until the next one. transitions must be sorted by At.
+//
+// Use it to test code against zone rules which do not exist in the real world,
+// such as a transition at a chosen instant or an unusual offset.
+func SyntheticLocation(name string, initial ZoneTransition, transitions ...ZoneTransition) (*time.Location, error) {
+	kinds := make(map[ZoneTransition]bool)
+	for i, zt := range transitions {
+		if i > 0 && !transitions[i-1].At.Before(zt.At) {
+			return nil, fmt.Errorf("mockable: transitions are not sorted at %d", i)
+		}
+		zt.At = time.Time{}
+		kinds[zt] = true
+	}
+	if len(kinds) > 255 {
+		return nil, errors.New("mockable: too many distinct zone states")
+	}
+	return time.LoadLocationFromTZData(name, buildTZif(initial, transitions))
+}
+
+// buildTZif encodes zone rules in TZif version 2.
+// The version 1 section only has initial, which Go skips in favor of the 64-bit section.
+func buildTZif(initial ZoneTransition, transitions []ZoneTransition) []byte {
+	var (
+		chars   []byte
+		charIdx = make(map[string]int)
+	)
+	desig := func(name string) byte {
+		if i, ok := charIdx[name]; ok {
+			return byte(i)
+		}
+		charIdx[name] = len(chars)
+		chars = append(append(chars, name...), 0)
+		return byte(charIdx[name])
+	}
+	ttinfo := func(b *bytes.Buffer, zt ZoneTransition) {
+		_ = binary.Write(b, binary.BigEndian, int32(zt.Offset/time.Second))
+		var isDST byte
+		if zt.DST {
+			isDST = 1
+		}
+		b.Write([]byte{isDST, desig(zt.Name)})
+	}
+	header := func(b *bytes.Buffer, timecnt, typecnt, charcnt int) {
+		b.WriteString("TZif2")
+		b.Write(make([]byte, 15))
+		// isutcnt, isstdcnt, leapcnt, timecnt, typecnt, charcnt
+		for _, n := range []int{0, 0, 0, timecnt, typecnt, charcnt} {
+			_ = binary.Write(b, binary.BigEndian, uint32(n))
+		}
+	}
+
+	var v1 bytes.Buffer
+	ttinfo(&v1, initial)
+	v1Chars := chars
+	var out bytes.Buffer
+	header(&out, 0, 1, len(v1Chars))
+	out.Write(v1.Bytes())
+	out.Write(v1Chars)
+
+	// The initial type is at index 0 and never referenced by transitions,
+	// which makes Go use it for times before the first transition.
+	var (
+		types   bytes.Buffer
+		typeIdx = make(map[ZoneTransition]int)
+		idx     []byte
+	)
+	ttinfo(&types, initial)
+	for _, zt := range transitions {
+		zt.At = time.Time{}
+		i, ok := typeIdx[zt]
+		if !ok {
+			i = len(typeIdx) + 1
+			typeIdx[zt] = i
+			ttinfo(&types, zt)
+		}
+		idx = append(idx, byte(i))
+	}
+	header(&out, len(transitions), len(typeIdx)+1, len(chars))
+	for _, zt := range transitions {
+		_ = binary.Write(&out, binary.BigEndian, zt.At.Unix())
+	}
+	out.Write(idx)
+	out.Write(types.Bytes())
+	out.Write(chars)
+	// An empty footer: no rule extends past the last transition.
+	out.WriteString("\n\n")
+	return out.Bytes()
+}
@@ -0,0 +1,106 @@
+package mockable_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngicks/mockable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZonesReal(t *testing.T) {
+	require := require.New(t)
+
+	var z mockable.Zones = mockable.ZonesReal{}
+	loc, err := z.LoadLocation("UTC")
+	require.NoError(err)
+	require.Equal(time.UTC, loc)
+
+	_, err = z.LoadLocation("Nowhere/Atlantis")
+	require.Error(err)
+}
+
+func TestZonesFake(t *testing.T) {
+	require := require.New(t)
+
+	z := mockable.NewZonesFake(time.FixedZone("Test/Plus9", 9*60*60))
+	z.AddFixed("Test/Minus3", -3*time.Hour)
+
+	loc, err := z.LoadLocation("Test/Plus9")
+	require.NoError(err)
+	_, offset := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone()
+	require.Equal(9*60*60, offset)
+
+	loc, err = z.LoadLocation("Test/Minus3")
+	require.NoError(err)
+	_, offset = time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone()
+	require.Equal(-3*60*60, offset)
+
+	for _, name := range []string{"", "UTC"} {
+		loc, err = z.LoadLocation(name)
+		require.NoError(err)
+		require.Equal(time.UTC, loc)
+	}
+	loc, err = z.LoadLocation("Local")
+	require.NoError(err)
+	require.Equal(time.Local, loc)
+
+	// The same error as time.LoadLocation, which may succeed on the host though.
+	_, err = z.LoadLocation("Asia/Tokyo")
+	require.EqualError(err, "unknown time zone Asia/Tokyo")
+	_, err = z.LoadLocation("../etc/passwd")
+	require.EqualError(err, "time: invalid location name")
+
+	z.Remove("Test/Plus9")
+	_, err = z.LoadLocation("Test/Plus9")
+	require.Error(err)
+
+	require.Error(z.AddTZData("Test/Broken", []byte("not tzif")))
+}
+
+func TestSyntheticLocation(t *testing.T) {
+	require := require.New(t)
+
+	springForward := time.Date(2030, 3, 10, 10, 0, 0, 0, time.UTC)
+	fallBack := time.Date(2030, 11, 3, 9, 0, 0, 0, time.UTC)
+	std := mockable.ZoneTransition{Name: "XST", Offset: -8 * time.Hour}
+	loc, err := mockable.SyntheticLocation(
+		"Test/Synthetic",
+		std,
+		mockable.ZoneTransition{At: springForward, Name: "XDT", Offset: -7 * time.Hour, DST: true},
+		mockable.ZoneTransition{At: fallBack, Name: "XST", Offset: -8 * time.Hour},
+	)
+	require.NoError(err)
+	require.Equal("Test/Synthetic", loc.String())
+
+	zone := func(t time.Time) (string, int, bool) {
+		t = t.In(loc)
+		name, offset := t.Zone()
+		return name, offset, t.IsDST()
+	}
+	name, offset, dst := zone(springForward.Add(-time.Second))
+	require.Equal("XST", name)
+	require.Equal(-8*60*60, offset)
+	require.False(dst)
+
+	name, offset, dst = zone(springForward)
+	require.Equal("XDT", name)
+	require.Equal(-7*60*60, offset)
+	require.True(dst)
+
+	name, _, _ = zone(fallBack)
+	require.Equal("XST", name)
+	name, _, _ = zone(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal("XST", name)
+
+	z := mockable.NewZonesFake(loc)
+	got, err := z.LoadLocation("Test/Synthetic")
+	require.NoError(err)
+	require.Equal(loc, got)
+
+	_, err = mockable.SyntheticLocation("Test/Unsorted", std,
+		mockable.ZoneTransition{At: fallBack, Name: "XST", Offset: -8 * time.Hour},
+		mockable.ZoneTransition{At: springForward, Name: "XDT", Offset: -7 * time.Hour, DST: true},
+	)
+	require.Error(err)
+}